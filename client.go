@@ -39,6 +39,27 @@ type Client struct {
 	timeout    time.Duration
 	bufferSize int
 
+	// sdrFile is the path of SDR dump file, set by WithSDRFile.
+	// If set, SDR records are loaded from this file instead of the BMC.
+	sdrFile     string
+	sdrFileSDRs []*SDR
+
+	// sdrCache caches the SDR Repository, set by WithSDRCache.
+	sdrCache    SDRCache
+	sdrCacheKey string
+
+	// sdrReadSizes holds the number of bytes to read in one partial read of SDR records
+	// for each target and SDR repository type, see sdrReadSize.
+	sdrReadSizes map[sdrReadSizeKey]*uint8
+
+	// satelliteSDRs indicates whether to merge the SDRs of satellite controllers, set by WithSatelliteSDRs.
+	satelliteSDRs bool
+	// sdrSource is where the SDRs of the BMC are read from, detected by useDeviceSDRs.
+	sdrSource sdrSource
+
+	// target is the controller and LUN which requests are addressed to, see withTarget.
+	target target
+
 	l sync.Mutex
 }
 
@@ -48,6 +69,8 @@ func NewOpenClient() (*Client, error) {
 	return &Client{
 		Interface: "open",
 
+		sdrCache: NewSDRMemoryCache(),
+
 		openipmi: &openipmi{
 			myAddr:     myAddr,
 			targetAddr: myAddr,
@@ -63,6 +86,8 @@ func NewToolClient(path string) (*Client, error) {
 	return &Client{
 		Host:      path,
 		Interface: "tool",
+
+		sdrCache: NewSDRMemoryCache(),
 	}, nil
 }
 
@@ -86,6 +111,8 @@ func NewClient(host string, port int, user string, pass string) (*Client, error)
 		Password:  pass,
 		Interface: "",
 
+		sdrCache: NewSDRMemoryCache(),
+
 		v20:        true,
 		timeout:    time.Second * time.Duration(DefaultExchangeTimeoutSec),
 		bufferSize: DefaultBufferSize,
@@ -127,6 +154,13 @@ func (c *Client) WithUDPProxy(proxy proxy.Dialer) *Client {
 	return c
 }
 
+// WithIPPreference sets which IP address family is used if the host resolves
+// to both IPv4 and IPv6 addresses, only used for lan and lanplus interface.
+func (c *Client) WithIPPreference(ipPreference IPPreference) *Client {
+	c.udpClient.SetIPPreference(ipPreference)
+	return c
+}
+
 func (c *Client) WithTimeout(timeout time.Duration) *Client {
 	c.timeout = timeout
 	c.udpClient.timeout = timeout
@@ -139,6 +173,37 @@ func (c *Client) WithBufferSize(bufferSize int) *Client {
 	return c
 }
 
+// WithSDRFile makes the client load SDR records from the specified file
+// which is generated by DumpSDRs (or "ipmitool sdr dump"), instead of
+// walking the SDR Repository of BMC.
+func (c *Client) WithSDRFile(sdrFile string) *Client {
+	c.sdrFile = sdrFile
+	c.sdrFileSDRs = nil
+	return c
+}
+
+// WithSDRCache makes the client cache the SDR Repository in the specified cache,
+// see NewSDRMemoryCache and NewSDRFileCache. The cached SDRs are reused as long as
+// the timestamps reported by GetSDRRepoInfo are not changed.
+//
+// The clients created by NewClient, NewOpenClient and NewToolClient use a SDRMemoryCache
+// by default, pass nil to disable the cache.
+func (c *Client) WithSDRCache(cache SDRCache) *Client {
+	c.sdrCache = cache
+	return c
+}
+
+// WithSatelliteSDRs makes the client also enumerate the SDRs of satellite controllers
+// (found by Management Controller Device Locator records) through bridging, and merge
+// them with the SDRs of the BMC. The walk of SDRs fails if a satellite controller can not be read.
+//
+// The lan and lanplus interfaces bridge the requests by Send Message command,
+// the open interface lets the kernel driver bridge them.
+func (c *Client) WithSatelliteSDRs(enable bool) *Client {
+	c.satelliteSDRs = enable
+	return c
+}
+
 func (c *Client) SessionPrivilegeLevel() PrivilegeLevel {
 	return c.session.v20.maxPrivilegeLevel
 }
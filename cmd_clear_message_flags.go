@@ -61,3 +61,10 @@ func (c *Client) ClearMessageFlags() (response *ClearMessageFlagsResponse, err e
 	err = c.Exchange(request, response)
 	return
 }
+
+// ClearMessageFlagsFor clears the message flags set in the request.
+func (c *Client) ClearMessageFlagsFor(request *ClearMessageFlagsRequest) (response *ClearMessageFlagsResponse, err error) {
+	response = &ClearMessageFlagsResponse{}
+	err = c.Exchange(request, response)
+	return
+}
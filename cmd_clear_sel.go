@@ -1,6 +1,9 @@
 package ipmi
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // 31.9 Clear SEL Command
 type ClearSELRequest struct {
@@ -12,6 +15,11 @@ type ClearSELResponse struct {
 	ErasureProgressStatus uint8
 }
 
+const (
+	SELErasureInProgress uint8 = 0x00
+	SELErasureCompleted  uint8 = 0x01
+)
+
 func (req *ClearSELRequest) Pack() []byte {
 	var out = make([]byte, 6)
 	packUint16L(req.ReservationID, out, 0)
@@ -35,7 +43,7 @@ func (res *ClearSELResponse) Unpack(msg []byte) error {
 		return ErrUnpackedDataTooShort
 	}
 
-	res.ErasureProgressStatus, _, _ = unpackUint8(msg, 0)
+	res.ErasureProgressStatus = msg[0] & 0x0f
 	return nil
 }
 
@@ -57,3 +65,55 @@ func (c *Client) ClearSEL(reservationID uint16) (response *ClearSELResponse, err
 	err = c.Exchange(request, response)
 	return
 }
+
+// GetSELErasureStatus returns the erasure progress status of the previous ClearSEL.
+func (c *Client) GetSELErasureStatus(reservationID uint16) (response *ClearSELResponse, err error) {
+	request := &ClearSELRequest{
+		ReservationID:        reservationID,
+		GetErasureStatusFlag: true,
+	}
+	response = &ClearSELResponse{}
+	err = c.Exchange(request, response)
+	return
+}
+
+// ClearSELAndWait reserves the SEL, initiates the erasure, and polls the erasure status
+// until it is completed or the timeout elapses.
+func (c *Client) ClearSELAndWait(timeout time.Duration) error {
+	reserveRes, err := c.ReserveSEL()
+	if err != nil {
+		return fmt.Errorf("ReserveSEL failed, err: %s", err)
+	}
+	reservationID := reserveRes.ReservationID
+
+	clearRes, err := c.ClearSEL(reservationID)
+	if err != nil {
+		return fmt.Errorf("ClearSEL failed, err: %s", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	status := clearRes.ErasureProgressStatus
+	for status != SELErasureCompleted {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("SEL erasure not completed in %s", timeout)
+		}
+		time.Sleep(500 * time.Millisecond)
+
+		statusRes, err := c.GetSELErasureStatus(reservationID)
+		if err != nil {
+			// the reservation is canceled once the SEL is erased on some BMCs, reserve again.
+			if !isResponseErrorCC(err, uint8(CompletionCodeReservationCanceled)) {
+				return fmt.Errorf("GetSELErasureStatus failed, err: %s", err)
+			}
+			reserveRes, err := c.ReserveSEL()
+			if err != nil {
+				return fmt.Errorf("ReserveSEL failed, err: %s", err)
+			}
+			reservationID = reserveRes.ReservationID
+			continue
+		}
+		status = statusRes.ErasureProgressStatus
+	}
+
+	return nil
+}
@@ -49,16 +49,58 @@ func (res *GetDeviceSDRResponse) Format() string {
 }
 
 // This command returns general information about the collection of sensors in a Dynamic Sensor Device.
+//
+// Like GetSDR, it falls back to partial reads if the entire record can not be returned in one response.
 func (c *Client) GetDeviceSDR(recordID uint16) (response *GetDeviceSDRResponse, err error) {
-	request := &GetDeviceSDRRequest{
-		ReservationID: 0,
-		RecordID:      recordID,
-		ReadOffset:    0,
-		ReadBytes:     0xff,
+	readSize := c.sdrReadSize(true)
+	if *readSize == 0 {
+		request := &GetDeviceSDRRequest{
+			ReservationID: 0,
+			RecordID:      recordID,
+			ReadOffset:    0,
+			ReadBytes:     0xff,
+		}
+		response = &GetDeviceSDRResponse{}
+		err = c.Exchange(request, response)
+		if err == nil || !sdrReadLength2Big(err) {
+			return
+		}
+		c.Debugf("Get entire device SDR record failed, fallback to partial reads, err: %s\n", err)
+	}
+
+	reserve := func() (uint16, error) {
+		res, err := c.ReserveDeviceSDRRepo()
+		if err != nil {
+			return 0, fmt.Errorf("ReserveDeviceSDRRepo failed, err: %s", err)
+		}
+		return res.ReservationID, nil
+	}
+
+	read := func(reservationID uint16, recordID uint16, offset uint8, count uint8) (uint16, []byte, error) {
+		request := &GetDeviceSDRRequest{
+			ReservationID: reservationID,
+			RecordID:      recordID,
+			ReadOffset:    offset,
+			ReadBytes:     count,
+		}
+		response := &GetDeviceSDRResponse{}
+		if err := c.Exchange(request, response); err != nil {
+			if isResponseErrorCC(err, 0x80) {
+				return 0, nil, errSDRRecordChanged
+			}
+			return 0, nil, err
+		}
+		return response.NextRecordID, response.RecordData, nil
+	}
+
+	nextRecordID, recordData, err := c.readSDRPartially(recordID, readSize, reserve, read)
+	if err != nil {
+		return nil, err
 	}
-	response = &GetDeviceSDRResponse{}
-	err = c.Exchange(request, response)
-	return
+	return &GetDeviceSDRResponse{
+		NextRecordID: nextRecordID,
+		RecordData:   recordData,
+	}, nil
 }
 
 func (c *Client) GetDeviceSDRBySensorID(sensorNumber uint8) (*SDR, error) {
@@ -66,53 +108,106 @@ func (c *Client) GetDeviceSDRBySensorID(sensorNumber uint8) (*SDR, error) {
 		return nil, fmt.Errorf("not valid sensorNumber, %#0x is reserved", sensorNumber)
 	}
 
-	var recordID uint16 = 0
-	for {
-		res, err := c.GetDeviceSDR(recordID)
-		if err != nil {
-			return nil, fmt.Errorf("GetDeviceSDR for recordID (%#0x) failed, err: %s", recordID, err)
+	var found *SDR
+	err := c.walkDeviceSDRs(func(sdr *SDR) bool {
+		if uint8(sdr.SensorNumber()) == sensorNumber {
+			found = sdr
+			return true
 		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("not found SDR for sensor id (%#0x)", sensorNumber)
+	}
+	return found, nil
+}
 
-		sdr, err := ParseSDR(res.RecordData, res.NextRecordID)
-		if err != nil {
-			return nil, fmt.Errorf("ParseSDR for recordID (%#0x) failed, err: %s", recordID, err)
+// GetDeviceSDRs fetches the SDR records with the specified RecordTypes
+// by Device SDR commands across all LUNs having sensors.
+func (c *Client) GetDeviceSDRs(recordTypes ...SDRRecordType) ([]*SDR, error) {
+	var out = make([]*SDR, 0)
+	err := c.walkDeviceSDRs(func(sdr *SDR) bool {
+		if len(recordTypes) == 0 {
+			out = append(out, sdr)
+			return false
 		}
-		if uint8(sdr.SensorNumber()) == sensorNumber {
-			return sdr, nil
+		for _, v := range recordTypes {
+			if sdr.RecordHeader.RecordType == v {
+				out = append(out, sdr)
+				break
+			}
 		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-		recordID = res.NextRecordID
-		if recordID == 0xffff {
-			break
+// walkDeviceSDRs calls fn for each SDR record read by Device SDR commands
+// from all LUNs which have sensors, and stops the walk once fn returns true.
+//
+// The LUNs are reported by Get Device SDR Info, only LUN 0 is walked if
+// the command is not supported.
+func (c *Client) walkDeviceSDRs(fn func(sdr *SDR) bool) error {
+	luns := []uint8{0}
+	info, err := c.GetDeviceSDRInfo(false)
+	if err != nil {
+		c.Debug("GetDeviceSDRInfo failed, only walk device SDRs on LUN 0", err)
+	} else {
+		luns = luns[:0]
+		for lun, hasSensors := range []bool{info.LUN0HasSensors, info.LUN1HasSensors, info.LUN2HasSensors, info.LUN3HasSensors} {
+			if hasSensors {
+				luns = append(luns, uint8(lun))
+			}
 		}
 	}
 
-	return nil, fmt.Errorf("not found SDR for sensor id (%#0x)", sensorNumber)
+	// some devices return the same records regardless of the addressed LUN
+	seen := make(map[string]bool)
+	stopped := false
+	for _, lun := range luns {
+		err := c.withTarget(c.target.addr, c.target.channel, lun, func() error {
+			return c.walkDeviceSDRsOnLUN(func(sdr *SDR) bool {
+				if seen[string(sdr.Raw())] {
+					return false
+				}
+				seen[string(sdr.Raw())] = true
+				stopped = fn(sdr)
+				return stopped
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("walk device SDRs on LUN (%d) failed, err: %s", lun, err)
+		}
+		if stopped {
+			break
+		}
+	}
+	return nil
 }
 
-func (c *Client) GetDeviceSDRs(recordTypes ...SDRRecordType) ([]*SDR, error) {
-	var out = make([]*SDR, 0)
+// walkDeviceSDRsOnLUN calls fn for each SDR record read by Device SDR commands
+// from the currently addressed LUN, and stops the walk once fn returns true.
+func (c *Client) walkDeviceSDRsOnLUN(fn func(sdr *SDR) bool) error {
 	var recordID uint16 = 0
 	for {
 		res, err := c.GetDeviceSDR(recordID)
 		if err != nil {
-			return nil, fmt.Errorf("GetDeviceSDR for recordID (%#0x) failed, err: %s", recordID, err)
+			return fmt.Errorf("GetDeviceSDR for recordID (%#0x) failed, err: %s", recordID, err)
 		}
 
 		sdr, err := ParseSDR(res.RecordData, res.NextRecordID)
 		if err != nil {
-			return nil, fmt.Errorf("ParseSDR for recordID (%#0x) failed, err: %s", recordID, err)
+			return fmt.Errorf("ParseSDR for recordID (%#0x) failed, err: %s", recordID, err)
 		}
 
-		if len(recordTypes) == 0 {
-			out = append(out, sdr)
-		} else {
-			for _, v := range recordTypes {
-				if sdr.RecordHeader.RecordType == v {
-					out = append(out, sdr)
-					break
-				}
-			}
+		if fn(sdr) {
+			break
 		}
 
 		recordID = res.NextRecordID
@@ -120,5 +215,5 @@ func (c *Client) GetDeviceSDRs(recordTypes ...SDRRecordType) ([]*SDR, error) {
 			break
 		}
 	}
-	return out, nil
+	return nil
 }
@@ -71,61 +71,25 @@ func (c *Client) GetFRU(deviceID uint8, deviceName string) (*FRU, error) {
 		return nil, fmt.Errorf("ReadFRUData failed, err: %s", err)
 	}
 
-	fruHeader := &FRUCommonHeader{}
-	if err := fruHeader.Unpack(readFRURes.Data); err != nil {
-		return nil, fmt.Errorf("unpack fru data failed, err: %s", err)
-	}
-	if fruHeader.FormatVersion != FRUFormatVersion {
-		return nil, fmt.Errorf("unkown FRU header version %#02x", fruHeader.FormatVersion)
-	}
-	c.Debug("FRU Common Header", fruHeader)
-	c.Debugf("%s\n\n", fruHeader.String())
-	fru.CommonHeader = fruHeader
-
-	if fruHeader.ChassisOffset8B != 0 {
-		offset := uint16(fruHeader.ChassisOffset8B) * 8
-		c.Debugf("Get FRU Area Chassis, offset (%d)\n", offset)
-		fruChassis, err := c.GetFRUAreaChassis(deviceID, uint16(fruHeader.ChassisOffset8B)*8)
-		if err != nil {
-			return nil, fmt.Errorf("GetFRUAreaChassis failed, err: %s", err)
-		}
-
-		c.Debug("FRU Area Chassis", fruChassis)
-		fru.ChassisInfoArea = fruChassis
-	}
-
-	if fruHeader.BoardOffset8B != 0 {
-		offset := uint16(fruHeader.BoardOffset8B) * 8
-		c.Debugf("Get FRU Area Board, offset (%d)\n", offset)
-		fruBoard, err := c.GetFRUAreaBoard(deviceID, offset)
+	// read the rest of the FRU data and parse it as a whole, the same as ParseFRUData
+	data := readFRURes.Data
+	if uint16(len(data)) < fruAreaInfoRes.AreaSizeBytes {
+		rest, err := c.readFRUDataByLength(deviceID, uint16(len(data)), fruAreaInfoRes.AreaSizeBytes-uint16(len(data)))
 		if err != nil {
-			return nil, fmt.Errorf("GetFRUAreaBoard failed, err: %s", err)
+			return nil, fmt.Errorf("read FRU data failed, err: %s", err)
 		}
-		c.Debug("FRU Area Board", fruBoard)
-		fru.BoardInfoArea = fruBoard
+		data = append(data, rest...)
 	}
+	c.DebugBytes("FRU Data", data, 16)
 
-	if fruHeader.ProductOffset8B != 0 {
-		offset := uint16(fruHeader.ProductOffset8B) * 8
-		c.Debugf("Get FRU Area Product, offset (%d)\n", offset)
-		fruProduct, err := c.GetFRUAreaProduct(deviceID, offset)
-		if err != nil {
-			return nil, fmt.Errorf("GetFRUAreaProduct failed, err: %s", err)
-		}
-		c.Debug("FRU Area Product", fruProduct)
-		fru.ProductInfoArea = fruProduct
-	}
-
-	if fruHeader.MultiRecordsOffset8B != 0 {
-		offset := uint16(fruHeader.MultiRecordsOffset8B) * 8
-		c.Debugf("Get FRU Area Multi Records, offset (%d)\n", offset)
-		fruMultiRecords, err := c.GetFRUAreaMultiRecords(deviceID, offset)
-		if err != nil {
-			return nil, fmt.Errorf("GetFRUAreaMultiRecord failed, err: %s", err)
-		}
-		c.Debug("FRU Area MultiRecords", fruMultiRecords)
-		fru.MultiRecords = fruMultiRecords
+	parsed, err := ParseFRUData(data)
+	if err != nil {
+		return nil, fmt.Errorf("ParseFRUData failed, err: %s", err)
 	}
+	c.Debugf("%s\n\n", parsed.CommonHeader.String())
+	parsed.deviceID = fru.deviceID
+	parsed.deviceName = fru.deviceName
+	fru = parsed
 
 	c.Debug("FRU", fru)
 	return fru, nil
@@ -161,53 +125,135 @@ func (c *Client) GetFRUs() ([]*FRU, error) {
 	for _, sdr := range sdrs {
 		switch sdr.RecordHeader.RecordType {
 		case SDRRecordTypeFRUDeviceLocator:
-			if !sdr.FRUDeviceLocator.IsLogicalFRUDevice {
-				// only logical FRU Device can be accessed via FRU commands to mgmt controller
-				continue
-			}
-
-			deviceType := sdr.FRUDeviceLocator.DeviceType
-			deviceTypeModifier := sdr.FRUDeviceLocator.DeviceTypeModifier
-			deviceID := sdr.FRUDeviceLocator.FRUDeviceID_SlaveAddress
-			deviceName := string(sdr.FRUDeviceLocator.DeviceIDBytes)
+			locator := sdr.FRUDeviceLocator
+			deviceType := locator.DeviceType
+			deviceTypeModifier := locator.DeviceTypeModifier
+			deviceID := locator.FRUDeviceID_SlaveAddress
+			deviceName := string(locator.DeviceIDBytes)
 
 			if deviceType != 0x10 && (deviceType < 0x08 || deviceType > 0x0f || deviceTypeModifier != 0x02) {
 				// ignore
 				continue
 			}
 
-			if sdr.FRUDeviceLocator.DeviceAccessAddress == BMC_SA && deviceID == 0x00 {
-				continue
-			}
-
-			switch deviceTypeModifier {
-			case 0x00, 0x02:
-				fru, err := c.GetFRU(deviceID, deviceName)
+			if !locator.IsLogicalFRUDevice {
+				// non-intelligent FRU device (SEEPROM) on IPMB or private bus
+				fru, err := c.GetPhysicalFRU(locator)
 				if err != nil {
-					return nil, fmt.Errorf("GetFRU sdr device id (%#02x) failed, err: %s", deviceID, err)
+					// an unreadable SEEPROM should not fail the whole enumeration, it is reported as not present
+					c.Debugf("GetPhysicalFRU (%s) failed, err: %s\n", deviceName, err)
+					fru = newUnreadableFRU(locator.FRUDeviceID_SlaveAddress&0xfe, deviceName, err)
 				}
 				frus = append(frus, fru)
+				continue
+			}
 
-			case 0x01:
-				// *   0x01 = DIMM Memory ID
-				fruData, err := c.GetFRUData(deviceID)
-				if err != nil {
-					return nil, fmt.Errorf("GetFRUData failed, err: %s", err)
-				}
-				c.DebugBytes("FRU Data", fruData, 16)
-				// Todo, parse SPD
+			accessAddr := locator.DeviceAccessAddress & 0xfe
+			if accessAddr == BMC_SA && deviceID == 0x00 {
+				continue
+			}
 
-			default:
+			fru, err := c.getLogicalFRU(locator)
+			if err != nil {
+				return nil, err
+			}
+			if fru != nil {
+				frus = append(frus, fru)
 			}
 
 		case SDRRecordTypeManagementControllerDeviceLocator:
+			// issue FRU commands to the satellite controller for its FRU device #0
+			locator := sdr.MgmtControllerDeviceLocator
+			addr := locator.DeviceSlaveAddress & 0xfe
+			if addr == BMC_SA || !locator.DeviceCap_FRUInventoryDevice {
+				continue
+			}
 
+			deviceName := string(locator.DeviceIDBytes)
+			var fru *FRU
+			err := c.withTarget(addr, locator.ChannelNumber&0x0f, 0, func() error {
+				var err error
+				fru, err = c.GetFRU(0x00, deviceName)
+				return err
+			})
+			if err != nil {
+				// an unreachable satellite controller should not fail the whole enumeration, it is reported as not present
+				c.Debugf("GetFRU of satellite controller (%#02x) on channel (%d) failed, err: %s\n", addr, locator.ChannelNumber, err)
+				fru = newUnreadableFRU(0x00, deviceName, err)
+			}
+			frus = append(frus, fru)
 		}
 	}
 
 	return frus, nil
 }
 
+// getLogicalFRU reads the logical FRU device described by the FRU Device Locator record by FRU commands
+// to the LUN of the controller at the device access address. It returns nil FRU if the SPD of a DIMM can not be parsed.
+//
+// If the controller is not the BMC and it can not be reached by bridging, the FRU device is read from the BMC,
+// as many BMCs serve the FRU devices of the other controllers by themselves.
+func (c *Client) getLogicalFRU(locator *SDRFRUDeviceLocator) (*FRU, error) {
+	deviceID := locator.FRUDeviceID_SlaveAddress
+	deviceName := string(locator.DeviceIDBytes)
+	accessAddr := locator.DeviceAccessAddress & 0xfe
+
+	var fru *FRU
+	read := func() error {
+		switch locator.DeviceTypeModifier {
+		case 0x00, 0x02:
+			var err error
+			fru, err = c.GetFRU(deviceID, deviceName)
+			if err != nil {
+				return fmt.Errorf("GetFRU sdr device id (%#02x) failed, err: %s", deviceID, err)
+			}
+
+		case 0x01:
+			// *   0x01 = DIMM Memory ID
+			fruData, err := c.GetFRUData(deviceID)
+			if err != nil {
+				return fmt.Errorf("GetFRUData failed, err: %s", err)
+			}
+			c.DebugBytes("FRU Data", fruData, 16)
+
+			spd, err := ParseSPD(fruData)
+			if err != nil {
+				c.Debugf("ParseSPD sdr device id (%#02x) failed, err: %s\n", deviceID, err)
+				return nil
+			}
+			fru = &FRU{
+				deviceID:   deviceID,
+				deviceName: deviceName,
+				SPD:        spd,
+			}
+
+		default:
+		}
+		return nil
+	}
+
+	err := c.withTarget(accessAddr, locator.ChannelNumber, locator.AccessLUN, read)
+	if err != nil && (target{addr: accessAddr}).bridged() {
+		c.Debugf("Read FRU (%s) of controller (%#02x) failed, read it from the BMC, err: %s\n", deviceName, accessAddr, err)
+		fru = nil
+		err = read()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fru, nil
+}
+
+// newUnreadableFRU returns a not present FRU for the device which can not be read, the error is the reason.
+func newUnreadableFRU(deviceID uint8, deviceName string, err error) *FRU {
+	return &FRU{
+		deviceID:               deviceID,
+		deviceName:             deviceName,
+		deviceNotPresent:       true,
+		deviceNotPresentReason: fmt.Sprintf("Unreadable: %s", err),
+	}
+}
+
 func (c *Client) GetFRUAreaChassis(deviceID uint8, offset uint16) (*FRUChassisInfoArea, error) {
 	// read enough (2 bytes) to check the length field
 	res, err := c.ReadFRUData(deviceID, offset, 2)
@@ -317,3 +363,167 @@ func (c *Client) GetFRUAreaMultiRecords(deviceID uint8, offset uint16) ([]*FRUMu
 
 	return records, nil
 }
+
+// GetPhysicalFRU reads the FRU of the non-intelligent FRU device (SEEPROM) described by
+// the FRU Device Locator record. The SEEPROM is accessed with Master Write-Read commands,
+// through the controller at the device access address if it is on a private bus.
+func (c *Client) GetPhysicalFRU(locator *SDRFRUDeviceLocator) (*FRU, error) {
+	dev := &physicalFRUDevice{
+		slaveAddr: locator.FRUDeviceID_SlaveAddress & 0xfe,
+		// 24C32 and 24C64 take two bytes word address
+		wordAddr16: locator.DeviceType == 0x0e || locator.DeviceType == 0x0f,
+	}
+
+	accessAddr := locator.DeviceAccessAddress & 0xfe
+	if locator.Location() == FRULocation_IPMB {
+		dev.channel = locator.ChannelNumber
+	} else {
+		dev.busID = locator.PrivateBusID
+		dev.private = true
+	}
+
+	var fru *FRU
+	err := c.withTarget(accessAddr, locator.ChannelNumber, locator.AccessLUN, func() error {
+		size, err := c.physicalFRUSize(dev)
+		if err != nil {
+			return fmt.Errorf("get physical FRU size failed, err: %s", err)
+		}
+
+		data, err := c.readPhysicalFRUData(dev, 0, size)
+		if err != nil {
+			return fmt.Errorf("read physical FRU data failed, err: %s", err)
+		}
+		c.DebugBytes("Physical FRU Data", data, 16)
+
+		fru, err = ParseFRUData(data)
+		if err != nil {
+			return fmt.Errorf("ParseFRUData failed, err: %s", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fru.deviceID = dev.slaveAddr
+	fru.deviceName = string(locator.DeviceIDBytes)
+	return fru, nil
+}
+
+// physicalFRUDevice addresses a SEEPROM for Master Write-Read commands.
+type physicalFRUDevice struct {
+	channel    uint8
+	busID      uint8
+	private    bool
+	slaveAddr  uint8
+	wordAddr16 bool
+}
+
+// physicalFRUSize determines the size of the FRU data by the common header,
+// the lengths of the info areas and the MultiRecord headers.
+func (c *Client) physicalFRUSize(dev *physicalFRUDevice) (uint16, error) {
+	headerData, err := c.readPhysicalFRUData(dev, 0, uint16(FRUCommonHeaderSize))
+	if err != nil {
+		return 0, err
+	}
+	header := &FRUCommonHeader{}
+	if err := header.Unpack(headerData); err != nil {
+		return 0, err
+	}
+	if header.FormatVersion != FRUFormatVersion {
+		return 0, fmt.Errorf("unkown FRU header version %#02x", header.FormatVersion)
+	}
+
+	size := uint16(FRUCommonHeaderSize)
+	for _, offset8B := range []uint8{header.InternalOffset8B, header.ChassisOffset8B, header.BoardOffset8B, header.ProductOffset8B} {
+		if offset8B == 0 {
+			continue
+		}
+		offset := uint16(offset8B) * 8
+		// the internal use area has no length field, it is at least 8 bytes
+		end := offset + 8
+		if offset8B != header.InternalOffset8B {
+			areaHeader, err := c.readPhysicalFRUData(dev, offset, 2)
+			if err != nil {
+				return 0, err
+			}
+			end = offset + uint16(areaHeader[1])*8
+		}
+		if end > size {
+			size = end
+		}
+	}
+
+	if header.MultiRecordsOffset8B != 0 {
+		offset := uint16(header.MultiRecordsOffset8B) * 8
+		for {
+			recordHeader, err := c.readPhysicalFRUData(dev, offset, 5)
+			if err != nil {
+				return 0, err
+			}
+			offset += 5 + uint16(recordHeader[2])
+			if isBit7Set(recordHeader[1]) {
+				break
+			}
+		}
+		if offset > size {
+			size = offset
+		}
+	}
+
+	return size, nil
+}
+
+// readPhysicalFRUData reads length bytes from offset of the SEEPROM in loop,
+// the read count of each Master Write-Read is decreased if it is too big.
+func (c *Client) readPhysicalFRUData(dev *physicalFRUDevice, offset uint16, length uint16) ([]byte, error) {
+	var data []byte
+	var readCount uint16 = 32
+
+	for length > 0 {
+		count := readCount
+		if count > length {
+			count = length
+		}
+
+		request := &MasterWriteReadRequest{
+			ChannelNumber:    dev.channel,
+			BusID:            dev.busID,
+			BusTypeIsPrivate: dev.private,
+			SlaveAddress:     dev.slaveAddr,
+		}
+		if dev.wordAddr16 {
+			request.Data = []byte{uint8(offset >> 8), uint8(offset)}
+		} else {
+			// the address bits above 8 are held by the block select bits of the slave address,
+			// so a read must not cross the 256 bytes block.
+			request.SlaveAddress |= uint8(offset>>8) << 1 & 0x0e
+			request.Data = []byte{uint8(offset)}
+			if blockLeft := 256 - offset%256; count > blockLeft {
+				count = blockLeft
+			}
+		}
+		request.ReadCount = uint8(count)
+
+		res, err := c.MasterWriteRead(request)
+		if err != nil {
+			if resErr, ok := err.(*ResponseError); ok && readFRUDataLength2Big(resErr.CompletionCode()) && readCount > 1 {
+				readCount -= 1
+				continue
+			}
+			return nil, fmt.Errorf("MasterWriteRead at offset %d failed, err: %s", offset, err)
+		}
+		if len(res.Data) == 0 {
+			return nil, fmt.Errorf("MasterWriteRead at offset %d returned no data", offset)
+		}
+		if len(res.Data) > int(count) {
+			res.Data = res.Data[:count]
+		}
+
+		data = append(data, res.Data...)
+		offset += uint16(len(res.Data))
+		length -= uint16(len(res.Data))
+	}
+
+	return data, nil
+}
@@ -0,0 +1,446 @@
+package ipmi
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// fakeFRUDevice is a fake logical FRU device served by the fake BMC.
+type fakeFRUDevice struct {
+	data []byte
+	// the completion code replied to Get FRU Inventory Area Info
+	infoCC uint8
+	// the max count replied by each Read FRU Data
+	maxCount int
+
+	// the Write FRU Data requests are replied by "FRU device busy" (81h) busy times first
+	busy int
+	// the max count accepted by each Write FRU Data, larger counts fail with CC C7h
+	maxWrite int
+	// the write-protected offsets, writes to them fail with CC 80h
+	protected map[int]bool
+	// the offsets whose writes are reported written but not stored
+	stuck map[int]bool
+	// the offset and data of the Write FRU Data requests
+	writes [][]byte
+}
+
+func (d *fakeFRUDevice) handle(req *testBMCRequest) (uint8, []byte) {
+	switch req.Cmd {
+	case CommandGetFRUInventoryAreaInfo.ID:
+		if d.infoCC != 0x00 {
+			return d.infoCC, nil
+		}
+		out := make([]byte, 3)
+		packUint16L(uint16(len(d.data)), out, 0)
+		return 0x00, out
+
+	case CommandReadFRUData.ID:
+		offset, _, _ := unpackUint16L(req.Data, 1)
+		count := int(req.Data[3])
+		if d.maxCount > 0 && count > d.maxCount {
+			count = d.maxCount
+		}
+		if int(offset)+count > len(d.data) {
+			count = len(d.data) - int(offset)
+		}
+		return 0x00, append([]byte{uint8(count)}, d.data[offset:int(offset)+count]...)
+
+	case CommandWriteFRUData.ID:
+		offset, _, _ := unpackUint16L(req.Data, 1)
+		data := req.Data[3:]
+		d.writes = append(d.writes, append([]byte{uint8(offset)}, data...))
+		if d.busy > 0 {
+			d.busy--
+			return 0x81, nil
+		}
+		if d.maxWrite > 0 && len(data) > d.maxWrite {
+			return uint8(CompletionCodeRequestDataLengthInvalid), nil
+		}
+		for i := range data {
+			if d.protected[int(offset)+i] {
+				return 0x80, nil
+			}
+		}
+		for i, b := range data {
+			if !d.stuck[int(offset)+i] {
+				d.data[int(offset)+i] = b
+			}
+		}
+		return 0x00, []byte{uint8(len(data))}
+	}
+	return 0xc1, nil
+}
+
+func Test_GetFRU(t *testing.T) {
+	fru := &FRU{
+		InternalUseArea: &FRUInternalUseArea{
+			FormatVersion: FRUFormatVersion,
+			Data:          []byte{0x01, 0x02, 0x03},
+		},
+		BoardInfoArea: &FRUBoardInfoArea{
+			Manufacturer: []byte("ACME Inc."),
+			ProductName:  []byte("MB-1"),
+			SerialNumber: []byte("B0001"),
+			PartNumber:   []byte("MB-1-001"),
+			FRUFileID:    []byte{},
+		},
+		ProductInfoArea: &FRUProductInfoArea{
+			Manufacturer: []byte("ACME Inc."),
+			Name:         []byte("Server"),
+			PartModel:    []byte("S-1"),
+			Version:      []byte("1.0"),
+			SerialNumber: []byte("S0001"),
+			AssetTag:     []byte{},
+			FRUFileID:    []byte{},
+		},
+	}
+	data, err := fru.Pack()
+	if err != nil {
+		t.Fatalf("pack fru failed, err: %s", err)
+	}
+	expected, err := ParseFRUData(data)
+	if err != nil {
+		t.Fatalf("parse fru failed, err: %s", err)
+	}
+	expected.deviceID = 0x01
+	expected.deviceName = "test"
+
+	tests := []struct {
+		name     string
+		device   *fakeFRUDevice
+		expected *FRU
+	}{
+		{
+			name:     "read at once",
+			device:   &fakeFRUDevice{data: data},
+			expected: expected,
+		},
+		{
+			name:     "read in small chunks",
+			device:   &fakeFRUDevice{data: data, maxCount: 5},
+			expected: expected,
+		},
+		{
+			name:   "not present",
+			device: &fakeFRUDevice{infoCC: uint8(CompletionCodeRequestedDataNotPresent)},
+			expected: &FRU{
+				deviceID:               0x01,
+				deviceName:             "test",
+				deviceNotPresent:       true,
+				deviceNotPresentReason: "InventoryRecordNotExist",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		c := newTestLANClient(t, test.device.handle)
+		got, err := c.GetFRU(0x01, "test")
+		if err != nil {
+			t.Errorf("test %s: GetFRU failed, err: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("test %s not matched, got: %v, expected: %v", test.name, got, test.expected)
+		}
+	}
+}
+
+// fakeSEEPROM is a fake non-intelligent FRU device accessed by Master Write-Read.
+type fakeSEEPROM struct {
+	data       []byte
+	wordAddr16 bool
+	// the max read count, larger counts fail with CC CAh
+	maxCount uint8
+
+	// the bus byte, slave address, read count and the data of the succeeded requests
+	requests [][]byte
+}
+
+func (d *fakeSEEPROM) handle(req *testBMCRequest) (uint8, []byte) {
+	if req.Cmd != CommandMasterWriteRead.ID {
+		return 0xc1, nil
+	}
+	count := req.Data[2]
+	if d.maxCount != 0 && count > d.maxCount {
+		return uint8(CompletionCodeCannotReturnRequestedDataBytes), nil
+	}
+	d.requests = append(d.requests, append([]byte{}, req.Data...))
+
+	var offset int
+	if d.wordAddr16 {
+		offset = int(req.Data[3])<<8 | int(req.Data[4])
+	} else {
+		// the block select bits of the slave address
+		offset = int(req.Data[1]>>1&0x07)<<8 | int(req.Data[3])
+	}
+	end := offset + int(count)
+	if end > len(d.data) {
+		end = len(d.data)
+	}
+	return 0x00, d.data[offset:end]
+}
+
+func Test_readPhysicalFRUData(t *testing.T) {
+	data := make([]byte, 512)
+	for i := range data {
+		data[i] = uint8(i * 7)
+	}
+
+	tests := []struct {
+		name     string
+		dev      *physicalFRUDevice
+		seeprom  *fakeSEEPROM
+		offset   uint16
+		length   uint16
+		expected [][]byte
+	}{
+		{
+			name:    "ipmb",
+			dev:     &physicalFRUDevice{channel: 1, slaveAddr: 0xa0},
+			seeprom: &fakeSEEPROM{data: data},
+			offset:  0x10,
+			length:  8,
+			expected: [][]byte{
+				{0x10, 0xa0, 0x08, 0x10},
+			},
+		},
+		{
+			name:    "private bus",
+			dev:     &physicalFRUDevice{busID: 2, private: true, slaveAddr: 0xa0},
+			seeprom: &fakeSEEPROM{data: data},
+			offset:  0,
+			length:  40,
+			expected: [][]byte{
+				{0x05, 0xa0, 0x20, 0x00},
+				{0x05, 0xa0, 0x08, 0x20},
+			},
+		},
+		{
+			name:    "not cross the block",
+			dev:     &physicalFRUDevice{slaveAddr: 0xa0},
+			seeprom: &fakeSEEPROM{data: data},
+			offset:  250,
+			length:  12,
+			expected: [][]byte{
+				{0x00, 0xa0, 0x06, 0xfa},
+				{0x00, 0xa2, 0x06, 0x00},
+			},
+		},
+		{
+			name:    "word address",
+			dev:     &physicalFRUDevice{slaveAddr: 0xa0, wordAddr16: true},
+			seeprom: &fakeSEEPROM{data: data, wordAddr16: true},
+			offset:  250,
+			length:  12,
+			expected: [][]byte{
+				{0x00, 0xa0, 0x0c, 0x00, 0xfa},
+			},
+		},
+		{
+			name:    "read count decreased",
+			dev:     &physicalFRUDevice{slaveAddr: 0xa0},
+			seeprom: &fakeSEEPROM{data: data, maxCount: 20},
+			offset:  0,
+			length:  30,
+			expected: [][]byte{
+				{0x00, 0xa0, 0x14, 0x00},
+				{0x00, 0xa0, 0x0a, 0x14},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		c := newTestLANClient(t, test.seeprom.handle)
+		got, err := c.readPhysicalFRUData(test.dev, test.offset, test.length)
+		if err != nil {
+			t.Errorf("test %s: readPhysicalFRUData failed, err: %s", test.name, err)
+			continue
+		}
+		expected := data[test.offset : test.offset+test.length]
+		if !bytes.Equal(got, expected) {
+			t.Errorf("test %s not matched, got: % x, expected: % x", test.name, got, expected)
+		}
+		if !reflect.DeepEqual(test.seeprom.requests, test.expected) {
+			t.Errorf("test %s requests not matched, got: % x, expected: % x", test.name, test.seeprom.requests, test.expected)
+		}
+	}
+}
+
+func Test_physicalFRUSize(t *testing.T) {
+	fru := &FRU{
+		InternalUseArea: &FRUInternalUseArea{
+			FormatVersion: FRUFormatVersion,
+			Data:          []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07},
+		},
+		BoardInfoArea: &FRUBoardInfoArea{
+			Manufacturer: []byte("ACME Inc."),
+			ProductName:  []byte("MB-1"),
+			SerialNumber: []byte("B0001"),
+			PartNumber:   []byte("MB-1-001"),
+			FRUFileID:    []byte{},
+		},
+		MultiRecords: []*FRUMultiRecord{
+			NewFRUMultiRecord(0xc0, []byte{0x57, 0x01, 0x00, 0x01}),
+			NewFRUMultiRecord(0xc0, []byte{0x57, 0x01, 0x00, 0x02, 0x03}),
+		},
+	}
+	image, err := fru.Pack()
+	if err != nil {
+		t.Fatalf("pack fru failed, err: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected uint16
+	}{
+		{
+			name:     "multi records",
+			data:     image,
+			expected: uint16(len(image)),
+		},
+		{
+			// the unused bytes of the SEEPROM are not counted
+			name:     "with unused bytes",
+			data:     append(append([]byte{}, image...), make([]byte, 64)...),
+			expected: uint16(len(image)),
+		},
+	}
+
+	for _, test := range tests {
+		c := newTestLANClient(t, (&fakeSEEPROM{data: test.data}).handle)
+		got, err := c.physicalFRUSize(&physicalFRUDevice{slaveAddr: 0xa0})
+		if err != nil {
+			t.Errorf("test %s: physicalFRUSize failed, err: %s", test.name, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("test %s not matched, got: %d, expected: %d", test.name, got, test.expected)
+		}
+	}
+}
+
+func Test_GetPhysicalFRU(t *testing.T) {
+	fru := &FRU{
+		ProductInfoArea: &FRUProductInfoArea{
+			Manufacturer: []byte("ACME Inc."),
+			Name:         []byte("PSU"),
+			SerialNumber: []byte("P0001"),
+			AssetTag:     []byte{},
+			FRUFileID:    []byte{},
+		},
+	}
+	image, err := fru.Pack()
+	if err != nil {
+		t.Fatalf("pack fru failed, err: %s", err)
+	}
+	expected, err := ParseFRUData(image)
+	if err != nil {
+		t.Fatalf("parse fru failed, err: %s", err)
+	}
+	expected.deviceID = 0xa6
+	expected.deviceName = "PSU1"
+
+	// on the private bus 3 behind the BMC, with 16-bit word address
+	seeprom := &fakeSEEPROM{data: append(image, make([]byte, 32)...), wordAddr16: true}
+	c := newTestLANClient(t, seeprom.handle)
+	got, err := c.GetPhysicalFRU(&SDRFRUDeviceLocator{
+		DeviceAccessAddress:      BMC_SA,
+		FRUDeviceID_SlaveAddress: 0xa7,
+		PrivateBusID:             3,
+		DeviceType:               0x0f,
+		DeviceIDBytes:            []byte("PSU1"),
+	})
+	if err != nil {
+		t.Fatalf("GetPhysicalFRU failed, err: %s", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("fru not matched, got: %v, expected: %v", got, expected)
+	}
+	for _, request := range seeprom.requests {
+		if request[0] != 0x07 || request[1] != 0xa6 || len(request) != 5 {
+			t.Errorf("request not matched, got: % x, expected: bus 07, slave address a6 and 2 bytes word address", request)
+		}
+	}
+}
+
+func Test_getLogicalFRU(t *testing.T) {
+	fru := &FRU{
+		BoardInfoArea: &FRUBoardInfoArea{
+			Manufacturer: []byte("ACME Inc."),
+			ProductName:  []byte("MB-1"),
+			SerialNumber: []byte("B0001"),
+			PartNumber:   []byte("MB-1-001"),
+			FRUFileID:    []byte{},
+		},
+	}
+	image, err := fru.Pack()
+	if err != nil {
+		t.Fatalf("pack fru failed, err: %s", err)
+	}
+
+	tests := []struct {
+		name       string
+		accessAddr uint8
+		device     *fakeFRUDevice
+
+		expectedBridged int
+		expectedErr     bool
+	}{
+		{
+			name:       "bmc",
+			accessAddr: BMC_SA,
+			device:     &fakeFRUDevice{data: image},
+		},
+		{
+			name:            "not bridged, read from bmc",
+			accessAddr:      0x40,
+			device:          &fakeFRUDevice{data: image},
+			expectedBridged: 1,
+		},
+		{
+			name:        "failed on bmc",
+			accessAddr:  BMC_SA,
+			device:      &fakeFRUDevice{infoCC: 0xff},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		bridged := 0
+		c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+			if req.Cmd == CommandSendMessage.ID && req.NetFn == NetFnAppRequest {
+				// the BMC does not bridge the requests
+				bridged++
+				return 0xd4, nil
+			}
+			return test.device.handle(req)
+		})
+
+		got, err := c.getLogicalFRU(&SDRFRUDeviceLocator{
+			DeviceAccessAddress:      test.accessAddr,
+			FRUDeviceID_SlaveAddress: 0x02,
+			IsLogicalFRUDevice:       true,
+			DeviceType:               0x10,
+			DeviceIDBytes:            []byte("MB"),
+		})
+		if bridged != test.expectedBridged {
+			t.Errorf("test %s bridged not matched, got: %d, expected: %d", test.name, bridged, test.expectedBridged)
+		}
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("test %s expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %s: getLogicalFRU failed, err: %s", test.name, err)
+			continue
+		}
+		if got == nil || string(got.BoardInfoArea.SerialNumber) != "B0001" || got.DeviceName() != "MB" {
+			t.Errorf("test %s not matched, got: %v", test.name, got)
+		}
+	}
+}
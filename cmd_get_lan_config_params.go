@@ -1,6 +1,7 @@
 package ipmi
 
 import (
+	"errors"
 	"fmt"
 	"net"
 )
@@ -60,43 +61,153 @@ func (c *Client) GetLanConfigParams(channelNumber uint8, paramSelector LanParamS
 	return
 }
 
+// GetLanConfigParamsFor gets the LAN parameter by the set selector and block selector,
+// which are used by the parameters having multiple sets, like the alert destinations.
+func (c *Client) GetLanConfigParamsFor(channelNumber uint8, paramSelector LanParamSelector, setSelector uint8, blockSelector uint8) (response *GetLanConfigParamsResponse, err error) {
+	request := &GetLanConfigParamsRequest{
+		ChannelNumber: channelNumber,
+		ParamSelector: paramSelector,
+		SetSelector:   setSelector,
+		BlockSelector: blockSelector,
+	}
+	response = &GetLanConfigParamsResponse{}
+	err = c.Exchange(request, response)
+	return
+}
+
+// GetLanConfigFor returns the LanConfig with only the specified param (of the set selector) filled.
+func (c *Client) GetLanConfigFor(channelNumber uint8, paramSelector LanParamSelector, setSelector uint8) (*LanConfig, error) {
+	res, err := c.GetLanConfigParamsFor(channelNumber, paramSelector, setSelector, 0)
+	if err != nil {
+		return nil, fmt.Errorf("get lan config param (%s) failed, err: %s", paramSelector, err)
+	}
+
+	lanConfig := &LanConfig{}
+	if err := parseLanConfig(lanConfig, paramSelector, res.ConfigData); err != nil {
+		return nil, fmt.Errorf("get lan config param (%s) failed, err: %s", paramSelector, err)
+	}
+	return lanConfig, nil
+}
+
 func (c *Client) GetLanConfig(channelNumber uint8) (*LanConfig, error) {
 	lanConfig := &LanConfig{}
 
-	for _, lanParam := range LanParams {
-		paramSelector := LanParamSelector(lanParam.Selector)
+	fetched := make(map[LanParamSelector]bool)
+	var fetch func(paramSelector LanParamSelector) error
+	fetch = func(paramSelector LanParamSelector) error {
+		if fetched[paramSelector] {
+			return nil
+		}
+		fetched[paramSelector] = true
 
-		res, err := c.GetLanConfigParams(channelNumber, paramSelector)
-		if err != nil {
-			resErr, ok := err.(*ResponseError)
-			if !ok {
-				return nil, fmt.Errorf("not ResponseError")
+		if paramSelector.isIP6() && paramSelector != LanParam_IP6Support {
+			if err := fetch(LanParam_IP6Support); err != nil {
+				return err
+			}
+			if !lanConfig.ip6Supported() {
+				// skip all other IPv6 parameters
+				return nil
 			}
+		}
+		// the numbers of sets are got from other params
+		if prerequisite, ok := lanParamSetsPrerequisites[paramSelector]; ok {
+			if err := fetch(prerequisite); err != nil {
+				return err
+			}
+		}
 
-			cc := resErr.CompletionCode()
-			ccString := StrCC(res, uint8(cc))
+		for _, setSelector := range lanConfig.setSelectors(paramSelector) {
+			res, err := c.GetLanConfigParamsFor(channelNumber, paramSelector, setSelector, 0)
+			if err != nil {
+				var resErr *ResponseError
+				if !errors.As(err, &resErr) {
+					return fmt.Errorf("get lan config param (%s) failed, err: %s", paramSelector, err)
+				}
+
+				cc := resErr.CompletionCode()
+				ccString := StrCC(res, uint8(cc))
+
+				switch uint8(cc) {
+				case 0x80,
+					uint8(CompletionCodeParameterOutOfRange),
+					uint8(CompletionCodeRequestDataFieldInvalid):
+					c.Debugf("paramSelector (%#02x) %s, cc: (%#02x) %s\n", uint8(paramSelector), paramSelector, uint8(cc), ccString)
+					continue
+				default:
+					// other completion codes are treated as error.
+					// including 0x00 which means cc is successful, but other part failed
+					return fmt.Errorf("get lan config param (%s) failed, err: %s", paramSelector, err)
+				}
+			}
 
-			switch uint8(cc) {
-			case 0x80,
-				uint8(CompletionCodeParameterOutOfRange),
-				uint8(CompletionCodeRequestDataFieldInvalid):
-				c.Debugf("paramSelector (%#02x) %s, cc: (%#02x) %s\n", uint8(paramSelector), paramSelector, uint8(cc), ccString)
-				continue
-			default:
-				// other completion codes are treated as error.
-				// including 0x00 which means cc is successful, but other part failed
-				return nil, fmt.Errorf("get lan config param (%s) failed, err: %s", paramSelector, err)
+			if err := parseLanConfig(lanConfig, paramSelector, res.ConfigData); err != nil {
+				return fmt.Errorf("get lan config param (%s) failed, err: %s", paramSelector, err)
 			}
 		}
+		return nil
+	}
 
-		if err := parseLanConfig(lanConfig, paramSelector, res.ConfigData); err != nil {
-			return nil, fmt.Errorf("get lan config param (%s) failed, err: %s", paramSelector, err)
+	for _, lanParam := range LanParams {
+		if err := fetch(LanParamSelector(lanParam.Selector)); err != nil {
+			return nil, err
 		}
 	}
 
 	return lanConfig, nil
 }
 
+// lanParamSetsPrerequisites maps the params having multiple sets to the param
+// which holds the number of sets, see setSelectors.
+var lanParamSetsPrerequisites = map[LanParamSelector]LanParamSelector{
+	LanParam_IP6StaticAddr:                LanParam_IP6Status,
+	LanParam_IP6DynamicAddr:               LanParam_IP6Status,
+	LanParam_IP6DynamicRouterIP:           LanParam_IP6DynamicRouterSetsNumber,
+	LanParam_IP6DynamicRouterMAC:          LanParam_IP6DynamicRouterSetsNumber,
+	LanParam_IP6DynamicRouterPrefixLength: LanParam_IP6DynamicRouterSetsNumber,
+	LanParam_IP6DynamicRouterPrefixValue:  LanParam_IP6DynamicRouterSetsNumber,
+}
+
+// setSelectors returns the set selectors to get for the param.
+// The numbers of sets of IPv6 addresses and dynamic routers are
+// determined by the previously got params.
+func (lanConfig *LanConfig) setSelectors(paramSelector LanParamSelector) []uint8 {
+	var count uint8
+	switch paramSelector {
+	case LanParam_IP6StaticAddr:
+		count = lanConfig.IP6Status.StaticAddressMax
+	case LanParam_IP6DynamicAddr:
+		count = lanConfig.IP6Status.DynamicAddressMax
+	case LanParam_IP6DynamicRouterIP,
+		LanParam_IP6DynamicRouterMAC,
+		LanParam_IP6DynamicRouterPrefixLength,
+		LanParam_IP6DynamicRouterPrefixValue:
+		count = lanConfig.IP6DynamicRouterSetsNumber
+	default:
+		return []uint8{0}
+	}
+
+	out := make([]uint8, count)
+	for i := range out {
+		out[i] = uint8(i)
+	}
+	return out
+}
+
+func (lanConfig *LanConfig) ip6Supported() bool {
+	return lanConfig.IP6Support.CanUseIP6Only || lanConfig.IP6Support.CanUseBothIP4AndIP6
+}
+
+// ip6DynamicRouter returns the dynamic router of the set selector, it is appended if not exists.
+func (lanConfig *LanConfig) ip6DynamicRouter(setSelector uint8) *IP6Router {
+	for i := range lanConfig.IP6DynamicRouters {
+		if lanConfig.IP6DynamicRouters[i].SetSelector == setSelector {
+			return &lanConfig.IP6DynamicRouters[i]
+		}
+	}
+	lanConfig.IP6DynamicRouters = append(lanConfig.IP6DynamicRouters, IP6Router{SetSelector: setSelector})
+	return &lanConfig.IP6DynamicRouters[len(lanConfig.IP6DynamicRouters)-1]
+}
+
 func parseLanConfig(lanConfig *LanConfig, paramSelector LanParamSelector, paramData []byte) error {
 	var lanParam LanParam
 	for _, v := range LanParams {
@@ -222,12 +333,18 @@ func parseLanConfig(lanConfig *LanConfig, paramSelector LanParamSelector, paramD
 
 	case LanParam_AlertDestinationAddress:
 		lanConfig.AlertDestinationAddress = AlertDestinationAddress{
-			SetSelector:         paramData[0],
-			AddressFormat:       (paramData[1] & 0xf0) >> 4,
-			IP4UseBackupGateway: isBit0Set(paramData[2]),
-			IP4IP:               net.IP(paramData[3:7]),
-			IP4MAC:              net.HardwareAddr(paramData[7:12]),
-			IP6IP:               net.IP(paramData[2:18]),
+			SetSelector:   paramData[0],
+			AddressFormat: (paramData[1] & 0xf0) >> 4,
+		}
+		if lanConfig.AlertDestinationAddress.AddressFormat == 0x01 {
+			if len(paramData) < 18 {
+				return fmt.Errorf("the data for param (%s) is too short, input (%d), required (%d)", paramSelector, len(paramData), 18)
+			}
+			lanConfig.AlertDestinationAddress.IP6IP = net.IP(paramData[2:18])
+		} else {
+			lanConfig.AlertDestinationAddress.IP4UseBackupGateway = isBit0Set(paramData[2])
+			lanConfig.AlertDestinationAddress.IP4IP = net.IP(paramData[3:7])
+			lanConfig.AlertDestinationAddress.IP4MAC = net.HardwareAddr(paramData[7:13])
 		}
 
 	case LanParam_VLANID:
@@ -295,7 +412,103 @@ func parseLanConfig(lanConfig *LanConfig, paramSelector LanParamSelector, paramD
 			CanUseIP6Only:              isBit0Set(paramData[0]),
 		}
 
-		// Todo IP6 params parse
+	case LanParam_IP6Enables:
+		lanConfig.IP6Enables = IP6Enables(paramData[0])
+
+	case LanParam_IP6StaticTrafficClass:
+		lanConfig.IP6StaticTrafficClass = paramData[0]
+
+	case LanParam_IP6StaticHopLimit:
+		lanConfig.IP6StaticHopLimit = paramData[0]
+
+	case LanParam_IP6FlowLabel:
+		// 20-bit flow label, MS-byte first
+		lanConfig.IP6FlowLabel = uint32(paramData[0]&0x0f)<<16 | uint32(paramData[1])<<8 | uint32(paramData[2])
+
+	case LanParam_IP6Status:
+		lanConfig.IP6Status = IP6Status{
+			StaticAddressMax:  paramData[0],
+			DynamicAddressMax: paramData[1],
+			SupportDHCP6:      isBit1Set(paramData[2]),
+			SupportSLAAC:      isBit0Set(paramData[2]),
+		}
+
+	case LanParam_IP6StaticAddr:
+		addr := IP6Address{}
+		if err := addr.Unpack(paramData); err != nil {
+			return fmt.Errorf("unpack param (%s) failed, err: %s", paramSelector, err)
+		}
+		lanConfig.IP6StaticAddresses = append(lanConfig.IP6StaticAddresses, addr)
+
+	case LanParam_IP6DHCP6StaticDUIDLength:
+		lanConfig.IP6DHCP6StaticDUIDStorageLength = paramData[0]
+
+	case LanParam_IP6DynamicAddr:
+		addr := IP6Address{}
+		if err := addr.Unpack(paramData); err != nil {
+			return fmt.Errorf("unpack param (%s) failed, err: %s", paramSelector, err)
+		}
+		lanConfig.IP6DynamicAddresses = append(lanConfig.IP6DynamicAddresses, addr)
+
+	case LanParam_IP6DHCP6DynamicDUIDLenth:
+		lanConfig.IP6DHCP6DynamicDUIDStorageLength = paramData[0]
+
+	case LanParam_IP6DHCP6TimingConfigSupport:
+		lanConfig.IP6DHCP6TimingConfigSupport = IP6TimingConfigSupport(paramData[0])
+
+	case LanParam_IP6RouterAddressConfigControl:
+		lanConfig.IP6RouterConfig = IP6RouterConfig{
+			EnableDynamic: isBit1Set(paramData[0]),
+			EnableStatic:  isBit0Set(paramData[0]),
+		}
+
+	case LanParam_IP6StaticRouter1IP:
+		lanConfig.IP6StaticRouter1.IP = net.IP(paramData[0:16])
+
+	case LanParam_IP6StaticRouter1MAC:
+		lanConfig.IP6StaticRouter1.MAC = net.HardwareAddr(paramData[0:6])
+
+	case LanParam_IP6StaticRouter1PrefixLength:
+		lanConfig.IP6StaticRouter1.PrefixLength = paramData[0]
+
+	case LanParam_IP6StaticRouter1PrefixValue:
+		lanConfig.IP6StaticRouter1.PrefixValue = net.IP(paramData[0:16])
+
+	case LanParam_IP6StaticRouter2IP:
+		lanConfig.IP6StaticRouter2.IP = net.IP(paramData[0:16])
+
+	case LanParam_IP6StaticRouter2MAC:
+		lanConfig.IP6StaticRouter2.MAC = net.HardwareAddr(paramData[0:6])
+
+	case LanParam_IP6StaticRouter2PrefixLength:
+		lanConfig.IP6StaticRouter2.PrefixLength = paramData[0]
+
+	case LanParam_IP6StaticRouter2PrefixValue:
+		lanConfig.IP6StaticRouter2.PrefixValue = net.IP(paramData[0:16])
+
+	case LanParam_IP6DynamicRouterSetsNumber:
+		lanConfig.IP6DynamicRouterSetsNumber = paramData[0]
+
+	case LanParam_IP6DynamicRouterIP:
+		lanConfig.ip6DynamicRouter(paramData[0]).IP = net.IP(paramData[1:17])
+
+	case LanParam_IP6DynamicRouterMAC:
+		lanConfig.ip6DynamicRouter(paramData[0]).MAC = net.HardwareAddr(paramData[1:7])
+
+	case LanParam_IP6DynamicRouterPrefixLength:
+		lanConfig.ip6DynamicRouter(paramData[0]).PrefixLength = paramData[1]
+
+	case LanParam_IP6DynamicRouterPrefixValue:
+		lanConfig.ip6DynamicRouter(paramData[0]).PrefixValue = net.IP(paramData[1:17])
+
+	case LanParam_IP6DynamicRouterReceivedHopLimit:
+		lanConfig.IP6DynamicRouterReceivedHopLimit = paramData[0]
+
+	case LanParam_IP6NDSLAACTimingConfigSupport:
+		lanConfig.IP6NDSLAACTimingConfigSupport = IP6TimingConfigSupport(paramData[0])
+
+	case LanParam_IP6NDSLAACTiming:
+		lanConfig.IP6NDSLAACTimingConfig = paramData
 
 	}
 
@@ -0,0 +1,316 @@
+package ipmi
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func Test_parseLanConfig_IP6(t *testing.T) {
+	ip6 := net.ParseIP("2001:db8::1")
+	prefix := net.ParseIP("2001:db8::")
+	mac := net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+
+	tests := []struct {
+		name          string
+		paramSelector LanParamSelector
+		data          []byte
+		expected      *LanConfig
+	}{
+		{
+			name:          "support",
+			paramSelector: LanParam_IP6Support,
+			data:          []byte{0x06},
+			expected: &LanConfig{
+				IP6Support: IP6Support{SupportIP6AlertDestination: true, CanUseBothIP4AndIP6: true},
+			},
+		},
+		{
+			name:          "enables",
+			paramSelector: LanParam_IP6Enables,
+			data:          []byte{0x02},
+			expected:      &LanConfig{IP6Enables: IP6EnablesIP4AndIP6},
+		},
+		{
+			name:          "flow label",
+			paramSelector: LanParam_IP6FlowLabel,
+			data:          []byte{0xf1, 0x23, 0x45},
+			expected:      &LanConfig{IP6FlowLabel: 0x12345},
+		},
+		{
+			name:          "status",
+			paramSelector: LanParam_IP6Status,
+			data:          []byte{0x02, 0x04, 0x03},
+			expected: &LanConfig{
+				IP6Status: IP6Status{StaticAddressMax: 2, DynamicAddressMax: 4, SupportDHCP6: true, SupportSLAAC: true},
+			},
+		},
+		{
+			name:          "static address",
+			paramSelector: LanParam_IP6StaticAddr,
+			data: append(append([]byte{0x01, 0x80}, ip6...),
+				0x40, 0x00),
+			expected: &LanConfig{
+				IP6StaticAddresses: []IP6Address{
+					{SetSelector: 1, Enabled: true, Source: IP6AddressSourceStatic, Address: ip6, PrefixLength: 64, Status: IP6AddressStatusActive},
+				},
+			},
+		},
+		{
+			name:          "dynamic address",
+			paramSelector: LanParam_IP6DynamicAddr,
+			data: append(append([]byte{0x00, 0x02}, ip6...),
+				0x40, 0x02),
+			expected: &LanConfig{
+				IP6DynamicAddresses: []IP6Address{
+					{SetSelector: 0, Source: IP6AddressSourceDHCP6, Address: ip6, PrefixLength: 64, Status: IP6AddressStatusPending},
+				},
+			},
+		},
+		{
+			name:          "router address config control",
+			paramSelector: LanParam_IP6RouterAddressConfigControl,
+			data:          []byte{0x02},
+			expected:      &LanConfig{IP6RouterConfig: IP6RouterConfig{EnableDynamic: true}},
+		},
+		{
+			name:          "static router 1 ip",
+			paramSelector: LanParam_IP6StaticRouter1IP,
+			data:          ip6,
+			expected:      &LanConfig{IP6StaticRouter1: IP6Router{IP: ip6}},
+		},
+		{
+			name:          "static router 2 mac",
+			paramSelector: LanParam_IP6StaticRouter2MAC,
+			data:          mac,
+			expected:      &LanConfig{IP6StaticRouter2: IP6Router{MAC: mac}},
+		},
+		{
+			name:          "dynamic router prefix value",
+			paramSelector: LanParam_IP6DynamicRouterPrefixValue,
+			data:          append([]byte{0x01}, prefix...),
+			expected: &LanConfig{
+				IP6DynamicRouters: []IP6Router{{SetSelector: 1, PrefixValue: prefix}},
+			},
+		},
+		{
+			name:          "dynamic router prefix length",
+			paramSelector: LanParam_IP6DynamicRouterPrefixLength,
+			data:          []byte{0x00, 0x30},
+			expected: &LanConfig{
+				IP6DynamicRouters: []IP6Router{{SetSelector: 0, PrefixLength: 48}},
+			},
+		},
+		{
+			name:          "nd slaac timing config support",
+			paramSelector: LanParam_IP6NDSLAACTimingConfigSupport,
+			data:          []byte{0x01},
+			expected:      &LanConfig{IP6NDSLAACTimingConfigSupport: 0x01},
+		},
+	}
+
+	for _, test := range tests {
+		lanConfig := &LanConfig{}
+		if err := parseLanConfig(lanConfig, test.paramSelector, test.data); err != nil {
+			t.Errorf("test %s: parseLanConfig failed, err: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(lanConfig, test.expected) {
+			t.Errorf("test %s not matched, got: %+v, expected: %+v", test.name, lanConfig, test.expected)
+		}
+	}
+}
+
+func Test_parseLanConfig_IP6TooShort(t *testing.T) {
+	if err := parseLanConfig(&LanConfig{}, LanParam_IP6StaticAddr, make([]byte, 19)); err == nil {
+		t.Error("parse short ipv6 static address should fail")
+	}
+}
+
+func Test_IP6Address_Pack(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  *IP6Address
+		expected []byte
+	}{
+		{
+			name: "enabled",
+			address: &IP6Address{
+				SetSelector:  1,
+				Enabled:      true,
+				Source:       IP6AddressSourceStatic,
+				Address:      net.ParseIP("2001:db8::1"),
+				PrefixLength: 64,
+			},
+			expected: []byte{
+				0x01, 0x80,
+				0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+				0x40,
+			},
+		},
+		{
+			name: "disabled",
+			address: &IP6Address{
+				SetSelector:  0,
+				Address:      net.ParseIP("fe80::2"),
+				PrefixLength: 128,
+			},
+			expected: []byte{
+				0x00, 0x00,
+				0xfe, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02,
+				0x80,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := test.address.Pack()
+		if !bytes.Equal(got, test.expected) {
+			t.Errorf("test %s not matched, got: % x, expected: % x", test.name, got, test.expected)
+		}
+
+		// unpack the packed data with the read only status
+		address := &IP6Address{}
+		if err := address.Unpack(append(got, uint8(IP6AddressStatusActive))); err != nil {
+			t.Errorf("test %s: unpack failed, err: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(address, test.address) {
+			t.Errorf("test %s unpack not matched, got: %+v, expected: %+v", test.name, address, test.address)
+		}
+	}
+}
+
+func Test_SetLanIP6Params(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      func(c *Client) error
+		expected [][]byte
+	}{
+		{
+			name: "enables",
+			set: func(c *Client) error {
+				return c.SetLanIP6Enables(1, IP6EnablesIP6Only)
+			},
+			expected: [][]byte{{0x01, 0x33, 0x01}},
+		},
+		{
+			name: "flow label",
+			set: func(c *Client) error {
+				return c.SetLanIP6FlowLabel(1, 0xabcde)
+			},
+			expected: [][]byte{{0x01, 0x36, 0x0a, 0xbc, 0xde}},
+		},
+		{
+			name: "router config",
+			set: func(c *Client) error {
+				return c.SetLanIP6RouterConfig(1, IP6RouterConfig{EnableDynamic: true, EnableStatic: true})
+			},
+			expected: [][]byte{{0x01, 0x40, 0x03}},
+		},
+		{
+			name: "static router 2",
+			set: func(c *Client) error {
+				return c.SetLanIP6StaticRouter(1, 2, &IP6Router{
+					IP:           net.ParseIP("2001:db8::fe"),
+					MAC:          net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+					PrefixLength: 64,
+				})
+			},
+			expected: [][]byte{
+				{0x01, 0x45, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xfe},
+				{0x01, 0x46, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+				{0x01, 0x47, 0x40},
+				{0x01, 0x48, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		var got [][]byte
+		c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+			if req.Cmd != CommandSetLanConfigParams.ID {
+				return 0xc1, nil
+			}
+			got = append(got, append([]byte{}, req.Data...))
+			return 0x00, nil
+		})
+
+		if err := test.set(c); err != nil {
+			t.Errorf("test %s: set failed, err: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("test %s not matched, got: % x, expected: % x", test.name, got, test.expected)
+		}
+	}
+}
+
+func Test_GetLanConfig_IP6(t *testing.T) {
+	tests := []struct {
+		name string
+		// data of IPv6/IPv4 Support
+		support          []byte
+		expectedRequests map[LanParamSelector]int
+	}{
+		{
+			name:    "not supported",
+			support: []byte{0x00},
+			expectedRequests: map[LanParamSelector]int{
+				LanParam_IP6Support:       1,
+				LanParam_IP6StaticAddr:    0,
+				LanParam_IP6Status:        0,
+				LanParam_IP6NDSLAACTiming: 0,
+			},
+		},
+		{
+			name:    "supported",
+			support: []byte{0x02},
+			expectedRequests: map[LanParamSelector]int{
+				LanParam_IP6Support:                 1,
+				LanParam_IP6Status:                  1,
+				LanParam_IP6StaticAddr:              2,
+				LanParam_IP6DynamicAddr:             3,
+				LanParam_IP6DynamicRouterSetsNumber: 1,
+				LanParam_IP6DynamicRouterIP:         1,
+				LanParam_IP6NDSLAACTiming:           1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		requests := make(map[LanParamSelector]int)
+		c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+			if req.Cmd != CommandGetLanConfigParams.ID || len(req.Data) < 4 {
+				return 0xc1, nil
+			}
+			paramSelector := LanParamSelector(req.Data[1])
+			requests[paramSelector]++
+
+			switch paramSelector {
+			case LanParam_IP6Support:
+				return 0x00, append([]byte{0x11}, test.support...)
+			case LanParam_IP6Status:
+				return 0x00, []byte{0x11, 0x02, 0x03, 0x00}
+			case LanParam_IP6DynamicRouterSetsNumber:
+				return 0x00, []byte{0x11, 0x01}
+			}
+			return 0x80, nil
+		})
+
+		if _, err := c.GetLanConfig(1); err != nil {
+			t.Errorf("test %s: GetLanConfig failed, err: %s", test.name, err)
+			continue
+		}
+		for paramSelector, expected := range test.expectedRequests {
+			if requests[paramSelector] != expected {
+				t.Errorf("test %s: requests of param (%s) not matched, got: %d, expected: %d", test.name, paramSelector, requests[paramSelector], expected)
+			}
+		}
+		// the params before the IPv6 params are always read
+		if requests[LanParam_IP] != 1 {
+			t.Errorf("test %s: requests of param (%s) not matched, got: %d, expected: %d", test.name, LanParam_IP, requests[LanParam_IP], 1)
+		}
+	}
+}
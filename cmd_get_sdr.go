@@ -1,6 +1,10 @@
 package ipmi
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // 33.12 Get SDR Command
 type GetSDRRequest struct {
@@ -47,154 +51,493 @@ func (res *GetSDRResponse) Format() string {
 }
 
 // GetSDR returns raw SDR record.
+//
+// The entire record is requested at first, if the BMC can not return it in one
+// response (eg: small message size when bridging), the record is then read in
+// chunks by partial reads with a reservation, see readSDRPartially.
 func (c *Client) GetSDR(recordID uint16) (response *GetSDRResponse, err error) {
-	request := &GetSDRRequest{
-		ReservationID: 0,
-		RecordID:      recordID,
-		Offset:        0,
-		Read:          0xff,
+	readSize := c.sdrReadSize(false)
+	if *readSize == 0 {
+		request := &GetSDRRequest{
+			ReservationID: 0,
+			RecordID:      recordID,
+			Offset:        0,
+			Read:          0xff,
+		}
+		response = &GetSDRResponse{}
+		err = c.Exchange(request, response)
+		if err == nil || !sdrReadLength2Big(err) {
+			return
+		}
+		c.Debugf("Get entire SDR record failed, fallback to partial reads, err: %s\n", err)
+	}
+
+	reserve := func() (uint16, error) {
+		res, err := c.ReserveSDRRepo()
+		if err != nil {
+			return 0, fmt.Errorf("ReserveSDRRepo failed, err: %s", err)
+		}
+		return res.ReservationID, nil
+	}
+
+	read := func(reservationID uint16, recordID uint16, offset uint8, count uint8) (uint16, []byte, error) {
+		request := &GetSDRRequest{
+			ReservationID: reservationID,
+			RecordID:      recordID,
+			Offset:        offset,
+			Read:          count,
+		}
+		response := &GetSDRResponse{}
+		if err := c.Exchange(request, response); err != nil {
+			return 0, nil, err
+		}
+		return response.NextRecordID, response.RecordData, nil
+	}
+
+	nextRecordID, recordData, err := c.readSDRPartially(recordID, readSize, reserve, read)
+	if err != nil {
+		return nil, err
 	}
-	response = &GetSDRResponse{}
-	err = c.Exchange(request, response)
-	return
+	return &GetSDRResponse{
+		NextRecordID: nextRecordID,
+		RecordData:   recordData,
+	}, nil
 }
 
-func (c *Client) GetSDRBySensorID(sensorNumber uint8) (*SDR, error) {
-	if SensorNumber(sensorNumber) == SensorNumberReserved {
-		return nil, fmt.Errorf("not valid sensorNumber, %#0x is reserved", sensorNumber)
+const (
+	// sdrPartialReadSize is the initial number of bytes to read in one partial read,
+	// it is decreased if the BMC can not return that many bytes.
+	sdrPartialReadSize uint8 = 32
+
+	// sdrReserveRetries is the max times to re-reserve the repository
+	// when the reservation is cancelled during partial reads of one record.
+	sdrReserveRetries int = 5
+)
+
+type sdrReadSizeKey struct {
+	target target
+	device bool
+}
+
+// sdrReadSize returns the number of bytes to read in one partial read of SDR records
+// from the SDR Repository or the Device SDR Repository (if device is true) of the current target.
+// Zero means the entire record can be read in one response.
+func (c *Client) sdrReadSize(device bool) *uint8 {
+	if c.sdrReadSizes == nil {
+		c.sdrReadSizes = make(map[sdrReadSizeKey]*uint8)
+	}
+	key := sdrReadSizeKey{target: c.target, device: device}
+	if _, ok := c.sdrReadSizes[key]; !ok {
+		c.sdrReadSizes[key] = new(uint8)
 	}
+	return c.sdrReadSizes[key]
+}
 
-	var recordID uint16 = 0
-	for {
-		res, err := c.GetSDR(recordID)
+// sdrPartialReadFunc reads count bytes at offset of the SDR record with the reservation,
+// it is implemented by Get SDR and Get Device SDR commands.
+type sdrPartialReadFunc func(reservationID uint16, recordID uint16, offset uint8, count uint8) (nextRecordID uint16, data []byte, err error)
+
+// errSDRRecordChanged is returned by sdrPartialReadFunc if the record is changed during partial reads.
+var errSDRRecordChanged = errors.New("SDR record changed")
+
+// readSDRPartially reads one SDR record by partial reads, like ipmitool does.
+// It reserves the repository, reads the record header to get the record length,
+// then reads the record body in chunks of readSize bytes. The readSize is decreased
+// if the controller rejects the read count, and kept for the next records.
+//
+// If the reservation is cancelled or the record is changed, the repository is reserved again
+// and the record is read again from the header, as the record may be changed.
+func (c *Client) readSDRPartially(recordID uint16, readSize *uint8, reserve func() (uint16, error), read sdrPartialReadFunc) (uint16, []byte, error) {
+	for retry := 0; ; retry++ {
+		reservationID, err := reserve()
 		if err != nil {
-			return nil, fmt.Errorf("GetSDR failed, err: %s", err)
+			return 0, nil, err
 		}
-		sdr, err := ParseSDR(res.RecordData, res.NextRecordID)
-		if err != nil {
-			return nil, fmt.Errorf("ParseSDR failed, err: %s", err)
+
+		nextRecordID, data, err := c.readSDRRecord(recordID, reservationID, readSize, read)
+		if err == nil {
+			return nextRecordID, data, nil
+		}
+		if !sdrReadRestart(err) || retry >= sdrReserveRetries {
+			return 0, nil, err
 		}
+		c.Debugf("Read SDR record (%#04x) interrupted, reserve again, err: %s\n", recordID, err)
+	}
+}
 
-		if uint8(sdr.SensorNumber()) != sensorNumber {
-			recordID = sdr.NextRecordID
-			if recordID == 0xffff {
-				break
-			}
-			continue
+// readSDRRecord reads the header and the body of the SDR record with the reservation.
+func (c *Client) readSDRRecord(recordID uint16, reservationID uint16, readSize *uint8, read sdrPartialReadFunc) (uint16, []byte, error) {
+	const SDRRecordHeaderSize int = 5
+
+	c.Debugf("Partial read SDR record (%#04x) header\n", recordID)
+	nextRecordID, header, err := read(reservationID, recordID, 0, uint8(SDRRecordHeaderSize))
+	if err != nil {
+		return 0, nil, fmt.Errorf("read SDR record header failed, err: %w", err)
+	}
+	if len(header) < SDRRecordHeaderSize {
+		return 0, nil, fmt.Errorf("SDR record header too short, got %d bytes", len(header))
+	}
+
+	recordLength := SDRRecordHeaderSize + int(header[4])
+	if recordLength > 0xff {
+		return 0, nil, fmt.Errorf("SDR record length (%d) exceeds the max offset of partial reads", recordLength)
+	}
+
+	data := make([]byte, 0, recordLength)
+	data = append(data, header[:SDRRecordHeaderSize]...)
+	for len(data) < recordLength {
+		if *readSize == 0 {
+			*readSize = sdrPartialReadSize
+		}
+		count := *readSize
+		if left := recordLength - len(data); left < int(count) {
+			count = uint8(left)
 		}
 
-		if err := c.enhanceSDR(sdr); err != nil {
-			return sdr, fmt.Errorf("enhanceSDR failed, err: %s", err)
+		c.Debugf("Partial read SDR record (%#04x), offset: (%d), count: (%d)\n", recordID, len(data), count)
+		_, chunk, err := read(reservationID, recordID, uint8(len(data)), count)
+		if err != nil {
+			// ipmitool also decreases the read size on unspecified error
+			if (sdrReadLength2Big(err) || isResponseErrorCC(err, uint8(CompletionCodeUnspecifiedError))) && *readSize > 1 {
+				*readSize = *readSize / 2
+				continue
+			}
+			return 0, nil, fmt.Errorf("read SDR record body at offset (%d) failed, err: %w", len(data), err)
+		}
+		if len(chunk) == 0 {
+			return 0, nil, fmt.Errorf("read SDR record body at offset (%d) returned no data", len(data))
 		}
-		return sdr, nil
+		data = append(data, chunk...)
 	}
 
-	return nil, fmt.Errorf("not found SDR for sensor id (%#0x)", sensorNumber)
+	return nextRecordID, data[:recordLength], nil
 }
 
-func (c *Client) GetSDRBySensorName(sensorName string) (*SDR, error) {
-	var recordID uint16 = 0
-	for {
-		res, err := c.GetSDR(recordID)
-		if err != nil {
-			return nil, fmt.Errorf("GetSDR failed, err: %s", err)
+// sdrReadRestart checks whether the partial reads of the record should be restarted.
+func sdrReadRestart(err error) bool {
+	var resErr *ResponseError
+	if errors.As(err, &resErr) {
+		return resErr.CompletionCode() == CompletionCodeReservationCanceled
+	}
+	return errors.Is(err, errSDRRecordChanged)
+}
+
+// sdrReadLength2Big checks whether the err is caused by
+// the read count which the BMC can not return in one response.
+func sdrReadLength2Big(err error) bool {
+	var resErr *ResponseError
+	if !errors.As(err, &resErr) {
+		return false
+	}
+	cc := resErr.CompletionCode()
+	return cc == CompletionCodeRequestDataLengthInvalid ||
+		cc == CompletionCodeRequestDataLengthLimitExceeded ||
+		cc == CompletionCodeCannotReturnRequestedDataBytes
+}
+
+func (c *Client) GetSDRBySensorID(sensorNumber uint8) (*SDR, error) {
+	if SensorNumber(sensorNumber) == SensorNumberReserved {
+		return nil, fmt.Errorf("not valid sensorNumber, %#0x is reserved", sensorNumber)
+	}
+
+	// The sensor numbers are only unique per sensor owner, so all the SDRs are walked.
+	var founds []*SDR
+	err := c.walkSDRs(func(sdr *SDR) bool {
+		for _, s := range sdr.ExpandShared() {
+			if uint8(s.SensorNumber()) == sensorNumber {
+				founds = append(founds, s)
+			}
 		}
-		sdr, err := ParseSDR(res.RecordData, res.NextRecordID)
-		if err != nil {
-			return nil, fmt.Errorf("ParseSDR failed, err: %s", err)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(founds) == 0 {
+		return nil, fmt.Errorf("not found SDR for sensor id (%#0x)", sensorNumber)
+	}
+	owners := make(map[GeneratorID]bool)
+	for _, s := range founds {
+		key, _ := s.ownerKey()
+		owners[key.generatorID] = true
+	}
+	if len(owners) > 1 {
+		list := make([]string, 0, len(founds))
+		for _, s := range founds {
+			key, _ := s.ownerKey()
+			list = append(list, fmt.Sprintf("%#02x (LUN: %d)", uint8(key.generatorID), uint8(key.generatorID>>8)&0x03))
 		}
+		return nil, fmt.Errorf("sensor id (%#0x) is owned by several controllers: %s, specify the sensor owner", sensorNumber, strings.Join(list, ", "))
+	}
 
-		if sdr.SensorName() != sensorName {
-			recordID = sdr.NextRecordID
-			if recordID == 0xffff {
-				break
+	found := founds[0]
+	if err := c.enhanceSDR(found); err != nil {
+		return found, fmt.Errorf("enhanceSDR failed, err: %s", err)
+	}
+	return found, nil
+}
+
+// GetSDRBySensorOwner returns the SDR of the sensor by the sensor owner ID, sensor owner LUN and sensor number.
+func (c *Client) GetSDRBySensorOwner(ownerID uint8, ownerLUN uint8, sensorNumber uint8) (*SDR, error) {
+	if SensorNumber(sensorNumber) == SensorNumberReserved {
+		return nil, fmt.Errorf("not valid sensorNumber, %#0x is reserved", sensorNumber)
+	}
+
+	var found *SDR
+	err := c.walkSDRs(func(sdr *SDR) bool {
+		for _, s := range sdr.ExpandShared() {
+			key, ok := s.ownerKey()
+			if !ok {
+				continue
+			}
+			if uint8(key.sensorNumber) == sensorNumber && uint8(key.generatorID) == ownerID && uint8(key.generatorID>>8)&0x03 == ownerLUN {
+				found = s
+				return true
 			}
-			continue
 		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("not found SDR for sensor id (%#0x) owned by %#02x (LUN: %d)", sensorNumber, ownerID, ownerLUN)
+	}
 
-		if err := c.enhanceSDR(sdr); err != nil {
-			return sdr, fmt.Errorf("enhanceSDR failed, err: %s", err)
+	if err := c.enhanceSDR(found); err != nil {
+		return found, fmt.Errorf("enhanceSDR failed, err: %s", err)
+	}
+	return found, nil
+}
+
+func (c *Client) GetSDRBySensorName(sensorName string) (*SDR, error) {
+	var found *SDR
+	err := c.walkSDRs(func(sdr *SDR) bool {
+		for _, s := range sdr.ExpandShared() {
+			if s.SensorName() == sensorName {
+				found = s
+				return true
+			}
 		}
-		return sdr, nil
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("not found SDR for sensor name (%s)", sensorName)
 	}
 
-	return nil, fmt.Errorf("not found SDR for sensor name (%#0x)", sensorName)
+	if err := c.enhanceSDR(found); err != nil {
+		return found, fmt.Errorf("enhanceSDR failed, err: %s", err)
+	}
+	return found, nil
 }
 
 // GetSDRs fetches the SDR records with the specified RecordTypes.
 // The parameter is a slice of SDRRecordType used as filter.
 // Empty means to get all SDR records.
 func (c *Client) GetSDRs(recordTypes ...SDRRecordType) ([]*SDR, error) {
-	var recordID uint16 = 0
 	var out = make([]*SDR, 0)
-	for {
-		res, err := c.GetSDR(recordID)
-		if err != nil {
-			return nil, fmt.Errorf("GetSDR for recordID (%#0x) failed, err: %s", recordID, err)
-		}
-		sdr, err := ParseSDR(res.RecordData, res.NextRecordID)
-		if err != nil {
-			return nil, fmt.Errorf("ParseSDR failed, err: %s", err)
-		}
 
+	err := c.walkSDRs(func(sdr *SDR) bool {
 		if len(recordTypes) == 0 {
 			out = append(out, sdr)
-		} else {
-			for _, v := range recordTypes {
-				if sdr.RecordHeader.RecordType == v {
-					out = append(out, sdr)
-					break
-				}
-			}
+			return false
 		}
-
-		recordID = sdr.NextRecordID
-		if recordID == 0xffff {
-			break
+		for _, v := range recordTypes {
+			if sdr.RecordHeader.RecordType == v {
+				out = append(out, sdr)
+				break
+			}
 		}
+		return false
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return out, nil
 }
 
-// GetSDRsMap returns all Full/Compact SDRs grouped by GeneratorID and SensorNumber.
+// GetSDRsMap returns all Full/Compact/Event-Only SDRs grouped by GeneratorID and SensorNumber.
 // The sensor name can only be got from SDR record. So use this method to construct a map from which
 // you can get sensor name.
+//
+// The shared Compact and Event-Only records are expanded to one SDR per sensor, see SDR.ExpandShared.
 func (c *Client) GetSDRsMap() (SDRMapBySensorNumber, error) {
 	var out = make(map[GeneratorID]map[SensorNumber]*SDR)
 
-	var recordID uint16 = 0
-	for {
-		res, err := c.GetSDR(recordID)
-		if err != nil {
-			return nil, fmt.Errorf("GetSDR for recordID (%#0x) failed, err: %s", recordID, err)
+	sdrs, err := c.GetSDRs(SDRRecordTypeFullSensor, SDRRecordTypeCompactSensor, SDRRecordTypeEventOnly)
+	if err != nil {
+		return nil, fmt.Errorf("GetSDRs failed, err: %s", err)
+	}
+
+	for _, sdr := range sdrs {
+		for _, s := range sdr.ExpandShared() {
+			key, ok := s.ownerKey()
+			if !ok {
+				continue
+			}
+
+			if _, ok := out[key.generatorID]; !ok {
+				out[key.generatorID] = make(map[SensorNumber]*SDR)
+			}
+			// Full and Compact records take precedence over Event-Only records for the same sensor
+			if _, exists := out[key.generatorID][key.sensorNumber]; exists && s.RecordHeader.RecordType == SDRRecordTypeEventOnly {
+				continue
+			}
+			out[key.generatorID][key.sensorNumber] = s
 		}
-		sdr, err := ParseSDR(res.RecordData, res.NextRecordID)
+	}
+
+	return out, nil
+}
+
+// walkSDRs calls fn for each SDR record in order, and stops the walk
+// once fn returns true.
+//
+// The SDR records are loaded from the SDR file if the client is
+// configured by WithSDRFile, otherwise they are read from the BMC, see walkBMCSDRs.
+// If the client is configured by WithSatelliteSDRs, the SDRs of the satellite
+// controllers found by Management Controller Device Locator records are
+// walked after those of the BMC.
+func (c *Client) walkSDRs(fn func(sdr *SDR) bool) error {
+	if c.sdrFile != "" {
+		sdrs, err := c.loadSDRFile()
 		if err != nil {
-			return nil, fmt.Errorf("ParseSDR failed, err: %s", err)
+			return err
+		}
+		for _, sdr := range sdrs {
+			if fn(sdr) {
+				break
+			}
 		}
+		return nil
+	}
+
+	if !c.satelliteSDRs {
+		return c.walkBMCSDRs(fn)
+	}
 
-		var generatorID GeneratorID
-		var sensorNumber SensorNumber
+	// The sensors of satellite controllers may also be recorded in the
+	// SDR Repository of the BMC, so the sensor records are merged by
+	// the sensor owner (ID, channel, LUN) and sensor number.
+	seen := make(map[sdrOwnerKey]bool)
+	locators := make([]*SDRMgmtControllerDeviceLocator, 0)
+	stopped := false
 
-		recordType := sdr.RecordHeader.RecordType
-		switch recordType {
-		case SDRRecordTypeFullSensor:
-			generatorID = sdr.Full.GeneratorID
-			sensorNumber = sdr.Full.SensorNumber
-		case SDRRecordTypeCompactSensor:
-			generatorID = sdr.Compact.GeneratorID
-			sensorNumber = sdr.Compact.SensorNumber
+	err := c.walkBMCSDRs(func(sdr *SDR) bool {
+		if key, ok := sdr.ownerKey(); ok {
+			seen[key] = true
 		}
-		if recordType != SDRRecordTypeFullSensor && recordType != SDRRecordTypeCompactSensor {
-			// ignored the SDR
+		if sdr.RecordHeader.RecordType == SDRRecordTypeManagementControllerDeviceLocator {
+			locators = append(locators, sdr.MgmtControllerDeviceLocator)
+		}
+		stopped = fn(sdr)
+		return stopped
+	})
+	if err != nil || stopped {
+		return err
+	}
+
+	for _, locator := range locators {
+		addr := locator.DeviceSlaveAddress & 0xfe
+		channel := locator.ChannelNumber & 0x0f
+		if addr == BMC_SA {
+			continue
+		}
+		// For controllers other than the BMC, the SDR Repository Device capability
+		// indicates the controller accepts Device SDR commands.
+		if !locator.DeviceCap_SensorDevice || !locator.DeviceCap_SDRRepoDevice {
 			continue
 		}
 
-		if _, ok := out[generatorID]; !ok {
-			out[generatorID] = make(map[SensorNumber]*SDR)
+		err := c.withTarget(addr, channel, 0, func() error {
+			return c.walkDeviceSDRs(func(sdr *SDR) bool {
+				if key, ok := sdr.ownerKey(); ok {
+					if seen[key] {
+						return false
+					}
+					seen[key] = true
+				}
+				stopped = fn(sdr)
+				return stopped
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("walk device SDRs of satellite controller (%#02x) on channel (%d) failed, err: %w", addr, channel, err)
+		}
+		if stopped {
+			break
+		}
+	}
+
+	return nil
+}
+
+// walkBMCSDRs calls fn for each SDR record of the BMC, and stops the walk
+// once fn returns true.
+//
+// The SDR records are read from the SDR Repository (through the SDR cache if
+// the client is configured by WithSDRCache), or by the Device SDR commands across
+// all LUNs if the BMC is a Sensor Device but not a SDR Repository Device.
+func (c *Client) walkBMCSDRs(fn func(sdr *SDR) bool) error {
+	if c.useDeviceSDRs() {
+		return c.walkDeviceSDRs(fn)
+	}
+
+	if c.sdrCache != nil {
+		sdrs, err := c.cachedSDRs()
+		if err == nil {
+			for _, sdr := range sdrs {
+				if fn(sdr) {
+					break
+				}
+			}
+			return nil
+		}
+		c.Debug("SDR cache not usable, fallback to read SDR Repository", err)
+	}
+
+	return c.walkRepoSDRs(fn)
+}
+
+// useDeviceSDRs checks whether the SDRs of the BMC should be read by Device SDR commands,
+// that is the BMC reports Sensor Device but not SDR Repository Device in Get Device ID.
+// The result is detected once for the client.
+func (c *Client) useDeviceSDRs() bool {
+	if c.sdrSource == sdrSourceUnknown {
+		c.sdrSource = sdrSourceRepo
+
+		res, err := c.GetDeviceID()
+		if err != nil {
+			c.Debug("GetDeviceID failed, assume SDR Repository Device", err)
+		} else if !res.SupportSDRRepo && res.SupportSensor {
+			c.sdrSource = sdrSourceDevice
+		}
+	}
+
+	return c.sdrSource == sdrSourceDevice
+}
+
+// walkRepoSDRs calls fn for each SDR record read from the SDR Repository,
+// and stops the walk once fn returns true.
+func (c *Client) walkRepoSDRs(fn func(sdr *SDR) bool) error {
+	var recordID uint16 = 0
+	for {
+		res, err := c.GetSDR(recordID)
+		if err != nil {
+			return fmt.Errorf("GetSDR for recordID (%#0x) failed, err: %s", recordID, err)
+		}
+		sdr, err := ParseSDR(res.RecordData, res.NextRecordID)
+		if err != nil {
+			return fmt.Errorf("ParseSDR failed, err: %s", err)
+		}
+
+		if fn(sdr) {
+			break
 		}
-		out[generatorID][sensorNumber] = sdr
 
 		recordID = sdr.NextRecordID
 		if recordID == 0xffff {
@@ -202,5 +545,5 @@ func (c *Client) GetSDRsMap() (SDRMapBySensorNumber, error) {
 		}
 	}
 
-	return out, nil
+	return nil
 }
@@ -0,0 +1,219 @@
+package ipmi
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// fakeSDRRepo serves partial reads of one SDR record,
+// or of the records by the fake BMC (see handle).
+type fakeSDRRepo struct {
+	record        []byte
+	records       [][]byte
+	reservationID uint16
+	reserves      int
+
+	// maxRead is the max count the repo can return, larger counts fail with CC CAh (or FFh if unspecified is set).
+	maxRead     uint8
+	unspecified bool
+
+	// onRead is called before each read, it may change the repo or return an error to fail the read.
+	onRead func(repo *fakeSDRRepo, offset uint8, count uint8) error
+}
+
+func (repo *fakeSDRRepo) reserve() (uint16, error) {
+	repo.reserves++
+	repo.reservationID++
+	return repo.reservationID, nil
+}
+
+func (repo *fakeSDRRepo) read(reservationID uint16, recordID uint16, offset uint8, count uint8) (uint16, []byte, error) {
+	if repo.onRead != nil {
+		if err := repo.onRead(repo, offset, count); err != nil {
+			return 0, nil, err
+		}
+	}
+	if reservationID != repo.reservationID {
+		return 0, nil, &ResponseError{completionCode: CompletionCodeReservationCanceled}
+	}
+	if repo.maxRead != 0 && count > repo.maxRead {
+		if repo.unspecified {
+			return 0, nil, &ResponseError{completionCode: CompletionCodeUnspecifiedError}
+		}
+		return 0, nil, &ResponseError{completionCode: CompletionCodeCannotReturnRequestedDataBytes}
+	}
+	end := int(offset) + int(count)
+	if end > len(repo.record) {
+		end = len(repo.record)
+	}
+	return recordID + 1, repo.record[offset:end], nil
+}
+
+// handle serves the records by Reserve SDR Repository and Get SDR commands,
+// the Record IDs of the records are 1, 2, ...
+func (repo *fakeSDRRepo) handle(req *testBMCRequest) (uint8, []byte) {
+	switch req.Cmd {
+	case CommandReserveSDRRepo.ID:
+		reservationID, _ := repo.reserve()
+		out := make([]byte, 2)
+		packUint16L(reservationID, out, 0)
+		return 0x00, out
+
+	case CommandGetSDR.ID:
+		reservationID, _, _ := unpackUint16L(req.Data, 0)
+		recordID, _, _ := unpackUint16L(req.Data, 2)
+		if recordID == 0 {
+			recordID = 1
+		}
+		if int(recordID) > len(repo.records) {
+			return uint8(CompletionCodeRequestedDataNotPresent), nil
+		}
+		repo.record = repo.records[recordID-1]
+		nextRecordID, data, err := repo.read(reservationID, recordID, req.Data[4], req.Data[5])
+		if err != nil {
+			var resErr *ResponseError
+			if errors.As(err, &resErr) {
+				return uint8(resErr.CompletionCode()), nil
+			}
+			return uint8(CompletionCodeUnspecifiedError), nil
+		}
+		if int(nextRecordID) > len(repo.records) {
+			nextRecordID = 0xffff
+		}
+		out := make([]byte, 2)
+		packUint16L(nextRecordID, out, 0)
+		return 0x00, append(out, data...)
+	}
+	return 0xc1, nil
+}
+
+func Test_readSDRPartially(t *testing.T) {
+	record := testCompactSDR(1, 0x01, "CPU1 Temperature")
+	changedRecord := testCompactSDR(1, 0x01, "Temp")
+
+	tests := []struct {
+		name             string
+		repo             *fakeSDRRepo
+		expected         []byte
+		expectedReserves int
+		expectedReadSize uint8
+		expectedErr      bool
+	}{
+		{
+			name:             "whole",
+			repo:             &fakeSDRRepo{},
+			expected:         record,
+			expectedReserves: 1,
+			expectedReadSize: sdrPartialReadSize,
+		},
+		{
+			name:             "shrink on cannot return requested bytes",
+			repo:             &fakeSDRRepo{maxRead: 10},
+			expected:         record,
+			expectedReserves: 1,
+			expectedReadSize: 8,
+		},
+		{
+			name:             "shrink on unspecified error",
+			repo:             &fakeSDRRepo{maxRead: 16, unspecified: true},
+			expected:         record,
+			expectedReserves: 1,
+			expectedReadSize: 16,
+		},
+		{
+			name: "reservation lost",
+			repo: &fakeSDRRepo{
+				onRead: func(repo *fakeSDRRepo, offset uint8, count uint8) error {
+					// another requester reserves the repository once during the body reads
+					if offset > 0 && repo.reserves == 1 {
+						repo.reservationID++
+					}
+					return nil
+				},
+			},
+			expected:         record,
+			expectedReserves: 2,
+			expectedReadSize: sdrPartialReadSize,
+		},
+		{
+			name: "record changed",
+			repo: &fakeSDRRepo{
+				onRead: func(repo *fakeSDRRepo, offset uint8, count uint8) error {
+					// the record is replaced by a shorter one after the header is read
+					if offset > 0 && repo.reserves == 1 {
+						repo.record = changedRecord
+						return errSDRRecordChanged
+					}
+					return nil
+				},
+			},
+			expected:         changedRecord,
+			expectedReserves: 2,
+			expectedReadSize: sdrPartialReadSize,
+		},
+		{
+			name: "reservation always lost",
+			repo: &fakeSDRRepo{
+				onRead: func(repo *fakeSDRRepo, offset uint8, count uint8) error {
+					return &ResponseError{completionCode: CompletionCodeReservationCanceled}
+				},
+			},
+			expectedReserves: sdrReserveRetries + 1,
+			expectedErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		c := &Client{}
+		if test.repo.record == nil {
+			test.repo.record = append([]byte{}, record...)
+		}
+
+		var readSize uint8
+		nextRecordID, data, err := c.readSDRPartially(1, &readSize, test.repo.reserve, test.repo.read)
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("test %s: expected error, got nil", test.name)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test %s: readSDRPartially failed, err: %s", test.name, err)
+				continue
+			}
+			if !bytes.Equal(data, test.expected) {
+				t.Errorf("test %s: record not matched, got: %v, expected: %v", test.name, data, test.expected)
+			}
+			if nextRecordID != 2 {
+				t.Errorf("test %s: next record id not matched, got: %d, expected: 2", test.name, nextRecordID)
+			}
+			if readSize != test.expectedReadSize {
+				t.Errorf("test %s: read size not matched, got: %d, expected: %d", test.name, readSize, test.expectedReadSize)
+			}
+		}
+		if test.repo.reserves != test.expectedReserves {
+			t.Errorf("test %s: reserves not matched, got: %d, expected: %d", test.name, test.repo.reserves, test.expectedReserves)
+		}
+	}
+}
+
+func Test_sdrReadSize(t *testing.T) {
+	c := &Client{}
+
+	*c.sdrReadSize(false) = 4
+	if got := *c.sdrReadSize(true); got != 0 {
+		t.Errorf("device SDR read size not separated, got: %d, expected: 0", got)
+	}
+
+	_ = c.withTarget(0x2c, 6, 0, func() error {
+		if got := *c.sdrReadSize(false); got != 0 {
+			t.Errorf("satellite SDR read size not separated, got: %d, expected: 0", got)
+		}
+		*c.sdrReadSize(false) = 8
+		return nil
+	})
+
+	if got := *c.sdrReadSize(false); got != 4 {
+		t.Errorf("BMC SDR read size not matched, got: %d, expected: 4", got)
+	}
+}
@@ -16,6 +16,11 @@ type GetSensorEventEnableResponse struct {
 	SensorScanningDisabled bool
 
 	SensorEventFlag
+
+	// AssertionEventMask and DeassertionEventMask hold the enable bits of the assertion and deassertion events,
+	// the same bits as the SensorEventFlag, see SensorEventsOfMask.
+	AssertionEventMask   uint16
+	DeassertionEventMask uint16
 }
 
 func (req *GetSensorEventEnableRequest) Command() Command {
@@ -36,6 +41,14 @@ func (res *GetSensorEventEnableResponse) Unpack(msg []byte) error {
 	res.EventMessagesDisabled = !isBit7Set(b1)
 	res.SensorScanningDisabled = !isBit6Set(b1)
 
+	// the absent bytes are all disabled
+	masks := make([]byte, 4)
+	copy(masks, msg[1:])
+	assertionMask, _, _ := unpackUint16L(masks, 0)
+	deassertionMask, _, _ := unpackUint16L(masks, 2)
+	res.AssertionEventMask = assertionMask & 0x7fff
+	res.DeassertionEventMask = deassertionMask & 0x7fff
+
 	if len(msg) >= 2 {
 		b2, _, _ := unpackUint8(msg, 1)
 		res.SensorEvent_UNC_High_Assert = isBit7Set(b2)
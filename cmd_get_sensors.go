@@ -25,21 +25,24 @@ func (c *Client) GetSensors(filterOptions ...SensorFilterOption) ([]*Sensor, err
 	}
 
 	for _, sdr := range sdrs {
-		sensor, err := c.sdrToSensor(sdr)
-		if err != nil {
-			return nil, fmt.Errorf("sdrToSensor failed, err: %s", err)
-		}
+		// shared Compact records describe several sensors
+		for _, s := range sdr.ExpandShared() {
+			sensor, err := c.sdrToSensor(s)
+			if err != nil {
+				return nil, fmt.Errorf("sdrToSensor failed, err: %s", err)
+			}
 
-		var choose bool = true
-		for _, filterOption := range filterOptions {
-			if !filterOption(sensor) {
-				choose = false
-				break
+			var choose bool = true
+			for _, filterOption := range filterOptions {
+				if !filterOption(sensor) {
+					choose = false
+					break
+				}
 			}
-		}
 
-		if choose {
-			out = append(out, sensor)
+			if choose {
+				out = append(out, sensor)
+			}
 		}
 	}
 
@@ -47,6 +50,8 @@ func (c *Client) GetSensors(filterOptions ...SensorFilterOption) ([]*Sensor, err
 }
 
 // GetSensor returns the sensor with current reading and status by specified sensor number.
+// The sensor numbers are only unique per sensor owner, it returns error if sensors of
+// several owners have the number, use GetSensorByOwner instead.
 func (c *Client) GetSensorByID(sensorNumber uint8) (*Sensor, error) {
 	sdr, err := c.GetSDRBySensorID(sensorNumber)
 	if err != nil {
@@ -61,6 +66,22 @@ func (c *Client) GetSensorByID(sensorNumber uint8) (*Sensor, error) {
 	return sensor, nil
 }
 
+// GetSensorByOwner returns the sensor with current reading and status by specified
+// sensor owner ID, sensor owner LUN and sensor number.
+func (c *Client) GetSensorByOwner(ownerID uint8, ownerLUN uint8, sensorNumber uint8) (*Sensor, error) {
+	sdr, err := c.GetSDRBySensorOwner(ownerID, ownerLUN, sensorNumber)
+	if err != nil {
+		return nil, fmt.Errorf("GetSDRBySensorOwner failed, err: %s", err)
+	}
+
+	sensor, err := c.sdrToSensor(sdr)
+	if err != nil {
+		return nil, fmt.Errorf("GetSensorFromSDR failed, err: %s", err)
+	}
+
+	return sensor, nil
+}
+
 // GetSensor returns the sensor with current reading and status by specified sensor name.
 func (c *Client) GetSensorByName(sensorName string) (*Sensor, error) {
 	sdr, err := c.GetSDRBySensorName(sensorName)
@@ -90,10 +111,15 @@ func (c *Client) sdrToSensor(sdr *SDR) (*Sensor, error) {
 		HasAnalogReading: sdr.HasAnalogReading(),
 	}
 
+	var mask Mask
+	var ownerID GeneratorID
+
 	switch sdr.RecordHeader.RecordType {
 	case SDRRecordTypeFullSensor:
 		sensor.Number = uint8(sdr.Full.SensorNumber)
 		sensor.Name = string(sdr.Full.IDStringBytes)
+		sensor.EntityID = sdr.Full.SensorEntityID
+		sensor.EntityInstance = sdr.Full.SensorEntityInstance
 		sensor.SensorUnit = sdr.Full.SensorUnit
 		sensor.SensorType = sdr.Full.SensorType
 		sensor.EventReadingType = sdr.Full.SensorEventReadingType
@@ -102,46 +128,93 @@ func (c *Client) sdrToSensor(sdr *SDR) (*Sensor, error) {
 
 		sensor.Threshold.LinearizationFunc = sdr.Full.LinearizationFunc
 		sensor.Threshold.ReadingFactors = sdr.Full.ReadingFactors
+		mask = sdr.Full.Mask
+		ownerID = sdr.Full.GeneratorID
 
 	case SDRRecordTypeCompactSensor:
 		sensor.Number = uint8(sdr.Compact.SensorNumber)
 		sensor.Name = string(sdr.Compact.IDStringBytes)
+		sensor.EntityID = sdr.Compact.SensorEntityID
+		sensor.EntityInstance = sdr.Compact.SensorEntityInstance
 		sensor.SensorUnit = sdr.Compact.SensorUnit
 		sensor.SensorType = sdr.Compact.SensorType
 		sensor.EventReadingType = sdr.Compact.SensorEventReadingType
 		sensor.SensorInitialization = sdr.Compact.SensorInitialization
 		sensor.SensorCapabilitites = sdr.Compact.SensorCapabilitites
+		mask = sdr.Compact.Mask
+		ownerID = sdr.Compact.GeneratorID
 
 	default:
 		return nil, fmt.Errorf("only support Full or Compact SDR record type, input is %s", sdr.RecordHeader.RecordType)
 	}
 
+	// The Settable Threshold Mask is only held in SDR,
+	// the readable masks are updated by Get Sensor Thresholds.
+	sensor.Threshold.Mask.LNR.Settable = mask.Threshold.LNR.Settable
+	sensor.Threshold.Mask.LCR.Settable = mask.Threshold.LCR.Settable
+	sensor.Threshold.Mask.LNC.Settable = mask.Threshold.LNC.Settable
+	sensor.Threshold.Mask.UNC.Settable = mask.Threshold.UNC.Settable
+	sensor.Threshold.Mask.UCR.Settable = mask.Threshold.UCR.Settable
+	sensor.Threshold.Mask.UNR.Settable = mask.Threshold.UNR.Settable
+
+	// Sensor Owner ID and Sensor Owner LUN, see 5.4 Sensor Owner Identification
+	sensor.OwnerID = uint8(ownerID)
+	sensor.OwnerChannel = uint8(ownerID>>8) >> 4
+	sensor.OwnerLUN = uint8(ownerID>>8) & 0x03
+
 	c.Debug("Sensor brief", sensor)
 
-	c.Debug("Get Sensor", fmt.Sprintf("Sensor Name: %s, Sensor Number: %#02x\n", sensor.Name, sensor.Number))
+	c.Debug("Get Sensor", fmt.Sprintf("Sensor Name: %s, Sensor Number: %#02x, Owner: %#02x, LUN: %d, Channel: %d\n",
+		sensor.Name, sensor.Number, sensor.OwnerID, sensor.OwnerLUN, sensor.OwnerChannel))
+
+	err := c.WithSensorOwner(sensor, func() error {
+		return c.fillSensor(sensor)
+	})
+	if err != nil {
+		if !sensor.ownerTarget().bridged() {
+			return nil, err
+		}
+		// A satellite controller may be absent or not reachable, which should not
+		// fail all the sensors, the error is held by the sensor.
+		sensor.Err = fmt.Errorf("sensor %s (%#02x) owned by %#02x not available, err: %w", sensor.Name, sensor.Number, sensor.OwnerID, err)
+	}
 
+	return sensor, nil
+}
+
+// WithSensorOwner makes the requests issued by fn addressed to the owner of the sensor,
+// that is the LUN of the controller which owns the sensor. The per-sensor commands
+// (eg: GetSensorReading, GetSensorEventEnable) of the sensors owned by satellite
+// controllers are bridged when issued within fn.
+func (c *Client) WithSensorOwner(sensor *Sensor, fn func() error) error {
+	t := sensor.ownerTarget()
+	return c.withTarget(t.addr, t.channel, t.lun, fn)
+}
+
+// fillSensor retrieves and fills the sensor attributes which are not stored in SDR.
+func (c *Client) fillSensor(sensor *Sensor) error {
 	if err := c.fillSensorReading(sensor); err != nil {
-		return nil, fmt.Errorf("fillSensorReading failed, err: %s", err)
+		return fmt.Errorf("fillSensorReading failed, err: %s", err)
 	}
 
 	// scanningDisabled is filled/set by fillSensorReading
 	if sensor.scanningDisabled {
 		// Sensor scanning disabled, no need to continue
 		c.Debug(fmt.Sprintf(":( Sensor [%s](%#02x) scanning disabled\n", sensor.Name, sensor.Number), "")
-		return sensor, nil
+		return nil
 	}
 
 	if !sensor.EventReadingType.IsThreshold() || !sensor.SensorUnit.IsAnalog() {
 		if err := c.fillSensorDiscrete(sensor); err != nil {
-			return nil, fmt.Errorf("fillSensorDiscrete failed, err: %s", err)
+			return fmt.Errorf("fillSensorDiscrete failed, err: %s", err)
 		}
 	} else {
 		if err := c.fillSensorThreshold(sensor); err != nil {
-			return nil, fmt.Errorf("fillSensorThreshold failed, err: %s", err)
+			return fmt.Errorf("fillSensorThreshold failed, err: %s", err)
 		}
 	}
 
-	return sensor, nil
+	return nil
 }
 
 func (c *Client) fillSensorReading(sensor *Sensor) error {
@@ -1,6 +1,9 @@
 package ipmi
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // 27.7 Get Watchdog Timer Command
 type GetWatchdogTimerRequest struct {
@@ -57,16 +60,16 @@ func (res *GetWatchdogTimerResponse) Format() string {
 Watchdog Timer Is:      %s
 Watchdog Timer Actions: %s (%#02x)
 Pre-timeout interval:   %d seconds
-Timer Expiration Flags: %#02x
-Initial Countdown:      %d sec
-Present Countdown:      %d sec`,
+Timer Expiration Flags: %#02x%s
+Initial Countdown:      %.1f sec
+Present Countdown:      %.1f sec`,
 		res.TimerUse, uint8(res.TimerUse),
 		formatBool(res.TimerIsStarted, "Started", "Stopped"),
 		res.TimeoutAction, uint8(res.TimeoutAction),
 		res.PreTimeoutIntevalSec,
-		res.ExpirationFlags,
-		res.InitialCountdown,
-		res.PresentCountdown,
+		res.ExpirationFlags, formatWatchdogExpirationFlags(res.ExpirationFlags),
+		WatchdogCountdownDuration(res.InitialCountdown).Seconds(),
+		WatchdogCountdownDuration(res.PresentCountdown).Seconds(),
 	)
 }
 
@@ -102,6 +105,40 @@ func (t TimerUse) String() string {
 	return ""
 }
 
+// WatchdogExpirationFlagsMask is the mask of valid timer use expiration flags.
+const WatchdogExpirationFlagsMask uint8 = 0x3e
+
+// WatchdogExpirationFlag returns the expiration flag bit of the timer use.
+func WatchdogExpirationFlag(timerUse TimerUse) uint8 {
+	if timerUse < TimerUseBIOSFRB2 || timerUse > TimerUseOEM {
+		return 0
+	}
+	return 1 << uint8(timerUse)
+}
+
+// ExpiredTimerUses returns the timer uses whose expiration flag is set.
+func ExpiredTimerUses(expirationFlags uint8) []TimerUse {
+	out := make([]TimerUse, 0)
+	for timerUse := TimerUseBIOSFRB2; timerUse <= TimerUseOEM; timerUse++ {
+		if expirationFlags&WatchdogExpirationFlag(timerUse) != 0 {
+			out = append(out, timerUse)
+		}
+	}
+	return out
+}
+
+func formatWatchdogExpirationFlags(expirationFlags uint8) string {
+	timerUses := ExpiredTimerUses(expirationFlags)
+	if len(timerUses) == 0 {
+		return ""
+	}
+	names := make([]string, len(timerUses))
+	for i, timerUse := range timerUses {
+		names[i] = timerUse.String()
+	}
+	return fmt.Sprintf(" (%s expired)", strings.Join(names, ", "))
+}
+
 type PreTimeoutInterrupt uint8
 
 const (
@@ -0,0 +1,51 @@
+package ipmi
+
+import (
+	"testing"
+)
+
+func Test_GetWatchdogTimerResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{
+			name: "started",
+			data: []byte{0x44, 0x21, 0x0a, 0x10, 0x58, 0x02, 0x2c, 0x01},
+			expected: `Watchdog Timer Use:     SMS/OS (0x04)
+Watchdog Timer Is:      Started
+Watchdog Timer Actions: Hard Reset (0x01)
+Pre-timeout interval:   10 seconds
+Timer Expiration Flags: 0x10 (SMS/OS expired)
+Initial Countdown:      60.0 sec
+Present Countdown:      30.0 sec`,
+		},
+		{
+			name: "stopped",
+			data: []byte{0x83, 0x00, 0x00, 0x0c, 0x64, 0x00, 0x64, 0x00},
+			expected: `Watchdog Timer Use:     OS Load (0x03)
+Watchdog Timer Is:      Stopped
+Watchdog Timer Actions: No action (0x00)
+Pre-timeout interval:   0 seconds
+Timer Expiration Flags: 0x0c (BIOS/POST, OS Load expired)
+Initial Countdown:      10.0 sec
+Present Countdown:      10.0 sec`,
+		},
+	}
+
+	for _, test := range tests {
+		res := &GetWatchdogTimerResponse{}
+		if err := res.Unpack(test.data); err != nil {
+			t.Errorf("test %s: unpack failed, err: %s", test.name, err)
+			continue
+		}
+		if got := res.Format(); got != test.expected {
+			t.Errorf("test %s not matched, got:\n%s\nexpected:\n%s", test.name, got, test.expected)
+		}
+	}
+
+	if err := (&GetWatchdogTimerResponse{}).Unpack(make([]byte, 7)); err != ErrUnpackedDataTooShort {
+		t.Errorf("unpack short data not matched, got: %v, expected: %v", err, ErrUnpackedDataTooShort)
+	}
+}
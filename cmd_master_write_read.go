@@ -37,6 +37,7 @@ func (req *MasterWriteReadRequest) Pack() []byte {
 }
 
 func (res *MasterWriteReadResponse) Unpack(msg []byte) error {
+	res.Data, _, _ = unpackBytes(msg, 0, len(msg))
 	return nil
 }
 
@@ -0,0 +1,58 @@
+package ipmi
+
+// 35.12 Re-arm Sensor Events Command
+type RearmSensorEventsRequest struct {
+	SensorNumber uint8
+
+	// RearmAll re-arms all event status of the sensor, otherwise only the Events are re-armed.
+	RearmAll bool
+	// Events are the assertion and deassertion events to re-arm,
+	// eg: SensorEvent_UCR_High_Assert, SensorEvent_State_3_Deassert.
+	Events []SensorEvent
+}
+
+type RearmSensorEventsResponse struct {
+}
+
+func (req *RearmSensorEventsRequest) Command() Command {
+	return CommandRearmSensorEvents
+}
+
+func (req *RearmSensorEventsRequest) Pack() []byte {
+	out := make([]byte, 2)
+	packUint8(req.SensorNumber, out, 0)
+
+	if req.RearmAll {
+		// [7] - 0b = re-arm all event status from this sensor
+		return out
+	}
+
+	packUint8(setBit7(0), out, 1)
+	return append(out, packSensorEvents(req.Events)...)
+}
+
+func (res *RearmSensorEventsResponse) Unpack(msg []byte) error {
+	return nil
+}
+
+func (r *RearmSensorEventsResponse) CompletionCodes() map[uint8]string {
+	return map[uint8]string{}
+}
+
+func (res *RearmSensorEventsResponse) Format() string {
+	return ""
+}
+
+// RearmSensorEvents is used to re-arm the event generation of the sensor, so a new event will
+// be generated if the event condition still exists, eg: after the events are latched by a manual re-arm sensor.
+// All event status of the sensor are re-armed if no events are specified.
+func (c *Client) RearmSensorEvents(sensorNumber uint8, events ...SensorEvent) (response *RearmSensorEventsResponse, err error) {
+	request := &RearmSensorEventsRequest{
+		SensorNumber: sensorNumber,
+		RearmAll:     len(events) == 0,
+		Events:       events,
+	}
+	response = &RearmSensorEventsResponse{}
+	err = c.Exchange(request, response)
+	return
+}
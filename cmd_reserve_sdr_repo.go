@@ -0,0 +1,45 @@
+package ipmi
+
+// 33.11 Reserve SDR Repository Command
+type ReserveSDRRepoRequest struct {
+	// empty
+}
+
+type ReserveSDRRepoResponse struct {
+	ReservationID uint16
+}
+
+func (req *ReserveSDRRepoRequest) Command() Command {
+	return CommandReserveSDRRepo
+}
+
+func (req *ReserveSDRRepoRequest) Pack() []byte {
+	return []byte{}
+}
+
+func (res *ReserveSDRRepoResponse) Unpack(msg []byte) error {
+	if len(msg) < 2 {
+		return ErrUnpackedDataTooShort
+	}
+
+	res.ReservationID, _, _ = unpackUint16L(msg, 0)
+	return nil
+}
+
+func (r *ReserveSDRRepoResponse) CompletionCodes() map[uint8]string {
+	// no command-specific cc
+	return map[uint8]string{}
+}
+
+func (res *ReserveSDRRepoResponse) Format() string {
+	return ""
+}
+
+// This command is used to obtain a Reservation ID, which is required by
+// partial reads (Get SDR with non-zero offset) of the SDR Repository.
+func (c *Client) ReserveSDRRepo() (response *ReserveSDRRepoResponse, err error) {
+	request := &ReserveSDRRepoRequest{}
+	response = &ReserveSDRRepoResponse{}
+	err = c.Exchange(request, response)
+	return
+}
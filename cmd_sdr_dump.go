@@ -0,0 +1,115 @@
+package ipmi
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// DumpSDRs writes the raw data of all SDR records to w.
+//
+// The records are written one after another without any separator,
+// each record consists of the 5 bytes record header and the record body.
+// It is the same binary format as "ipmitool sdr dump", so the output
+// can be loaded by LoadSDRs, WithSDRFile or "ipmitool -S".
+func (c *Client) DumpSDRs(w io.Writer) error {
+	sdrs, err := c.GetSDRs()
+	if err != nil {
+		return fmt.Errorf("GetSDRs failed, err: %s", err)
+	}
+
+	for _, sdr := range sdrs {
+		if _, err := w.Write(sdr.Raw()); err != nil {
+			return fmt.Errorf("write SDR record (%#04x) failed, err: %s", sdr.RecordHeader.RecordID, err)
+		}
+	}
+	return nil
+}
+
+// DumpSDRsToFile writes the raw data of all SDR records to the specified file.
+// See DumpSDRs.
+func (c *Client) DumpSDRsToFile(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("create file (%s) failed, err: %s", filename, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if err := c.DumpSDRs(w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write file (%s) failed, err: %s", filename, err)
+	}
+	return nil
+}
+
+// LoadSDRs reads and parses SDR records from r, which holds the data generated
+// by DumpSDRs or "ipmitool sdr dump".
+//
+// The dump data does not record the Next Record ID, so NextRecordID of the
+// returned SDRs is set to the Record ID of the following record, and 0xffff for the last one.
+func LoadSDRs(r io.Reader) ([]*SDR, error) {
+	const SDRRecordHeaderSize int = 5
+
+	var out = make([]*SDR, 0)
+	reader := bufio.NewReader(r)
+
+	for {
+		header := make([]byte, SDRRecordHeaderSize)
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("read SDR record header failed, err: %s", err)
+		}
+
+		// header[4] is the Record Length, the number of bytes following the header.
+		data := make([]byte, SDRRecordHeaderSize+int(header[4]))
+		copy(data, header)
+		if _, err := io.ReadFull(reader, data[SDRRecordHeaderSize:]); err != nil {
+			return nil, fmt.Errorf("read SDR record body failed, err: %s", err)
+		}
+
+		sdr, err := ParseSDR(data, 0xffff)
+		if err != nil {
+			return nil, fmt.Errorf("ParseSDR failed, err: %s", err)
+		}
+
+		if len(out) > 0 {
+			out[len(out)-1].NextRecordID = sdr.RecordHeader.RecordID
+		}
+		out = append(out, sdr)
+	}
+
+	return out, nil
+}
+
+// LoadSDRsFromFile reads and parses SDR records from the specified file.
+// See LoadSDRs.
+func LoadSDRsFromFile(filename string) ([]*SDR, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("open file (%s) failed, err: %s", filename, err)
+	}
+	defer f.Close()
+
+	return LoadSDRs(f)
+}
+
+// loadSDRFile loads the SDR records from the SDR file set by WithSDRFile.
+// The file is only read once, the loaded records are kept by the client.
+func (c *Client) loadSDRFile() ([]*SDR, error) {
+	if c.sdrFileSDRs != nil {
+		return c.sdrFileSDRs, nil
+	}
+
+	sdrs, err := LoadSDRsFromFile(c.sdrFile)
+	if err != nil {
+		return nil, fmt.Errorf("LoadSDRsFromFile failed, err: %s", err)
+	}
+	c.sdrFileSDRs = sdrs
+	return sdrs, nil
+}
@@ -0,0 +1,119 @@
+package ipmi
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_DumpSDRs_LoadSDRs(t *testing.T) {
+	records := [][]byte{
+		testCompactSDR(1, 0x01, "Temp"),
+		testCompactSDR(2, 0x02, "FAN1"),
+		testCompactSDR(3, 0x03, "CPU1 Temperature"),
+	}
+	dump := bytes.Join(records, nil)
+
+	repo := &fakeSDRRepo{records: records}
+	c := newTestLANClient(t, repo.handle)
+
+	var buf bytes.Buffer
+	if err := c.DumpSDRs(&buf); err != nil {
+		t.Fatalf("DumpSDRs failed, err: %s", err)
+	}
+	if !bytes.Equal(buf.Bytes(), dump) {
+		t.Errorf("dump not matched, got: % x, expected: % x", buf.Bytes(), dump)
+	}
+
+	tests := []struct {
+		name                 string
+		data                 []byte
+		expectedNextRecordID []uint16
+		expectedErr          bool
+	}{
+		{
+			name:                 "dump",
+			data:                 dump,
+			expectedNextRecordID: []uint16{2, 3, 0xffff},
+		},
+		{
+			name:                 "empty",
+			data:                 nil,
+			expectedNextRecordID: []uint16{},
+		},
+		{
+			name:        "truncated header",
+			data:        dump[:len(records[0])+3],
+			expectedErr: true,
+		},
+		{
+			name:        "truncated body",
+			data:        dump[:len(dump)-1],
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		sdrs, err := LoadSDRs(bytes.NewReader(test.data))
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("test %s expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %s: LoadSDRs failed, err: %s", test.name, err)
+			continue
+		}
+		if len(sdrs) != len(test.expectedNextRecordID) {
+			t.Errorf("test %s not matched, got: %d records, expected: %d", test.name, len(sdrs), len(test.expectedNextRecordID))
+			continue
+		}
+		for i, sdr := range sdrs {
+			if !bytes.Equal(sdr.Raw(), records[i]) {
+				t.Errorf("test %s record %d not matched, got: % x, expected: % x", test.name, i, sdr.Raw(), records[i])
+			}
+			if sdr.NextRecordID != test.expectedNextRecordID[i] {
+				t.Errorf("test %s record %d next record id not matched, got: %#04x, expected: %#04x", test.name, i, sdr.NextRecordID, test.expectedNextRecordID[i])
+			}
+		}
+	}
+}
+
+func Test_WithSDRFile(t *testing.T) {
+	records := [][]byte{
+		testCompactSDR(1, 0x01, "Temp"),
+		testCompactSDR(2, 0x02, "FAN1"),
+	}
+	filename := filepath.Join(t.TempDir(), "sdr.dump")
+	if err := os.WriteFile(filename, bytes.Join(records, nil), 0644); err != nil {
+		t.Fatalf("write sdr file failed, err: %s", err)
+	}
+
+	// the BMC has no SDRs, they must be loaded from the file
+	requests := 0
+	c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+		requests++
+		return 0xc1, nil
+	})
+	c.WithSDRFile(filename)
+
+	sdrMap, err := c.GetSDRsMap()
+	if err != nil {
+		t.Fatalf("GetSDRsMap failed, err: %s", err)
+	}
+	expected := map[SensorNumber]string{0x01: "Temp", 0x02: "FAN1"}
+	sdrs := sdrMap[GeneratorID(BMC_SA)]
+	if len(sdrMap) != 1 || len(sdrs) != len(expected) {
+		t.Fatalf("test sdr map not matched, got: %v, expected: %v", sdrMap, expected)
+	}
+	for number, name := range expected {
+		if sdr, ok := sdrs[number]; !ok || sdr.SensorName() != name {
+			t.Errorf("test sensor %#02x not matched, got: %v, expected: %s", number, sdr, name)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("test requests not matched, got: %d, expected: 0", requests)
+	}
+}
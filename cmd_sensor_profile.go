@@ -0,0 +1,357 @@
+package ipmi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffSensorProfile compares the sensor profile with the live values of the sensors,
+// and returns the changes needed for the sensors matched by the profile.
+//
+// It refuses (returns error) the profile if any desired setting is not settable,
+// or results in thresholds not in the proper order, see SetSensorThresholds.
+// A matched sensor which can not be read (see Sensor.Err) does not refuse the profile,
+// it is returned as a diff with Err set, and skipped by ApplySensorProfile.
+func (c *Client) DiffSensorProfile(profile *SensorProfile) ([]*SensorProfileDiff, error) {
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+
+	sensors, err := c.GetSensors()
+	if err != nil {
+		return nil, fmt.Errorf("GetSensors failed, err: %s", err)
+	}
+
+	out := make([]*SensorProfileDiff, 0)
+	for _, sensor := range sensors {
+		desired, ok := profile.desired(sensor.Name)
+		if !ok {
+			continue
+		}
+		if sensor.Err != nil {
+			out = append(out, &SensorProfileDiff{Sensor: sensor, Err: sensor.Err})
+			continue
+		}
+
+		var diff *SensorProfileDiff
+		err := c.WithSensorOwner(sensor, func() (err error) {
+			diff, err = c.diffSensorProfile(sensor, desired)
+			return
+		})
+		if err != nil {
+			return nil, fmt.Errorf("sensor %s (%#02x): %s", sensor.Name, sensor.Number, err)
+		}
+		if len(diff.Changes) > 0 {
+			out = append(out, diff)
+		}
+	}
+
+	return out, nil
+}
+
+// ApplySensorProfile applies the changes returned by DiffSensorProfile.
+// The diffs with Err set are skipped.
+func (c *Client) ApplySensorProfile(diffs []*SensorProfileDiff) error {
+	for _, diff := range diffs {
+		if diff.Err != nil {
+			continue
+		}
+		if err := c.WithSensorOwner(diff.Sensor, func() error {
+			return c.applySensorProfileDiff(diff)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) applySensorProfileDiff(diff *SensorProfileDiff) error {
+	sensor := diff.Sensor
+
+	if len(diff.thresholds) > 0 {
+		request, err := c.sensorThresholdsRequest(sensor, diff.thresholds)
+		if err != nil {
+			return fmt.Errorf("sensor %s (%#02x): %s", sensor.Name, sensor.Number, err)
+		}
+		if _, err := c.SetSensorThresholds(request); err != nil {
+			return fmt.Errorf("SetSensorThresholds for sensor %s (%#02x) failed, err: %s", sensor.Name, sensor.Number, err)
+		}
+	}
+
+	if diff.hysteresis {
+		if _, err := c.SetSensorHysteresis(sensor.Number, diff.positiveHysteresisRaw, diff.negativeHysteresisRaw); err != nil {
+			return fmt.Errorf("SetSensorHysteresis for sensor %s (%#02x) failed, err: %s", sensor.Name, sensor.Number, err)
+		}
+	}
+
+	if diff.eventEnable {
+		requests := make([]*SetSensorEventEnableRequest, 0)
+
+		// the individual events are enabled and disabled by separate requests
+		sensorClass := SensorClassDiscrete
+		if sensor.IsThreshold() {
+			sensorClass = SensorClassThreshold
+		}
+		for _, op := range []struct {
+			operation       SensorEventEnableOperation
+			assertionMask   uint16
+			deassertionMask uint16
+		}{
+			{SensorEventEnableOperation_Enable, diff.assertionEventMask &^ diff.currentAssertionEventMask, diff.deassertionEventMask &^ diff.currentDeassertionEventMask},
+			{SensorEventEnableOperation_Disable, diff.currentAssertionEventMask &^ diff.assertionEventMask, diff.currentDeassertionEventMask &^ diff.deassertionEventMask},
+		} {
+			if !diff.eventMasks || op.assertionMask == 0 && op.deassertionMask == 0 {
+				continue
+			}
+			requests = append(requests, &SetSensorEventEnableRequest{
+				Operation: op.operation,
+				Events: append(SensorEventsOfMask(op.assertionMask, true, sensorClass),
+					SensorEventsOfMask(op.deassertionMask, false, sensorClass)...),
+			})
+		}
+		if len(requests) == 0 {
+			requests = append(requests, &SetSensorEventEnableRequest{})
+		}
+
+		for _, request := range requests {
+			request.SensorNumber = sensor.Number
+			request.EventMessagesEnabled = diff.eventMessagesEnabled
+			request.SensorScanningEnabled = diff.sensorScanningEnabled
+			if _, err := c.SetSensorEventEnable(request); err != nil {
+				return fmt.Errorf("SetSensorEventEnable for sensor %s (%#02x) failed, err: %s", sensor.Name, sensor.Number, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) diffSensorProfile(sensor *Sensor, desired SensorProfileEntry) (*SensorProfileDiff, error) {
+	diff := &SensorProfileDiff{
+		Sensor:     sensor,
+		thresholds: map[SensorThresholdType]float64{},
+	}
+
+	if len(desired.Thresholds) > 0 || desired.PositiveHysteresis != nil || desired.NegativeHysteresis != nil {
+		if !sensor.IsThreshold() {
+			return nil, fmt.Errorf("thresholds and hysteresis are only for threshold sensors")
+		}
+	}
+
+	if len(desired.Thresholds) > 0 {
+		if err := c.diffSensorThresholds(diff, desired.Thresholds); err != nil {
+			return nil, err
+		}
+	}
+
+	if desired.PositiveHysteresis != nil || desired.NegativeHysteresis != nil {
+		if err := diffSensorHysteresis(diff, desired.PositiveHysteresis, desired.NegativeHysteresis); err != nil {
+			return nil, err
+		}
+	}
+
+	if desired.EventMessages != nil || desired.SensorScanning != nil || desired.AssertionEvents != nil || desired.DeassertionEvents != nil {
+		if err := c.diffSensorEventEnable(diff, desired); err != nil {
+			return nil, err
+		}
+	}
+
+	return diff, nil
+}
+
+func (c *Client) diffSensorThresholds(diff *SensorProfileDiff, desired map[string]float64) error {
+	sensor := diff.Sensor
+
+	// the thresholds after the changes applied, used to check the order
+	final := map[SensorThresholdType]float64{}
+	for _, t := range sensorThresholdTypesOrdered {
+		if sensor.IsThresholdReadable(t) {
+			final[t] = sensor.thresholdValue(t)
+		}
+	}
+
+	for _, t := range sensorThresholdTypesOrdered {
+		value, ok := desired[t.Abbr()]
+		if !ok {
+			continue
+		}
+		final[t] = value
+
+		raw, err := c.convertSensorValueToRaw(sensor, value)
+		if err != nil {
+			return fmt.Errorf("convert threshold %s value failed, err: %s", t.Abbr(), err)
+		}
+		if sensor.IsThresholdReadable(t) && raw == sensor.thresholdRaw(t) {
+			continue
+		}
+
+		if sensor.SensorCapabilitites.ThresholdAccess != SensorThresholdAccess_ReadableSettable {
+			return fmt.Errorf("thresholds are not settable, threshold access: %s", sensor.SensorCapabilitites.ThresholdAccess)
+		}
+		if !sensor.Threshold.Mask.IsThresholdSettable(t) {
+			return fmt.Errorf("threshold %s is not settable", t.Abbr())
+		}
+
+		diff.thresholds[t] = value
+		diff.Changes = append(diff.Changes, SensorProfileChange{
+			Setting: t.Abbr(),
+			Current: sensor.ThresholdStr(t),
+			Desired: fmt.Sprintf("%.3f", value),
+		})
+	}
+
+	if err := checkSensorThresholdsOrder(final); err != nil {
+		return fmt.Errorf("thresholds not in order, %s", err)
+	}
+	return nil
+}
+
+func diffSensorHysteresis(diff *SensorProfileDiff, positive *float64, negative *float64) error {
+	sensor := diff.Sensor
+
+	access := sensor.SensorCapabilitites.HysteresisAccess
+	readable := access == SensorHysteresisAccess_Readable || access == SensorHysteresisAccess_ReadableSettable
+
+	positiveRaw, negativeRaw := sensor.Threshold.PositiveHysteresisRaw, sensor.Threshold.NegativeHysteresisRaw
+
+	var changes []SensorProfileChange
+	for _, h := range []struct {
+		setting string
+		desired *float64
+		raw     *uint8
+	}{
+		{"positive_hysteresis", positive, &positiveRaw},
+		{"negative_hysteresis", negative, &negativeRaw},
+	} {
+		if h.desired == nil {
+			continue
+		}
+		raw, err := sensor.ConvertSensorHysteresisToRaw(*h.desired)
+		if err != nil {
+			return fmt.Errorf("convert %s value failed, err: %s", h.setting, err)
+		}
+		if readable && raw == *h.raw {
+			continue
+		}
+		changes = append(changes, SensorProfileChange{
+			Setting: h.setting,
+			Current: sensor.HysteresisStr(*h.raw),
+			Desired: fmt.Sprintf("%.3f", *h.desired),
+		})
+		*h.raw = raw
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+	if access != SensorHysteresisAccess_ReadableSettable {
+		return fmt.Errorf("hysteresis is not settable, hysteresis access: %s", access)
+	}
+
+	diff.hysteresis = true
+	diff.positiveHysteresisRaw = positiveRaw
+	diff.negativeHysteresisRaw = negativeRaw
+	diff.Changes = append(diff.Changes, changes...)
+	return nil
+}
+
+func (c *Client) diffSensorEventEnable(diff *SensorProfileDiff, desired SensorProfileEntry) error {
+	sensor := diff.Sensor
+	eventMessages, sensorScanning := desired.EventMessages, desired.SensorScanning
+
+	res, err := c.GetSensorEventEnable(sensor.Number)
+	if err != nil {
+		return fmt.Errorf("GetSensorEventEnable failed, err: %s", err)
+	}
+
+	diff.eventMessagesEnabled = !res.EventMessagesDisabled
+	diff.sensorScanningEnabled = !res.SensorScanningDisabled
+	diff.currentAssertionEventMask = res.AssertionEventMask
+	diff.currentDeassertionEventMask = res.DeassertionEventMask
+	diff.assertionEventMask = res.AssertionEventMask
+	diff.deassertionEventMask = res.DeassertionEventMask
+
+	if eventMessages != nil && *eventMessages != diff.eventMessagesEnabled {
+		diff.Changes = append(diff.Changes, SensorProfileChange{
+			Setting: "event_messages",
+			Current: formatBool(diff.eventMessagesEnabled, "enabled", "disabled"),
+			Desired: formatBool(*eventMessages, "enabled", "disabled"),
+		})
+		diff.eventMessagesEnabled = *eventMessages
+		diff.eventEnable = true
+	}
+
+	if sensorScanning != nil && *sensorScanning != diff.sensorScanningEnabled {
+		diff.Changes = append(diff.Changes, SensorProfileChange{
+			Setting: "sensor_scanning",
+			Current: formatBool(diff.sensorScanningEnabled, "enabled", "disabled"),
+			Desired: formatBool(*sensorScanning, "enabled", "disabled"),
+		})
+		diff.sensorScanningEnabled = *sensorScanning
+		diff.eventEnable = true
+	}
+
+	sensorClass := SensorClassDiscrete
+	if sensor.IsThreshold() {
+		sensorClass = SensorClassThreshold
+	}
+	for _, m := range []struct {
+		setting string
+		events  []string
+		assert  bool
+		mask    *uint16
+	}{
+		{"assertion_events", desired.AssertionEvents, true, &diff.assertionEventMask},
+		{"deassertion_events", desired.DeassertionEvents, false, &diff.deassertionEventMask},
+	} {
+		if m.events == nil {
+			continue
+		}
+		events, err := parseSensorEvents(m.events, m.assert)
+		if err != nil {
+			return fmt.Errorf("invalid %s, err: %s", m.setting, err)
+		}
+		for _, event := range events {
+			if event.SensorClass != sensorClass {
+				return fmt.Errorf("invalid %s, event %s is not a %s event", m.setting, event, sensorClass)
+			}
+		}
+
+		var mask uint16
+		masks := packSensorEvents(events)
+		if m.assert {
+			mask, _, _ = unpackUint16L(masks, 0)
+		} else {
+			mask, _, _ = unpackUint16L(masks, 2)
+		}
+		if mask == *m.mask {
+			continue
+		}
+		if control := sensor.SensorCapabilitites.EventMessageControl; control != SensorEventMessageControl_PerThresholdState {
+			return fmt.Errorf("%s are not settable, event message control: %s", m.setting, control)
+		}
+
+		diff.Changes = append(diff.Changes, SensorProfileChange{
+			Setting: m.setting,
+			Current: formatSensorEvents(SensorEventsOfMask(*m.mask, m.assert, sensorClass)),
+			Desired: formatSensorEvents(SensorEventsOfMask(mask, m.assert, sensorClass)),
+		})
+		*m.mask = mask
+		diff.eventMasks = true
+		diff.eventEnable = true
+	}
+
+	return nil
+}
+
+func formatSensorEvents(events []SensorEvent) string {
+	if len(events) == 0 {
+		return "none"
+	}
+	out := make([]string, 0, len(events))
+	for _, event := range events {
+		out = append(out, event.String())
+	}
+	return strings.Join(out, " ")
+}
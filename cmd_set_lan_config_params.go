@@ -2,7 +2,7 @@ package ipmi
 
 // 23.1 Set LAN Configuration Parameters Command
 type SetLanConfigParamsRequest struct {
-	ChannelNumber int8
+	ChannelNumber uint8
 	ParamSelector LanParamSelector
 	ConfigData    []byte
 }
@@ -12,7 +12,11 @@ type SetLanConfigParamsResponse struct {
 }
 
 func (req *SetLanConfigParamsRequest) Pack() []byte {
-	return []byte{}
+	out := make([]byte, 2+len(req.ConfigData))
+	packUint8(req.ChannelNumber&0x0f, out, 0)
+	packUint8(uint8(req.ParamSelector), out, 1)
+	packBytes(req.ConfigData, out, 2)
+	return out
 }
 
 func (req *SetLanConfigParamsRequest) Command() Command {
@@ -36,9 +40,14 @@ func (res *SetLanConfigParamsResponse) Format() string {
 	return ""
 }
 
-// Todo
-func (c *Client) SetLanConfigParams() (response *SetLanConfigParamsResponse, err error) {
-	request := &SetLanConfigParamsRequest{}
+// SetLanConfigParams sets the raw config data of the LAN parameter.
+// See the typed SetLanXXX methods for the commonly used parameters.
+func (c *Client) SetLanConfigParams(channelNumber uint8, paramSelector LanParamSelector, configData []byte) (response *SetLanConfigParamsResponse, err error) {
+	request := &SetLanConfigParamsRequest{
+		ChannelNumber: channelNumber,
+		ParamSelector: paramSelector,
+		ConfigData:    configData,
+	}
 	response = &SetLanConfigParamsResponse{}
 	err = c.Exchange(request, response)
 	return
@@ -0,0 +1,290 @@
+package ipmi
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// SetLanSetInProgress sets the Set In Progress parameter (#0) of the channel.
+func (c *Client) SetLanSetInProgress(channelNumber uint8, state SetInProgress) error {
+	if _, err := c.SetLanConfigParams(channelNumber, LanParam_SetInProgress, []byte{uint8(state)}); err != nil {
+		return fmt.Errorf("SetLanConfigParams (%s) failed, err: %w", LanParam_SetInProgress, err)
+	}
+	return nil
+}
+
+// UpdateLanConfig runs the update (which calls the SetLanXXX methods) wrapped in the
+// set-in-progress protocol: mark "set in progress" before the update, then "commit write"
+// and "set complete" after the update.
+//
+// The "set in progress" and "commit write" states are optional, so they are
+// skipped if the BMC reports the parameter is not supported.
+func (c *Client) UpdateLanConfig(channelNumber uint8, update func() error) error {
+	if err := c.SetLanSetInProgress(channelNumber, SetInProgressSetInProgress); err != nil {
+		if isResponseErrorCC(err, 0x81) {
+			return fmt.Errorf("another set is in progress on channel (%d), err: %w", channelNumber, err)
+		}
+		if !isResponseErrorCC(err, 0x80) {
+			return err
+		}
+		c.Debug("Set In Progress not supported, update directly", err)
+	}
+
+	updateErr := update()
+	if updateErr == nil {
+		if err := c.SetLanSetInProgress(channelNumber, SetInProgressCommitWrite); err != nil {
+			c.Debug("Commit Write failed", err)
+		}
+	}
+
+	if err := c.SetLanSetInProgress(channelNumber, SetInProgressSetComplete); err != nil && !isResponseErrorCC(err, 0x80) {
+		if updateErr == nil {
+			return err
+		}
+		c.Debug("Set Complete failed", err)
+	}
+
+	return updateErr
+}
+
+func (c *Client) setLanConfigParam(channelNumber uint8, paramSelector LanParamSelector, configData []byte) error {
+	if _, err := c.SetLanConfigParams(channelNumber, paramSelector, configData); err != nil {
+		return fmt.Errorf("SetLanConfigParams (%s) failed, err: %w", paramSelector, err)
+	}
+	return nil
+}
+
+func (c *Client) SetLanIPSource(channelNumber uint8, ipSource IPAddressSource) error {
+	if ipSource > IPAddressSourceOther {
+		return fmt.Errorf("invalid ip address source (%d)", ipSource)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_IPSource, []byte{uint8(ipSource)})
+}
+
+func (c *Client) SetLanIP(channelNumber uint8, ip net.IP) error {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return fmt.Errorf("invalid ipv4 address (%s)", ip)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_IP, ip4)
+}
+
+func (c *Client) SetLanSubnetMask(channelNumber uint8, mask net.IP) error {
+	mask4 := mask.To4()
+	if mask4 == nil {
+		return fmt.Errorf("invalid subnet mask (%s)", mask)
+	}
+	if ones, bits := net.IPMask(mask4).Size(); ones == 0 && bits == 0 {
+		return fmt.Errorf("invalid subnet mask (%s), not in canonical form", mask)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_SubnetMask, mask4)
+}
+
+func (c *Client) SetLanDefaultGatewayIP(channelNumber uint8, ip net.IP) error {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return fmt.Errorf("invalid ipv4 address (%s)", ip)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_DefaultGatewayIP, ip4)
+}
+
+func (c *Client) SetLanDefaultGatewayMAC(channelNumber uint8, mac net.HardwareAddr) error {
+	if len(mac) != 6 {
+		return fmt.Errorf("invalid mac address (%s)", mac)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_DefaultGatewayMAC, mac)
+}
+
+func (c *Client) SetLanBackupGatewayIP(channelNumber uint8, ip net.IP) error {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return fmt.Errorf("invalid ipv4 address (%s)", ip)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_BackupGatewayIP, ip4)
+}
+
+func (c *Client) SetLanBackupGatewayMAC(channelNumber uint8, mac net.HardwareAddr) error {
+	if len(mac) != 6 {
+		return fmt.Errorf("invalid mac address (%s)", mac)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_BackupGatewayMAC, mac)
+}
+
+func (c *Client) SetLanARPControl(channelNumber uint8, arpControl ARPControl) error {
+	return c.setLanConfigParam(channelNumber, LanParam_ARPControl, arpControl.Pack())
+}
+
+// SetLanVLANID enables the 802.1q VLAN with the id (1 - 4094), or disables the VLAN if enabled is false.
+func (c *Client) SetLanVLANID(channelNumber uint8, enabled bool, id uint16) error {
+	if enabled && (id < 1 || id > 4094) {
+		return fmt.Errorf("invalid vlan id (%d), must be in range 1-4094", id)
+	}
+
+	out := make([]byte, 2)
+	if enabled {
+		out[0] = uint8(id & 0xff)
+		out[1] = setBit7(uint8(id>>8) & 0x0f)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_VLANID, out)
+}
+
+func (c *Client) SetLanVLANPriority(channelNumber uint8, priority uint8) error {
+	if priority > 7 {
+		return fmt.Errorf("invalid vlan priority (%d), must be in range 0-7", priority)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_VLANPriority, []byte{priority})
+}
+
+func (c *Client) SetLanCommunityString(channelNumber uint8, community string) error {
+	if len(community) > 18 {
+		return fmt.Errorf("community string too long, max 18 bytes")
+	}
+	communityString := NewCommunityString(community)
+	return c.setLanConfigParam(channelNumber, LanParam_CommunityString, communityString[:])
+}
+
+func (c *Client) SetLanAlertDestinationType(channelNumber uint8, destinationType *AlertDestinationType) error {
+	if destinationType.SetSelector > 0x0f {
+		return fmt.Errorf("invalid alert destination (%d), must be in range 0-15", destinationType.SetSelector)
+	}
+	if destinationType.Retries > 7 {
+		return fmt.Errorf("invalid alert retries (%d), must be in range 0-7", destinationType.Retries)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_AlertDestinationType, destinationType.Pack())
+}
+
+func (c *Client) SetLanAlertDestinationAddress(channelNumber uint8, destinationAddress *AlertDestinationAddress) error {
+	if destinationAddress.SetSelector > 0x0f {
+		return fmt.Errorf("invalid alert destination (%d), must be in range 0-15", destinationAddress.SetSelector)
+	}
+	switch destinationAddress.AddressFormat {
+	case 0x00:
+		if destinationAddress.IP4IP.To4() == nil {
+			return fmt.Errorf("invalid ipv4 address (%s)", destinationAddress.IP4IP)
+		}
+		if len(destinationAddress.IP4MAC) != 6 {
+			return fmt.Errorf("invalid mac address (%s)", destinationAddress.IP4MAC)
+		}
+	case 0x01:
+		if destinationAddress.IP6IP.To16() == nil {
+			return fmt.Errorf("invalid ipv6 address (%s)", destinationAddress.IP6IP)
+		}
+	default:
+		return fmt.Errorf("invalid address format (%d)", destinationAddress.AddressFormat)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_AlertDestinationAddress, destinationAddress.Pack())
+}
+
+// SetLanCipherSuitePrivilegeLevels sets the maximum privilege levels of the cipher suites.
+// The index of levels is the Cipher Suite Entry index (0 - 15, not the Cipher Suite ID),
+// the levels of missing entries are set to PrivilegeLevelUnspecified (means unused).
+func (c *Client) SetLanCipherSuitePrivilegeLevels(channelNumber uint8, levels []PrivilegeLevel) error {
+	if len(levels) > 16 {
+		return fmt.Errorf("too many cipher suite privilege levels (%d), max 16", len(levels))
+	}
+
+	// first byte is reserved
+	out := make([]byte, 9)
+	for i, level := range levels {
+		if level > PrivilegeLevelOEM {
+			return fmt.Errorf("invalid privilege level (%d) for cipher suite entry (%d)", level, i)
+		}
+		if i%2 == 0 {
+			out[1+i/2] |= uint8(level)
+		} else {
+			out[1+i/2] |= uint8(level) << 4
+		}
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_CihperSuitePrivilegeLevels, out)
+}
+
+func (c *Client) SetLanIP6Enables(channelNumber uint8, enables IP6Enables) error {
+	if enables > IP6EnablesIP4AndIP6 {
+		return fmt.Errorf("invalid ipv6 enables (%d)", enables)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_IP6Enables, []byte{uint8(enables)})
+}
+
+func (c *Client) SetLanIP6StaticTrafficClass(channelNumber uint8, trafficClass uint8) error {
+	return c.setLanConfigParam(channelNumber, LanParam_IP6StaticTrafficClass, []byte{trafficClass})
+}
+
+func (c *Client) SetLanIP6StaticHopLimit(channelNumber uint8, hopLimit uint8) error {
+	return c.setLanConfigParam(channelNumber, LanParam_IP6StaticHopLimit, []byte{hopLimit})
+}
+
+// SetLanIP6FlowLabel sets the 20-bit IPv6 flow label.
+func (c *Client) SetLanIP6FlowLabel(channelNumber uint8, flowLabel uint32) error {
+	if flowLabel > 0xfffff {
+		return fmt.Errorf("invalid ipv6 flow label (%d), must be 20-bit", flowLabel)
+	}
+	out := []byte{uint8(flowLabel >> 16), uint8(flowLabel >> 8), uint8(flowLabel)}
+	return c.setLanConfigParam(channelNumber, LanParam_IP6FlowLabel, out)
+}
+
+func (c *Client) SetLanIP6StaticAddress(channelNumber uint8, address *IP6Address) error {
+	if address.Address.To16() == nil || address.Address.To4() != nil {
+		return fmt.Errorf("invalid ipv6 address (%s)", address.Address)
+	}
+	if address.PrefixLength > 128 {
+		return fmt.Errorf("invalid ipv6 prefix length (%d), must be in range 0-128", address.PrefixLength)
+	}
+	return c.setLanConfigParam(channelNumber, LanParam_IP6StaticAddr, address.Pack())
+}
+
+func (c *Client) SetLanIP6RouterConfig(channelNumber uint8, routerConfig IP6RouterConfig) error {
+	return c.setLanConfigParam(channelNumber, LanParam_IP6RouterAddressConfigControl, routerConfig.Pack())
+}
+
+// SetLanIP6StaticRouter sets the IP, MAC, prefix length and prefix value of
+// the static router 1 or 2.
+func (c *Client) SetLanIP6StaticRouter(channelNumber uint8, index uint8, router *IP6Router) error {
+	var ipParam, macParam, prefixLengthParam, prefixValueParam LanParamSelector
+	switch index {
+	case 1:
+		ipParam = LanParam_IP6StaticRouter1IP
+		macParam = LanParam_IP6StaticRouter1MAC
+		prefixLengthParam = LanParam_IP6StaticRouter1PrefixLength
+		prefixValueParam = LanParam_IP6StaticRouter1PrefixValue
+	case 2:
+		ipParam = LanParam_IP6StaticRouter2IP
+		macParam = LanParam_IP6StaticRouter2MAC
+		prefixLengthParam = LanParam_IP6StaticRouter2PrefixLength
+		prefixValueParam = LanParam_IP6StaticRouter2PrefixValue
+	default:
+		return fmt.Errorf("invalid ipv6 static router (%d), must be 1 or 2", index)
+	}
+
+	ip := router.IP.To16()
+	if ip == nil {
+		return fmt.Errorf("invalid ipv6 address (%s)", router.IP)
+	}
+	if len(router.MAC) != 6 {
+		return fmt.Errorf("invalid mac address (%s)", router.MAC)
+	}
+	if router.PrefixLength > 128 {
+		return fmt.Errorf("invalid ipv6 prefix length (%d), must be in range 0-128", router.PrefixLength)
+	}
+	prefix := router.PrefixValue.To16()
+	if prefix == nil {
+		prefix = net.IPv6zero
+	}
+
+	if err := c.setLanConfigParam(channelNumber, ipParam, ip); err != nil {
+		return err
+	}
+	if err := c.setLanConfigParam(channelNumber, macParam, router.MAC); err != nil {
+		return err
+	}
+	if err := c.setLanConfigParam(channelNumber, prefixLengthParam, []byte{router.PrefixLength}); err != nil {
+		return err
+	}
+	return c.setLanConfigParam(channelNumber, prefixValueParam, prefix)
+}
+
+// isResponseErrorCC checks whether err is (or wraps) a ResponseError with the completion code.
+func isResponseErrorCC(err error, cc uint8) bool {
+	var resErr *ResponseError
+	return errors.As(err, &resErr) && uint8(resErr.CompletionCode()) == cc
+}
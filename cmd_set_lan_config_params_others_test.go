@@ -0,0 +1,85 @@
+package ipmi
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_UpdateLanConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		// completion codes replied to Set In Progress: set in progress, commit write, set complete
+		setInProgressCC [3]uint8
+		expectedStates  []SetInProgress
+		expectedUpdated bool
+		expectedErr     string
+	}{
+		{
+			name:            "supported",
+			expectedStates:  []SetInProgress{SetInProgressSetInProgress, SetInProgressCommitWrite, SetInProgressSetComplete},
+			expectedUpdated: true,
+		},
+		{
+			name:            "not supported",
+			setInProgressCC: [3]uint8{0x80, 0x80, 0x80},
+			expectedStates:  []SetInProgress{SetInProgressSetInProgress, SetInProgressCommitWrite, SetInProgressSetComplete},
+			expectedUpdated: true,
+		},
+		{
+			name:            "set in progress by another",
+			setInProgressCC: [3]uint8{0x81, 0x00, 0x00},
+			expectedStates:  []SetInProgress{SetInProgressSetInProgress},
+			expectedUpdated: false,
+			expectedErr:     "another set is in progress",
+		},
+	}
+
+	for _, test := range tests {
+		var states []SetInProgress
+		var updated bool
+
+		c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+			if req.Cmd != CommandSetLanConfigParams.ID || len(req.Data) < 3 {
+				return 0xc1, nil
+			}
+			if LanParamSelector(req.Data[1]) != LanParam_SetInProgress {
+				updated = true
+				return 0x00, nil
+			}
+			state := SetInProgress(req.Data[2])
+			states = append(states, state)
+			switch state {
+			case SetInProgressSetInProgress:
+				return test.setInProgressCC[0], nil
+			case SetInProgressCommitWrite:
+				return test.setInProgressCC[1], nil
+			default:
+				return test.setInProgressCC[2], nil
+			}
+		})
+
+		err := c.UpdateLanConfig(1, func() error {
+			return c.SetLanIPSource(1, IPAddressSourceStatic)
+		})
+
+		if test.expectedErr == "" && err != nil {
+			t.Errorf("test %s: UpdateLanConfig failed, err: %s", test.name, err)
+		}
+		if test.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), test.expectedErr)) {
+			t.Errorf("test %s: error not matched, got: %v, expected: %s", test.name, err, test.expectedErr)
+		}
+		if updated != test.expectedUpdated {
+			t.Errorf("test %s: updated not matched, got: %v, expected: %v", test.name, updated, test.expectedUpdated)
+		}
+		if len(states) != len(test.expectedStates) {
+			t.Errorf("test %s: states not matched, got: %v, expected: %v", test.name, states, test.expectedStates)
+			continue
+		}
+		for i := range states {
+			if states[i] != test.expectedStates[i] {
+				t.Errorf("test %s: states not matched, got: %v, expected: %v", test.name, states, test.expectedStates)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package ipmi
+
+import "fmt"
+
+// SensorEventEnableOperation selects how the individual event enables are changed
+// by Set Sensor Event Enable command.
+type SensorEventEnableOperation uint8
+
+const (
+	// do not change individual enables
+	SensorEventEnableOperation_NoChange SensorEventEnableOperation = 0
+	// enable selected event messages
+	SensorEventEnableOperation_Enable SensorEventEnableOperation = 1
+	// disable selected event messages
+	SensorEventEnableOperation_Disable SensorEventEnableOperation = 2
+)
+
+func (op SensorEventEnableOperation) String() string {
+	switch op {
+	case 0:
+		return "no change"
+	case 1:
+		return "enable selected"
+	case 2:
+		return "disable selected"
+	default:
+		return ""
+	}
+}
+
+// 35.10 Set Sensor Event Enable Command
+//
+// The EventMessagesEnabled and SensorScanningEnabled are always sent, so a request
+// which only sets the Operation and Events (leaving them false) also disables all
+// event messages and the scanning of the sensor. Fill them with the current values
+// (see GetSensorEventEnable), or use UpdateSensorEventEnable which does it.
+type SetSensorEventEnableRequest struct {
+	SensorNumber uint8
+
+	EventMessagesEnabled  bool
+	SensorScanningEnabled bool
+
+	Operation SensorEventEnableOperation
+	// Events are the assertion and deassertion events to be enabled or disabled per Operation,
+	// eg: SensorEvent_UCR_High_Assert, SensorEvent_State_3_Deassert.
+	// It is ignored for SensorEventEnableOperation_NoChange.
+	Events []SensorEvent
+}
+
+type SetSensorEventEnableResponse struct {
+}
+
+func (req *SetSensorEventEnableRequest) Command() Command {
+	return CommandSetSensorEventEnable
+}
+
+func (req *SetSensorEventEnableRequest) Pack() []byte {
+	out := make([]byte, 2)
+	packUint8(req.SensorNumber, out, 0)
+
+	var b uint8
+	if req.EventMessagesEnabled {
+		b = setBit7(b)
+	}
+	if req.SensorScanningEnabled {
+		b = setBit6(b)
+	}
+	b |= uint8(req.Operation&0x03) << 4
+	packUint8(b, out, 1)
+
+	if req.Operation == SensorEventEnableOperation_NoChange {
+		return out
+	}
+	return append(out, packSensorEvents(req.Events)...)
+}
+
+func (res *SetSensorEventEnableResponse) Unpack(msg []byte) error {
+	return nil
+}
+
+func (r *SetSensorEventEnableResponse) CompletionCodes() map[uint8]string {
+	return map[uint8]string{}
+}
+
+func (res *SetSensorEventEnableResponse) Format() string {
+	return ""
+}
+
+// SetSensorEventEnable is used to enable or disable the event messages and scanning of the sensor,
+// and to enable or disable the individual assertion and deassertion events.
+//
+// The request overwrites the event messages and scanning enables, see SetSensorEventEnableRequest.
+func (c *Client) SetSensorEventEnable(request *SetSensorEventEnableRequest) (response *SetSensorEventEnableResponse, err error) {
+	response = &SetSensorEventEnableResponse{}
+	err = c.Exchange(request, response)
+	return
+}
+
+// SensorEventEnableUpdate holds the changes of the event enables of a sensor,
+// the nil fields are kept as they are.
+type SensorEventEnableUpdate struct {
+	EventMessagesEnabled  *bool
+	SensorScanningEnabled *bool
+
+	// the assertion and deassertion events to be enabled or disabled
+	Enable  []SensorEvent
+	Disable []SensorEvent
+}
+
+// UpdateSensorEventEnable reads the current event enables of the sensor and applies the update,
+// so the event messages and scanning enables not in the update are kept.
+// The enable and disable of the individual events are sent in separate requests.
+//
+// The requests are sent to the current target, for a sensor owned by a satellite
+// controller, call it within WithSensorOwner.
+func (c *Client) UpdateSensorEventEnable(sensorNumber uint8, update SensorEventEnableUpdate) error {
+	current, err := c.GetSensorEventEnable(sensorNumber)
+	if err != nil {
+		return fmt.Errorf("GetSensorEventEnable failed, err: %s", err)
+	}
+
+	request := &SetSensorEventEnableRequest{
+		SensorNumber:          sensorNumber,
+		EventMessagesEnabled:  !current.EventMessagesDisabled,
+		SensorScanningEnabled: !current.SensorScanningDisabled,
+	}
+	if update.EventMessagesEnabled != nil {
+		request.EventMessagesEnabled = *update.EventMessagesEnabled
+	}
+	if update.SensorScanningEnabled != nil {
+		request.SensorScanningEnabled = *update.SensorScanningEnabled
+	}
+
+	if len(update.Enable) == 0 && len(update.Disable) == 0 {
+		if _, err := c.SetSensorEventEnable(request); err != nil {
+			return fmt.Errorf("SetSensorEventEnable failed, err: %s", err)
+		}
+		return nil
+	}
+	if len(update.Enable) > 0 {
+		request.Operation = SensorEventEnableOperation_Enable
+		request.Events = update.Enable
+		if _, err := c.SetSensorEventEnable(request); err != nil {
+			return fmt.Errorf("SetSensorEventEnable failed, err: %s", err)
+		}
+	}
+	if len(update.Disable) > 0 {
+		request.Operation = SensorEventEnableOperation_Disable
+		request.Events = update.Disable
+		if _, err := c.SetSensorEventEnable(request); err != nil {
+			return fmt.Errorf("SetSensorEventEnable failed, err: %s", err)
+		}
+	}
+	return nil
+}
+
+// packSensorEvents packs the events into the 4 bytes of assertion event bits 7:0, 14:8
+// and deassertion event bits 7:0, 14:8, as used by Set Sensor Event Enable and Re-arm Sensor Events.
+func packSensorEvents(events []SensorEvent) []byte {
+	var assert, deassert uint16
+	for _, event := range events {
+		bit, ok := sensorEventBit(event)
+		if !ok {
+			continue
+		}
+		if event.Assert {
+			assert |= 1 << bit
+		} else {
+			deassert |= 1 << bit
+		}
+	}
+
+	out := make([]byte, 4)
+	packUint16L(assert, out, 0)
+	packUint16L(deassert, out, 2)
+	return out
+}
+
+// sensorEventThresholdTypes lists the threshold types in the order of the event bits,
+// each type takes two bits, going low and going high.
+var sensorEventThresholdTypes = []SensorThresholdType{
+	SensorThresholdType_LNC,
+	SensorThresholdType_LCR,
+	SensorThresholdType_LNR,
+	SensorThresholdType_UNC,
+	SensorThresholdType_UCR,
+	SensorThresholdType_UNR,
+}
+
+// sensorEventBit returns the bit (0-14) of the event in the assertion or deassertion event bits.
+//
+// For threshold events, the bits are LNC going low/high, LCR going low/high, LNR going low/high,
+// UNC going low/high, UCR going low/high and UNR going low/high.
+// For discrete events, the bit is the state (offset).
+func sensorEventBit(event SensorEvent) (uint8, bool) {
+	switch event.SensorClass {
+	case SensorClassThreshold:
+		for i, t := range sensorEventThresholdTypes {
+			if t == event.ThresholdType {
+				bit := uint8(i) * 2
+				if event.High {
+					bit++
+				}
+				return bit, true
+			}
+		}
+	case SensorClassDiscrete:
+		if event.State <= 14 {
+			return event.State, true
+		}
+	}
+	return 0, false
+}
+
+// SensorEventsOfMask returns the events of the bits set in the assertion or deassertion event mask
+// (eg: GetSensorEventEnableResponse.AssertionEventMask), the events are threshold events
+// if sensorClass is SensorClassThreshold, otherwise discrete states.
+func SensorEventsOfMask(mask uint16, assert bool, sensorClass SensorClass) []SensorEvent {
+	out := make([]SensorEvent, 0)
+	for bit := uint8(0); bit < 15; bit++ {
+		if mask&(1<<bit) == 0 {
+			continue
+		}
+		event := SensorEvent{SensorClass: sensorClass, Assert: assert}
+		if sensorClass == SensorClassThreshold {
+			if int(bit/2) >= len(sensorEventThresholdTypes) {
+				continue
+			}
+			event.ThresholdType = sensorEventThresholdTypes[bit/2]
+			event.High = bit%2 == 1
+		} else {
+			event.SensorClass = SensorClassDiscrete
+			event.State = bit
+		}
+		out = append(out, event)
+	}
+	return out
+}
+
+// ParseSensorEvent parses the event string in the format of SensorEvent.String,
+// that is "unc+" (going high) or "lcr-" (going low) for threshold events, and "state3" for discrete events.
+func ParseSensorEvent(s string, assert bool) (SensorEvent, error) {
+	event := SensorEvent{Assert: assert}
+
+	var state uint8
+	if _, err := fmt.Sscanf(s, "state%d", &state); err == nil && s == fmt.Sprintf("state%d", state) {
+		if state > 14 {
+			return event, fmt.Errorf("invalid event state %d, must be 0-14", state)
+		}
+		event.SensorClass = SensorClassDiscrete
+		event.State = state
+		return event, nil
+	}
+
+	if len(s) == 4 && (s[3] == '+' || s[3] == '-') {
+		if t, ok := sensorThresholdTypeByAbbr(s[:3]); ok {
+			event.SensorClass = SensorClassThreshold
+			event.ThresholdType = t
+			event.High = s[3] == '+'
+			return event, nil
+		}
+	}
+
+	return event, fmt.Errorf("invalid sensor event %q", s)
+}
@@ -1,5 +1,7 @@
 package ipmi
 
+import "fmt"
+
 // 35.6 Set Sensor Hysteresis Command
 type SetSensorHysteresisRequest struct {
 	SensorNumber       uint8
@@ -48,3 +50,39 @@ func (c *Client) SetSensorHysteresis(sensorNumber uint8, positiveHysteresis uint
 	err = c.Exchange(request, response)
 	return
 }
+
+// SetSensorHysteresisValues sets the positive-going and negative-going hysteresis of the sensor
+// to the values in the desired units for the sensor. The hysteresis must be settable
+// per the Sensor Capabilities of the SDR.
+func (c *Client) SetSensorHysteresisValues(sensorNumber uint8, positiveHysteresis float64, negativeHysteresis float64) error {
+	sensor, err := c.GetSensorByID(sensorNumber)
+	if err != nil {
+		return fmt.Errorf("GetSensorByID failed, err: %s", err)
+	}
+
+	return c.SetSensorHysteresisValuesFor(sensor, positiveHysteresis, negativeHysteresis)
+}
+
+// SetSensorHysteresisValuesFor is like SetSensorHysteresisValues, but for the sensor already
+// retrieved (eg: by GetSensorByOwner). The request is sent to the owner of the sensor.
+func (c *Client) SetSensorHysteresisValuesFor(sensor *Sensor, positiveHysteresis float64, negativeHysteresis float64) error {
+	if access := sensor.SensorCapabilitites.HysteresisAccess; access != SensorHysteresisAccess_ReadableSettable {
+		return fmt.Errorf("hysteresis of sensor %s (%#02x) is not settable, hysteresis access: %s", sensor.Name, sensor.Number, access)
+	}
+
+	positiveRaw, err := sensor.ConvertSensorHysteresisToRaw(positiveHysteresis)
+	if err != nil {
+		return fmt.Errorf("convert positive hysteresis value failed, err: %s", err)
+	}
+	negativeRaw, err := sensor.ConvertSensorHysteresisToRaw(negativeHysteresis)
+	if err != nil {
+		return fmt.Errorf("convert negative hysteresis value failed, err: %s", err)
+	}
+
+	return c.WithSensorOwner(sensor, func() error {
+		if _, err := c.SetSensorHysteresis(sensor.Number, positiveRaw, negativeRaw); err != nil {
+			return fmt.Errorf("SetSensorHysteresis failed, err: %s", err)
+		}
+		return nil
+	})
+}
@@ -1,5 +1,7 @@
 package ipmi
 
+import "fmt"
+
 // 35.8 Set Sensor Thresholds Command
 type SetSensorThresholdsRequest struct {
 	SensorNumber uint8
@@ -101,3 +103,113 @@ func (c *Client) SetSensorThresholds(request *SetSensorThresholdsRequest) (respo
 	err = c.Exchange(request, response)
 	return
 }
+
+// SetSensorThresholdValues sets the thresholds of the sensor to the values in the desired units for the sensor.
+// Only the thresholds present in values are set, and they must be settable per the
+// Settable Threshold Mask of the SDR. The values are converted to raw by the reading factors
+// of the sensor, for non-linear sensors the factors are retrieved for the converted raw value.
+func (c *Client) SetSensorThresholdValues(sensorNumber uint8, values map[SensorThresholdType]float64) error {
+	sensor, err := c.GetSensorByID(sensorNumber)
+	if err != nil {
+		return fmt.Errorf("GetSensorByID failed, err: %s", err)
+	}
+
+	return c.SetSensorThresholdValuesFor(sensor, values)
+}
+
+// SetSensorThresholdValuesFor is like SetSensorThresholdValues, but for the sensor already
+// retrieved (eg: by GetSensorByOwner). The request is sent to the owner of the sensor.
+func (c *Client) SetSensorThresholdValuesFor(sensor *Sensor, values map[SensorThresholdType]float64) error {
+	return c.WithSensorOwner(sensor, func() error {
+		request, err := c.sensorThresholdsRequest(sensor, values)
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.SetSensorThresholds(request); err != nil {
+			return fmt.Errorf("SetSensorThresholds failed, err: %s", err)
+		}
+		return nil
+	})
+}
+
+// sensorThresholdsRequest builds the Set Sensor Thresholds request for the values of the sensor.
+func (c *Client) sensorThresholdsRequest(sensor *Sensor, values map[SensorThresholdType]float64) (*SetSensorThresholdsRequest, error) {
+	if !sensor.IsThreshold() {
+		return nil, fmt.Errorf("sensor %s (%#02x) is not a threshold sensor", sensor.Name, sensor.Number)
+	}
+	if access := sensor.SensorCapabilitites.ThresholdAccess; access != SensorThresholdAccess_ReadableSettable {
+		return nil, fmt.Errorf("thresholds of sensor %s (%#02x) are not settable, threshold access: %s", sensor.Name, sensor.Number, access)
+	}
+
+	request := &SetSensorThresholdsRequest{
+		SensorNumber: sensor.Number,
+		LNC_Raw:      sensor.Threshold.LNC_Raw,
+		LCR_Raw:      sensor.Threshold.LCR_Raw,
+		LNR_Raw:      sensor.Threshold.LNR_Raw,
+		UNC_Raw:      sensor.Threshold.UNC_Raw,
+		UCR_Raw:      sensor.Threshold.UCR_Raw,
+		UNR_Raw:      sensor.Threshold.UNR_Raw,
+	}
+
+	for thresholdType, value := range values {
+		if !sensor.Threshold.Mask.IsThresholdSettable(thresholdType) {
+			return nil, fmt.Errorf("threshold %s of sensor %s (%#02x) is not settable", thresholdType.Abbr(), sensor.Name, sensor.Number)
+		}
+
+		raw, err := c.convertSensorValueToRaw(sensor, value)
+		if err != nil {
+			return nil, fmt.Errorf("convert threshold %s value failed, err: %s", thresholdType.Abbr(), err)
+		}
+
+		switch thresholdType {
+		case SensorThresholdType_LNC:
+			request.SetLNC, request.LNC_Raw = true, raw
+		case SensorThresholdType_LCR:
+			request.SetLCR, request.LCR_Raw = true, raw
+		case SensorThresholdType_LNR:
+			request.SetLNR, request.LNR_Raw = true, raw
+		case SensorThresholdType_UNC:
+			request.SetUNC, request.UNC_Raw = true, raw
+		case SensorThresholdType_UCR:
+			request.SetUCR, request.UCR_Raw = true, raw
+		case SensorThresholdType_UNR:
+			request.SetUNR, request.UNR_Raw = true, raw
+		}
+	}
+
+	return request, nil
+}
+
+// convertSensorValueToRaw converts the value to raw reading of the sensor.
+//
+// The reading factors of non-linear sensors vary with the raw reading (see 36.2 Non-Linear Sensors),
+// so the conversion is repeated with the factors of the converted raw value until it is stable.
+func (c *Client) convertSensorValueToRaw(sensor *Sensor, value float64) (uint8, error) {
+	raw, err := sensor.ConvertReadingToRaw(value)
+	if err != nil {
+		return 0, err
+	}
+	if !sensor.HasAnalogReading || !sensor.Threshold.LinearizationFunc.IsNonLinear() {
+		return raw, nil
+	}
+
+	s := *sensor
+	for i := 0; i < 8; i++ {
+		factorsRes, err := c.GetSensorReadingFactors(sensor.Number, raw)
+		if err != nil {
+			return 0, fmt.Errorf("GetSensorReadingFactors for sensor %#02x failed, err: %s", sensor.Number, err)
+		}
+		s.Threshold.ReadingFactors = factorsRes.ReadingFactors
+
+		next, err := s.ConvertReadingToRaw(value)
+		if err != nil {
+			return 0, err
+		}
+		if next == raw {
+			break
+		}
+		raw = next
+	}
+	return raw, nil
+}
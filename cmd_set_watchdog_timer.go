@@ -1,8 +1,15 @@
 package ipmi
 
+import (
+	"fmt"
+	"time"
+)
+
 // 27.6 Set Watchdog Timer Command
 type SetWatchdogTimerRequest struct {
-	DontLog       bool
+	DontLog bool
+	// DontStopTimer set to true means don't stop the timer if it is already running,
+	// the new settings take effect at the next Reset Watchdog Timer command.
 	DontStopTimer bool
 	TimerUse      TimerUse
 
@@ -10,7 +17,10 @@ type SetWatchdogTimerRequest struct {
 	TimeoutAction        TimeoutAction
 	PreTimeoutIntevalSec uint8
 
-	ExpirationFlags  uint8
+	// ExpirationFlags are the timer use expiration flags to clear,
+	// a bit set to 1 clears the flag of the corresponding timer use, see WatchdogExpirationFlag.
+	ExpirationFlags uint8
+	// InitialCountdown is in 100 ms units, see WatchdogCountdown.
 	InitialCountdown uint16
 }
 
@@ -57,9 +67,87 @@ func (res *SetWatchdogTimerResponse) Format() string {
 	return ""
 }
 
-func (c *Client) SetWatchdogTimer() (response *SetWatchdogTimerResponse, err error) {
-	request := &SetWatchdogTimerRequest{}
+// Validate checks the fields of the request before sending it to BMC.
+func (req *SetWatchdogTimerRequest) Validate() error {
+	if req.TimerUse < TimerUseBIOSFRB2 || req.TimerUse > TimerUseOEM {
+		return fmt.Errorf("invalid timer use (%#02x)", uint8(req.TimerUse))
+	}
+	if req.TimeoutAction > TimeoutActionPowerCycle {
+		return fmt.Errorf("invalid timeout action (%#02x)", uint8(req.TimeoutAction))
+	}
+	if req.PreTimeoutInterrupt > PreTimeoutInterruptMessaging {
+		return fmt.Errorf("invalid pre-timeout interrupt (%#02x)", uint8(req.PreTimeoutInterrupt))
+	}
+	if req.ExpirationFlags&^WatchdogExpirationFlagsMask != 0 {
+		return fmt.Errorf("invalid expiration flags (%#02x), only bit 1-5 can be set", req.ExpirationFlags)
+	}
+	// the pre-timeout interval is in seconds, and the countdown is in 100 ms units.
+	if req.PreTimeoutInterrupt != PreTimeoutInterruptNone &&
+		uint32(req.PreTimeoutIntevalSec)*10 > uint32(req.InitialCountdown) {
+		return fmt.Errorf("pre-timeout interval (%d sec) exceeds the initial countdown (%s)",
+			req.PreTimeoutIntevalSec, WatchdogCountdownDuration(req.InitialCountdown))
+	}
+	return nil
+}
+
+// SetWatchdogTimer initializes and configures the watchdog timer.
+// The timer is not started until ResetWatchdogTimer is called,
+// unless the timer is already running and DontStopTimer is set.
+func (c *Client) SetWatchdogTimer(request *SetWatchdogTimerRequest) (response *SetWatchdogTimerResponse, err error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid set watchdog timer request, err: %s", err)
+	}
 	response = &SetWatchdogTimerResponse{}
 	err = c.Exchange(request, response)
 	return
 }
+
+// StopWatchdogTimer shuts off the running watchdog timer.
+// The timer use and the initial countdown of the current settings are kept,
+// the timeout action is set to no action and the expiration flag of the timer use is cleared.
+func (c *Client) StopWatchdogTimer() error {
+	current, err := c.GetWatchdogTimer()
+	if err != nil {
+		return fmt.Errorf("GetWatchdogTimer failed, err: %s", err)
+	}
+
+	timerUse := current.TimerUse
+	if timerUse < TimerUseBIOSFRB2 || timerUse > TimerUseOEM {
+		timerUse = TimerUseSMSOS
+	}
+
+	request := &SetWatchdogTimerRequest{
+		DontLog:             current.DontLog,
+		DontStopTimer:       false,
+		TimerUse:            timerUse,
+		PreTimeoutInterrupt: PreTimeoutInterruptNone,
+		TimeoutAction:       TimeoutActionNoAction,
+		ExpirationFlags:     WatchdogExpirationFlag(timerUse),
+		InitialCountdown:    current.InitialCountdown,
+	}
+	if _, err := c.SetWatchdogTimer(request); err != nil {
+		return fmt.Errorf("SetWatchdogTimer failed, err: %s", err)
+	}
+	return nil
+}
+
+// WatchdogCountdown converts the duration to the countdown value in 100 ms units.
+// The duration must be a positive multiple of 100 ms and not exceed 6553.5 seconds.
+func WatchdogCountdown(d time.Duration) (uint16, error) {
+	if d <= 0 {
+		return 0, fmt.Errorf("watchdog countdown (%s) must be positive", d)
+	}
+	if d%(100*time.Millisecond) != 0 {
+		return 0, fmt.Errorf("watchdog countdown (%s) must be a multiple of 100ms", d)
+	}
+	units := d / (100 * time.Millisecond)
+	if units > 0xffff {
+		return 0, fmt.Errorf("watchdog countdown (%s) too long, max %s", d, WatchdogCountdownDuration(0xffff))
+	}
+	return uint16(units), nil
+}
+
+// WatchdogCountdownDuration converts the countdown value in 100 ms units to duration.
+func WatchdogCountdownDuration(countdown uint16) time.Duration {
+	return time.Duration(countdown) * 100 * time.Millisecond
+}
@@ -0,0 +1,159 @@
+package ipmi
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func Test_SetWatchdogTimerRequest_Pack(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  *SetWatchdogTimerRequest
+		expected []byte
+	}{
+		{
+			name: "sms/os hard reset",
+			request: &SetWatchdogTimerRequest{
+				DontLog:              true,
+				TimerUse:             TimerUseSMSOS,
+				PreTimeoutInterrupt:  PreTimeoutInterruptNMI,
+				TimeoutAction:        TimeoutActionHardReset,
+				PreTimeoutIntevalSec: 10,
+				ExpirationFlags:      WatchdogExpirationFlag(TimerUseSMSOS),
+				InitialCountdown:     600,
+			},
+			expected: []byte{0x84, 0x21, 0x0a, 0x10, 0x58, 0x02},
+		},
+		{
+			name: "dont stop os load power cycle",
+			request: &SetWatchdogTimerRequest{
+				DontStopTimer:    true,
+				TimerUse:         TimerUseOSLoad,
+				TimeoutAction:    TimeoutActionPowerCycle,
+				ExpirationFlags:  WatchdogExpirationFlagsMask,
+				InitialCountdown: 0xffff,
+			},
+			expected: []byte{0x43, 0x03, 0x00, 0x3e, 0xff, 0xff},
+		},
+	}
+
+	for _, test := range tests {
+		got := test.request.Pack()
+		if !bytes.Equal(got, test.expected) {
+			t.Errorf("test %s not matched, got: % x, expected: % x", test.name, got, test.expected)
+		}
+	}
+}
+
+func Test_SetWatchdogTimerRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     *SetWatchdogTimerRequest
+		expectedErr bool
+	}{
+		{
+			name:    "valid",
+			request: &SetWatchdogTimerRequest{TimerUse: TimerUseSMSOS, TimeoutAction: TimeoutActionHardReset, InitialCountdown: 100},
+		},
+		{
+			name:        "reserved timer use",
+			request:     &SetWatchdogTimerRequest{TimerUse: 0x00},
+			expectedErr: true,
+		},
+		{
+			name:        "reserved timeout action",
+			request:     &SetWatchdogTimerRequest{TimerUse: TimerUseSMSOS, TimeoutAction: 0x04},
+			expectedErr: true,
+		},
+		{
+			name:        "reserved pre-timeout interrupt",
+			request:     &SetWatchdogTimerRequest{TimerUse: TimerUseSMSOS, PreTimeoutInterrupt: 0x04},
+			expectedErr: true,
+		},
+		{
+			name:        "reserved expiration flags",
+			request:     &SetWatchdogTimerRequest{TimerUse: TimerUseSMSOS, ExpirationFlags: 0x01},
+			expectedErr: true,
+		},
+		{
+			name: "pre-timeout interval equals countdown",
+			request: &SetWatchdogTimerRequest{
+				TimerUse: TimerUseSMSOS, PreTimeoutInterrupt: PreTimeoutInterruptSMI, PreTimeoutIntevalSec: 10, InitialCountdown: 100,
+			},
+		},
+		{
+			name: "pre-timeout interval exceeds countdown",
+			request: &SetWatchdogTimerRequest{
+				TimerUse: TimerUseSMSOS, PreTimeoutInterrupt: PreTimeoutInterruptSMI, PreTimeoutIntevalSec: 11, InitialCountdown: 100,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "pre-timeout interval without interrupt",
+			request: &SetWatchdogTimerRequest{
+				TimerUse: TimerUseSMSOS, PreTimeoutIntevalSec: 11, InitialCountdown: 100,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		err := test.request.Validate()
+		if (err != nil) != test.expectedErr {
+			t.Errorf("test %s not matched, got: %v, expected error: %v", test.name, err, test.expectedErr)
+		}
+	}
+}
+
+func Test_WatchdogCountdown(t *testing.T) {
+	tests := []struct {
+		duration    time.Duration
+		expected    uint16
+		expectedErr bool
+	}{
+		{100 * time.Millisecond, 1, false},
+		{60 * time.Second, 600, false},
+		{6553500 * time.Millisecond, 0xffff, false},
+		{6553600 * time.Millisecond, 0, true},
+		{150 * time.Millisecond, 0, true},
+		{0, 0, true},
+		{-time.Second, 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := WatchdogCountdown(test.duration)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("test %s error not matched, got: %v, expected error: %v", test.duration, err, test.expectedErr)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("test %s not matched, got: %d, expected: %d", test.duration, got, test.expected)
+		}
+		if err == nil && WatchdogCountdownDuration(got) != test.duration {
+			t.Errorf("test %s duration not matched, got: %s, expected: %s", test.duration, WatchdogCountdownDuration(got), test.duration)
+		}
+	}
+}
+
+func Test_StopWatchdogTimer(t *testing.T) {
+	var set []byte
+	c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+		switch req.Cmd {
+		case CommandGetWatchdogTimer.ID:
+			// started SMS/OS timer, hard reset, 60 sec
+			return 0x00, []byte{0xc4, 0x01, 0x00, 0x00, 0x58, 0x02, 0x20, 0x01}
+		case CommandSetWatchdogTimer.ID:
+			set = append([]byte{}, req.Data...)
+			return 0x00, nil
+		}
+		return 0xc1, nil
+	})
+
+	if err := c.StopWatchdogTimer(); err != nil {
+		t.Fatalf("StopWatchdogTimer failed, err: %s", err)
+	}
+	expected := []byte{0x84, 0x00, 0x00, 0x10, 0x58, 0x02}
+	if !bytes.Equal(set, expected) {
+		t.Errorf("set watchdog timer not matched, got: % x, expected: % x", set, expected)
+	}
+}
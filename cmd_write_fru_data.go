@@ -1,7 +1,11 @@
 package ipmi
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"time"
 )
 
 // 34.3 Write FRU Data Command
@@ -20,7 +24,7 @@ func (req *WriteFRUDataRequest) Command() Command {
 }
 
 func (req *WriteFRUDataRequest) Pack() []byte {
-	out := make([]byte, 4)
+	out := make([]byte, 3+len(req.WriteData))
 	packUint8(req.FRUDeviceID, out, 0)
 	packUint16L(req.WriteOffset, out, 1)
 	packBytes(req.WriteData, out, 3)
@@ -58,3 +62,113 @@ func (c *Client) WriteFRUData(fruDeviceID uint8, writeOffset uint16, writeData [
 	err = c.Exchange(request, response)
 	return
 }
+
+const (
+	// the write data size of the first try, it is decreased if the interface
+	// or the FRU device can not accept it.
+	fruWriteChunkSize uint8 = 16
+
+	fruWriteBusyRetries  = 10
+	fruWriteBusyInterval = 100 * time.Millisecond
+)
+
+// WriteFRUImage writes data to the FRU inventory area of the FRU device starting from offset 0.
+//
+// The old contents of the whole area are read first and written to backup (if not nil)
+// before any write happens. The data is written in chunks sized for the interface, and
+// the chunks identical to the old contents are skipped. The "FRU device busy" (81h)
+// completion code is retried, and a "write-protected offset" (80h) chunk is split down
+// to single bytes, where a protected byte is accepted only if it already holds the wanted
+// value. At last the written data is read back and verified.
+func (c *Client) WriteFRUImage(deviceID uint8, data []byte, backup io.Writer) error {
+	fruAreaInfoRes, err := c.GetFRUInventoryAreaInfo(deviceID)
+	if err != nil {
+		return fmt.Errorf("GetFRUInventoryAreaInfo failed, err: %s", err)
+	}
+	if fruAreaInfoRes.DeviceAccessedByWords {
+		return fmt.Errorf("FRU device accessed by words is not supported")
+	}
+	if len(data) > int(fruAreaInfoRes.AreaSizeBytes) {
+		return fmt.Errorf("data size %d exceeds the FRU area size %d", len(data), fruAreaInfoRes.AreaSizeBytes)
+	}
+
+	oldData, err := c.readFRUDataByLength(deviceID, 0, fruAreaInfoRes.AreaSizeBytes)
+	if err != nil {
+		return fmt.Errorf("read old FRU data failed, err: %s", err)
+	}
+	if backup != nil {
+		if _, err := backup.Write(oldData); err != nil {
+			return fmt.Errorf("backup old FRU data failed, err: %s", err)
+		}
+	}
+
+	// maxChunkSize is decreased if the write size is too big, and chunkSize
+	// is narrowed down from it to find the write-protected bytes.
+	maxChunkSize := fruWriteChunkSize
+	chunkSize := maxChunkSize
+	var offset uint16
+	for int(offset) < len(data) {
+		end := int(offset) + int(chunkSize)
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := data[offset:end]
+
+		// a write-protected byte which already holds the wanted value is skipped here
+		if bytes.Equal(chunk, oldData[offset:end]) {
+			offset = uint16(end)
+			chunkSize = maxChunkSize
+			continue
+		}
+
+		res, err := c.tryWriteFRUData(deviceID, offset, chunk)
+		if err == nil {
+			if res.CountWritten == 0 {
+				return fmt.Errorf("WriteFRUData wrote nothing at offset %d", offset)
+			}
+			offset += uint16(res.CountWritten)
+			chunkSize = maxChunkSize
+			continue
+		}
+
+		var resErr *ResponseError
+		if !errors.As(err, &resErr) {
+			return fmt.Errorf("WriteFRUData at offset %d failed, err: %s", offset, err)
+		}
+
+		switch cc := uint8(resErr.CompletionCode()); {
+		case readFRUDataLength2Big(resErr.CompletionCode()) && chunkSize > 1:
+			maxChunkSize = chunkSize - 1
+			chunkSize = maxChunkSize
+		case cc == 0x80 && len(chunk) > 1:
+			// narrow down the write-protected bytes
+			chunkSize = uint8(len(chunk) / 2)
+		default:
+			return fmt.Errorf("WriteFRUData at offset %d failed, err: %s", offset, err)
+		}
+	}
+
+	newData, err := c.readFRUDataByLength(deviceID, 0, uint16(len(data)))
+	if err != nil {
+		return fmt.Errorf("read back FRU data failed, err: %s", err)
+	}
+	for i := range data {
+		if i >= len(newData) || newData[i] != data[i] {
+			return fmt.Errorf("verify FRU data failed at offset %d", i)
+		}
+	}
+
+	return nil
+}
+
+// tryWriteFRUData calls WriteFRUData and retries it when the FRU device is busy.
+func (c *Client) tryWriteFRUData(deviceID uint8, writeOffset uint16, writeData []byte) (response *WriteFRUDataResponse, err error) {
+	for i := 0; ; i++ {
+		c.Debugf("Try Write FRU Data, offset: (%d), count: (%d)\n", writeOffset, len(writeData))
+		response, err = c.WriteFRUData(deviceID, writeOffset, writeData)
+		if err == nil || i >= fruWriteBusyRetries || !isResponseErrorCC(err, 0x81) {
+			return
+		}
+		time.Sleep(fruWriteBusyInterval)
+	}
+}
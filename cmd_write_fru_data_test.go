@@ -0,0 +1,134 @@
+package ipmi
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// fruBackup records the backup data and the writes done to the device before the backup.
+type fruBackup struct {
+	device       *fakeFRUDevice
+	data         []byte
+	writesBefore int
+}
+
+func (b *fruBackup) Write(p []byte) (int, error) {
+	b.writesBefore = len(b.device.writes)
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
+
+func Test_WriteFRUImage(t *testing.T) {
+	newData := func(n int, fn func(i int) byte) []byte {
+		out := make([]byte, n)
+		for i := range out {
+			out[i] = fn(i)
+		}
+		return out
+	}
+	old := newData(32, func(i int) byte { return byte(i) })
+	// the first 16 bytes are unchanged
+	image := newData(32, func(i int) byte {
+		if i < 16 {
+			return byte(i)
+		}
+		return byte(i) + 0x80
+	})
+	// the byte at offset 20 is unchanged
+	imageKeep20 := append([]byte{}, image...)
+	imageKeep20[20] = old[20]
+
+	tests := []struct {
+		name        string
+		device      *fakeFRUDevice
+		image       []byte
+		expectedErr bool
+		// the offset and data of the Write FRU Data requests
+		expected [][]byte
+	}{
+		{
+			name:   "unchanged chunks skipped",
+			device: &fakeFRUDevice{},
+			image:  image,
+			expected: [][]byte{
+				append([]byte{16}, image[16:]...),
+			},
+		},
+		{
+			name:   "busy then success",
+			device: &fakeFRUDevice{busy: 2},
+			image:  image,
+			expected: [][]byte{
+				append([]byte{16}, image[16:]...),
+				append([]byte{16}, image[16:]...),
+				append([]byte{16}, image[16:]...),
+			},
+		},
+		{
+			name:   "too big shrinks the chunk",
+			device: &fakeFRUDevice{maxWrite: 14},
+			image:  image,
+			expected: [][]byte{
+				append([]byte{16}, image[16:]...),
+				append([]byte{16}, image[16:31]...),
+				append([]byte{16}, image[16:30]...),
+				append([]byte{30}, image[30:]...),
+			},
+		},
+		{
+			name:   "protected byte with the wanted value",
+			device: &fakeFRUDevice{protected: map[int]bool{20: true}},
+			image:  imageKeep20,
+			expected: [][]byte{
+				append([]byte{16}, imageKeep20[16:]...),
+				append([]byte{16}, imageKeep20[16:24]...),
+				append([]byte{16}, imageKeep20[16:20]...),
+				append([]byte{20}, imageKeep20[20:]...),
+				append([]byte{20}, imageKeep20[20:26]...),
+				append([]byte{20}, imageKeep20[20:23]...),
+				// the byte at offset 20 holds the wanted value
+				append([]byte{21}, imageKeep20[21:]...),
+			},
+		},
+		{
+			name:        "protected byte with other value",
+			device:      &fakeFRUDevice{protected: map[int]bool{20: true}},
+			image:       image,
+			expectedErr: true,
+		},
+		{
+			name:        "read back mismatch",
+			device:      &fakeFRUDevice{stuck: map[int]bool{25: true}},
+			image:       image,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		test.device.data = append([]byte{}, old...)
+		c := newTestLANClient(t, test.device.handle)
+		backup := &fruBackup{device: test.device}
+
+		err := c.WriteFRUImage(0x01, test.image, backup)
+		if !bytes.Equal(backup.data, old) || backup.writesBefore != 0 {
+			t.Errorf("test %s backup not matched, got: % x (after %d writes), expected: % x", test.name, backup.data, backup.writesBefore, old)
+		}
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("test %s expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %s: WriteFRUImage failed, err: %s", test.name, err)
+			continue
+		}
+		if !bytes.Equal(test.device.data, test.image) {
+			t.Errorf("test %s data not matched, got: % x, expected: % x", test.name, test.device.data, test.image)
+		}
+		if !reflect.DeepEqual(test.device.writes, test.expected) {
+			t.Errorf("test %s writes not matched, got: %x, expected: %x", test.name, test.device.writes, test.expected)
+		}
+	}
+}
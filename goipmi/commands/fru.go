@@ -1,8 +1,12 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/bougou/go-ipmi"
 	"github.com/spf13/cobra"
 )
 
@@ -20,25 +24,29 @@ func NewCmdFRU() *cobra.Command {
 		},
 	}
 	cmd.AddCommand(NewCmdFRUPrint())
+	cmd.AddCommand(NewCmdFRUBuild())
+	cmd.AddCommand(NewCmdFRURead())
+	cmd.AddCommand(NewCmdFRUWrite())
+	cmd.AddCommand(NewCmdFRUEdit())
 
 	return cmd
 }
 
 func NewCmdFRUPrint() *cobra.Command {
+	var output string
+
 	cmd := &cobra.Command{
 		Use:   "print",
 		Short: "print",
 		Run: func(cmd *cobra.Command, args []string) {
+			var frus []*ipmi.FRU
 
 			if len(args) < 1 {
-				frus, err := client.GetFRUs()
+				var err error
+				frus, err = client.GetFRUs()
 				if err != nil {
 					CheckErr(fmt.Errorf("GetFRUs failed, err: %s", err))
 				}
-
-				for _, fru := range frus {
-					fmt.Println(fru.String())
-				}
 			} else {
 				id, err := parseStringToInt64(args[0])
 				if err != nil {
@@ -50,10 +58,199 @@ func NewCmdFRUPrint() *cobra.Command {
 				if err != nil {
 					CheckErr(fmt.Errorf("GetFRU failed, err: %s", err))
 				}
-				fmt.Println(fru.String())
+				frus = append(frus, fru)
+			}
+
+			printFRUs(frus, output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "output format, supported (table,json)")
+
+	return cmd
+}
+
+// printFRUs prints the FRUs in the output format.
+func printFRUs(frus []*ipmi.FRU, output string) {
+	switch output {
+	case "table":
+		for _, fru := range frus {
+			fmt.Println(fru.String())
+		}
+	case "json":
+		b, err := json.MarshalIndent(frus, "", "  ")
+		if err != nil {
+			CheckErr(fmt.Errorf("marshal FRUs failed, err: %s", err))
+		}
+		fmt.Println(string(b))
+	default:
+		CheckErr(fmt.Errorf("unsupported output format: %s", output))
+	}
+}
+
+// NewCmdFRUBuild encodes a JSON FRU description into a FRU image file,
+// it works offline and does not connect to the BMC.
+func NewCmdFRUBuild() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build <spec.json> <output.bin>",
+		Short: "build a fru image file from a json description",
+		Args:  cobra.ExactArgs(2),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			specData, err := os.ReadFile(args[0])
+			if err != nil {
+				CheckErr(fmt.Errorf("read fru spec file failed, err: %s", err))
+			}
+
+			spec, err := ipmi.ParseFRUSpec(specData)
+			if err != nil {
+				CheckErr(fmt.Errorf("ParseFRUSpec failed, err: %s", err))
+			}
+
+			fru, err := spec.FRU()
+			if err != nil {
+				CheckErr(fmt.Errorf("invalid fru spec, err: %s", err))
+			}
+
+			data, err := fru.Pack()
+			if err != nil {
+				CheckErr(fmt.Errorf("pack fru failed, err: %s", err))
+			}
+
+			if err := os.WriteFile(args[1], data, 0644); err != nil {
+				CheckErr(fmt.Errorf("write fru image file failed, err: %s", err))
+			}
+			fmt.Printf("Wrote %d bytes FRU image to %s\n", len(data), args[1])
+		},
+	}
+	return cmd
+}
+
+func NewCmdFRURead() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "read <id> <file>",
+		Short: "read the fru image into a file",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			fruID := parseFRUID(args[0])
+
+			data, err := client.GetFRUData(fruID)
+			if err != nil {
+				CheckErr(fmt.Errorf("GetFRUData failed, err: %s", err))
+			}
+
+			if err := os.WriteFile(args[1], data, 0644); err != nil {
+				CheckErr(fmt.Errorf("write fru image file failed, err: %s", err))
+			}
+			fmt.Printf("Read %d bytes FRU image to %s\n", len(data), args[1])
+		},
+	}
+	return cmd
+}
+
+func NewCmdFRUWrite() *cobra.Command {
+	var backupFile string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "write <id> <file>",
+		Short: "write a fru image file, the old contents are saved to a backup file",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			fruID := parseFRUID(args[0])
+
+			data, err := os.ReadFile(args[1])
+			if err != nil {
+				CheckErr(fmt.Errorf("read fru image file failed, err: %s", err))
+			}
+			if _, err := ipmi.ParseFRUData(data); err != nil && !force {
+				CheckErr(fmt.Errorf("invalid fru image, use --force to write it anyway, err: %s", err))
 			}
 
+			writeFRUImage(fruID, data, backupFile)
 		},
 	}
+
+	cmd.Flags().StringVarP(&backupFile, "backup", "b", "", "the file to save the old fru contents, default fru_<id>_<time>.bak")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "write the image even if it can not be parsed")
 	return cmd
 }
+
+func NewCmdFRUEdit() *cobra.Command {
+	var backupFile string
+
+	cmd := &cobra.Command{
+		Use:   "edit <id> <area> <field> <value>",
+		Short: "edit a field of the fru info area, the checksums are recomputed",
+		Long: `edit a field of the fru info area, the checksums are recomputed.
+
+Only the edited area is rewritten in place, the other areas are kept as they are.
+If the area grows past the start of the next area, the whole image is laid out again.
+
+Areas and fields:
+  chassis : type, pn, serial, custom.<index>
+  board   : date (DD/MM/YYYY HH:MM:SS), mfg, pname, serial, pn, file, custom.<index>
+  product : mfg, pname, pn, ver, serial, atag, file, custom.<index>
+`,
+		Args: cobra.ExactArgs(4),
+		Run: func(cmd *cobra.Command, args []string) {
+			fruID := parseFRUID(args[0])
+
+			data, err := client.GetFRUData(fruID)
+			if err != nil {
+				CheckErr(fmt.Errorf("GetFRUData failed, err: %s", err))
+			}
+
+			fru, err := ipmi.ParseFRUData(data)
+			if err != nil {
+				CheckErr(fmt.Errorf("ParseFRUData failed, err: %s", err))
+			}
+
+			if err := fru.SetField(args[1], args[2], args[3]); err != nil {
+				CheckErr(fmt.Errorf("set fru field failed, err: %s", err))
+			}
+
+			newData, relaid, err := fru.PatchArea(data, args[1])
+			if err != nil {
+				CheckErr(fmt.Errorf("patch fru area failed, err: %s", err))
+			}
+			if relaid {
+				fmt.Printf("The %s area grows past its slot, the FRU image is laid out again\n", args[1])
+			}
+
+			writeFRUImage(fruID, newData, backupFile)
+		},
+	}
+
+	cmd.Flags().StringVarP(&backupFile, "backup", "b", "", "the file to save the old fru contents, default fru_<id>_<time>.bak")
+	return cmd
+}
+
+func parseFRUID(s string) uint8 {
+	id, err := parseStringToInt64(s)
+	if err != nil {
+		CheckErr(fmt.Errorf("invalid FRU Device ID passed, err: %s", err))
+	}
+	return uint8(id)
+}
+
+func writeFRUImage(fruID uint8, data []byte, backupFile string) {
+	if backupFile == "" {
+		backupFile = fmt.Sprintf("fru_%d_%s.bak", fruID, time.Now().Format("20060102150405"))
+	}
+	f, err := os.Create(backupFile)
+	if err != nil {
+		CheckErr(fmt.Errorf("create backup file failed, err: %s", err))
+	}
+	defer f.Close()
+
+	if err := client.WriteFRUImage(fruID, data, f); err != nil {
+		CheckErr(fmt.Errorf("WriteFRUImage failed, err: %s (old contents saved to %s)", err, backupFile))
+	}
+	fmt.Printf("Wrote and verified %d bytes FRU image, old contents saved to %s\n", len(data), backupFile)
+}
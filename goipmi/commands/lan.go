@@ -2,7 +2,10 @@ package commands
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 
+	"github.com/bougou/go-ipmi"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +24,7 @@ func NewCmdLan() *cobra.Command {
 	}
 	cmd.AddCommand(NewCmdLanStats())
 	cmd.AddCommand(NewCmdLanPrint())
+	cmd.AddCommand(NewCmdLanSet())
 
 	return cmd
 }
@@ -96,3 +100,481 @@ print [<channel number>]
 	}
 	return cmd
 }
+
+func NewCmdLanSet() *cobra.Command {
+	usage := `
+set <channel number> <param> <value>
+  ipaddr <x.x.x.x>                      Set IP address
+  netmask <x.x.x.x>                     Set subnet mask
+  ipsrc <none|static|dhcp|bios>         Set IP address source
+  defgw ipaddr <x.x.x.x>                Set default gateway IP address
+  defgw macaddr <xx:xx:xx:xx:xx:xx>     Set default gateway MAC address
+  bakgw ipaddr <x.x.x.x>                Set backup gateway IP address
+  bakgw macaddr <xx:xx:xx:xx:xx:xx>     Set backup gateway MAC address
+  vlan id <off|1-4094>                  Disable or enable 802.1q VLAN with the id
+  vlan priority <0-7>                   Set 802.1q VLAN priority
+  arp respond <on|off>                  Enable or disable BMC ARP responding
+  arp generate <on|off>                 Enable or disable BMC gratuitous ARP generation
+  community <string>                    Set SNMP community string
+  cipher_privs <XXXXXXXXXXXXXXX>        Set maximum privilege levels of cipher suites
+                                          X=Cipher Suite Unused
+                                          c=CALLBACK
+                                          u=USER
+                                          o=OPERATOR
+                                          a=ADMIN
+                                          O=OEM
+  alert <dest> ipaddr <x.x.x.x>         Set alert destination IP address
+  alert <dest> macaddr <xx:xx:xx:xx:xx:xx>
+                                        Set alert destination MAC address
+  alert <dest> gateway <def|bak>        Set alert destination gateway selector
+  alert <dest> ack <on|off>             Set alert acknowledge
+  alert <dest> type <pet|oem1|oem2>     Set alert destination type
+  alert <dest> time <seconds>           Set alert acknowledge timeout / retry interval
+  alert <dest> retry <number>           Set number of alert retries
+  ipv6 enables <ipv4|ipv6|both>         Set IPv4/IPv6 addressing enables
+  ipv6 static_addr <n> <enable|disable> <addr> <prefix_len>
+                                        Set IPv6 static address
+  ipv6 rtr_cfg <static|dynamic|both|none>
+                                        Set IPv6 router address configuration
+  ipv6 static_rtr <1|2> <addr> <macaddr> <prefix> <prefix_len>
+                                        Set IPv6 static router
+  ipv6 flow_label <label>               Set IPv6 header flow label
+  ipv6 hoplimit <hops>                  Set IPv6 header static hop limit
+  ipv6 traffic_class <class>            Set IPv6 header static traffic class
+`
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "set",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 3 {
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			id, err := parseStringToInt64(args[0])
+			if err != nil {
+				CheckErr(fmt.Errorf("invalid channel number passed, err: %s", err))
+			}
+			channelNumber := uint8(id)
+
+			update, err := lanSetUpdater(channelNumber, args[1], args[2:])
+			if err != nil {
+				CheckErr(fmt.Errorf("%s\nusage: %s", err, usage))
+			}
+
+			if err := client.UpdateLanConfig(channelNumber, update); err != nil {
+				CheckErr(fmt.Errorf("UpdateLanConfig failed, err: %s", err))
+			}
+		},
+	}
+	return cmd
+}
+
+// lanSetUpdater parses the arguments of "lan set" and returns the function to update the lan param.
+func lanSetUpdater(channelNumber uint8, param string, values []string) (func() error, error) {
+	parseIP := func(s string) (net.IP, error) {
+		ip := net.ParseIP(s)
+		if ip == nil || ip.To4() == nil {
+			return nil, fmt.Errorf("invalid ipv4 address (%s)", s)
+		}
+		return ip, nil
+	}
+
+	parseOnOff := func(s string) (bool, error) {
+		switch s {
+		case "on":
+			return true, nil
+		case "off":
+			return false, nil
+		}
+		return false, fmt.Errorf("invalid value (%s), must be on or off", s)
+	}
+
+	switch param {
+	case "ipaddr":
+		ip, err := parseIP(values[0])
+		if err != nil {
+			return nil, err
+		}
+		return func() error { return client.SetLanIP(channelNumber, ip) }, nil
+
+	case "netmask":
+		mask, err := parseIP(values[0])
+		if err != nil {
+			return nil, err
+		}
+		return func() error { return client.SetLanSubnetMask(channelNumber, mask) }, nil
+
+	case "ipsrc":
+		sources := map[string]ipmi.IPAddressSource{
+			"none":   ipmi.IPAddressSourceUnspecified,
+			"static": ipmi.IPAddressSourceStatic,
+			"dhcp":   ipmi.IPAddressSourceDHCP,
+			"bios":   ipmi.IPAddressSourceBIOS,
+		}
+		source, ok := sources[values[0]]
+		if !ok {
+			return nil, fmt.Errorf("invalid ip address source (%s)", values[0])
+		}
+		return func() error { return client.SetLanIPSource(channelNumber, source) }, nil
+
+	case "defgw", "bakgw":
+		if len(values) < 2 {
+			return nil, fmt.Errorf("missing value for %s %s", param, values[0])
+		}
+		switch values[0] {
+		case "ipaddr":
+			ip, err := parseIP(values[1])
+			if err != nil {
+				return nil, err
+			}
+			if param == "defgw" {
+				return func() error { return client.SetLanDefaultGatewayIP(channelNumber, ip) }, nil
+			}
+			return func() error { return client.SetLanBackupGatewayIP(channelNumber, ip) }, nil
+		case "macaddr":
+			mac, err := net.ParseMAC(values[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid mac address (%s), err: %s", values[1], err)
+			}
+			if param == "defgw" {
+				return func() error { return client.SetLanDefaultGatewayMAC(channelNumber, mac) }, nil
+			}
+			return func() error { return client.SetLanBackupGatewayMAC(channelNumber, mac) }, nil
+		}
+		return nil, fmt.Errorf("unknown %s param (%s)", param, values[0])
+
+	case "vlan":
+		if len(values) < 2 {
+			return nil, fmt.Errorf("missing value for vlan %s", values[0])
+		}
+		switch values[0] {
+		case "id":
+			if values[1] == "off" {
+				return func() error { return client.SetLanVLANID(channelNumber, false, 0) }, nil
+			}
+			id, err := strconv.ParseUint(values[1], 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid vlan id (%s)", values[1])
+			}
+			return func() error { return client.SetLanVLANID(channelNumber, true, uint16(id)) }, nil
+		case "priority":
+			priority, err := strconv.ParseUint(values[1], 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid vlan priority (%s)", values[1])
+			}
+			return func() error { return client.SetLanVLANPriority(channelNumber, uint8(priority)) }, nil
+		}
+		return nil, fmt.Errorf("unknown vlan param (%s)", values[0])
+
+	case "arp":
+		if len(values) < 2 {
+			return nil, fmt.Errorf("missing value for arp %s", values[0])
+		}
+		enabled, err := parseOnOff(values[1])
+		if err != nil {
+			return nil, err
+		}
+		if values[0] != "respond" && values[0] != "generate" {
+			return nil, fmt.Errorf("unknown arp param (%s)", values[0])
+		}
+		return func() error {
+			// read the current ARP control, only change the specified bit
+			lanConfig, err := client.GetLanConfigFor(channelNumber, ipmi.LanParam_ARPControl, 0)
+			if err != nil {
+				return fmt.Errorf("GetLanConfigFor failed, err: %s", err)
+			}
+			arpControl := lanConfig.ARPControl
+			if values[0] == "respond" {
+				arpControl.ARPResponseEnabled = enabled
+			} else {
+				arpControl.GratuitousARPEnabled = enabled
+			}
+			return client.SetLanARPControl(channelNumber, arpControl)
+		}, nil
+
+	case "community":
+		community := values[0]
+		return func() error { return client.SetLanCommunityString(channelNumber, community) }, nil
+
+	case "cipher_privs":
+		levels := make([]ipmi.PrivilegeLevel, 0)
+		shorts := map[rune]ipmi.PrivilegeLevel{
+			'X': ipmi.PrivilegeLevelUnspecified,
+			'c': ipmi.PrivilegeLevelCallback,
+			'u': ipmi.PrivilegeLevelUser,
+			'o': ipmi.PrivilegeLevelOperator,
+			'a': ipmi.PrivilegeLevelAdministrator,
+			'O': ipmi.PrivilegeLevelOEM,
+		}
+		for _, r := range values[0] {
+			level, ok := shorts[r]
+			if !ok {
+				return nil, fmt.Errorf("invalid privilege level (%c) in cipher_privs", r)
+			}
+			levels = append(levels, level)
+		}
+		return func() error { return client.SetLanCipherSuitePrivilegeLevels(channelNumber, levels) }, nil
+
+	case "alert":
+		if len(values) < 3 {
+			return nil, fmt.Errorf("usage: alert <dest> <param> <value>")
+		}
+		return lanSetAlertUpdater(channelNumber, values[0], values[1], values[2])
+
+	case "ipv6":
+		if len(values) < 2 {
+			return nil, fmt.Errorf("usage: ipv6 <param> <value>")
+		}
+		return lanSetIP6Updater(channelNumber, values[0], values[1:])
+	}
+
+	return nil, fmt.Errorf("unknown param (%s)", param)
+}
+
+// lanSetIP6Updater returns the function to update the IPv6 lan param.
+func lanSetIP6Updater(channelNumber uint8, param string, values []string) (func() error, error) {
+	parseIP6 := func(s string) (net.IP, error) {
+		ip := net.ParseIP(s)
+		if ip == nil || ip.To4() != nil {
+			return nil, fmt.Errorf("invalid ipv6 address (%s)", s)
+		}
+		return ip, nil
+	}
+
+	parseUint8 := func(name string, s string) (uint8, error) {
+		v, err := strconv.ParseUint(s, 0, 8)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s (%s)", name, s)
+		}
+		return uint8(v), nil
+	}
+
+	switch param {
+	case "enables":
+		enables := map[string]ipmi.IP6Enables{
+			"ipv4": ipmi.IP6EnablesIP4Only,
+			"ipv6": ipmi.IP6EnablesIP6Only,
+			"both": ipmi.IP6EnablesIP4AndIP6,
+		}
+		e, ok := enables[values[0]]
+		if !ok {
+			return nil, fmt.Errorf("invalid ipv6 enables (%s)", values[0])
+		}
+		return func() error { return client.SetLanIP6Enables(channelNumber, e) }, nil
+
+	case "static_addr":
+		if len(values) < 4 {
+			return nil, fmt.Errorf("usage: ipv6 static_addr <n> <enable|disable> <addr> <prefix_len>")
+		}
+		setSelector, err := parseUint8("address set selector", values[0])
+		if err != nil {
+			return nil, err
+		}
+		var enabled bool
+		switch values[1] {
+		case "enable":
+			enabled = true
+		case "disable":
+			enabled = false
+		default:
+			return nil, fmt.Errorf("invalid value (%s), must be enable or disable", values[1])
+		}
+		ip, err := parseIP6(values[2])
+		if err != nil {
+			return nil, err
+		}
+		prefixLength, err := parseUint8("prefix length", values[3])
+		if err != nil {
+			return nil, err
+		}
+		address := &ipmi.IP6Address{
+			SetSelector:  setSelector,
+			Enabled:      enabled,
+			Source:       ipmi.IP6AddressSourceStatic,
+			Address:      ip,
+			PrefixLength: prefixLength,
+		}
+		return func() error { return client.SetLanIP6StaticAddress(channelNumber, address) }, nil
+
+	case "rtr_cfg":
+		routerConfigs := map[string]ipmi.IP6RouterConfig{
+			"static":  {EnableStatic: true},
+			"dynamic": {EnableDynamic: true},
+			"both":    {EnableStatic: true, EnableDynamic: true},
+			"none":    {},
+		}
+		routerConfig, ok := routerConfigs[values[0]]
+		if !ok {
+			return nil, fmt.Errorf("invalid router config (%s)", values[0])
+		}
+		return func() error { return client.SetLanIP6RouterConfig(channelNumber, routerConfig) }, nil
+
+	case "static_rtr":
+		if len(values) < 5 {
+			return nil, fmt.Errorf("usage: ipv6 static_rtr <1|2> <addr> <macaddr> <prefix> <prefix_len>")
+		}
+		index, err := parseUint8("static router", values[0])
+		if err != nil {
+			return nil, err
+		}
+		ip, err := parseIP6(values[1])
+		if err != nil {
+			return nil, err
+		}
+		mac, err := net.ParseMAC(values[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid mac address (%s), err: %s", values[2], err)
+		}
+		prefix, err := parseIP6(values[3])
+		if err != nil {
+			return nil, err
+		}
+		prefixLength, err := parseUint8("prefix length", values[4])
+		if err != nil {
+			return nil, err
+		}
+		router := &ipmi.IP6Router{
+			IP:           ip,
+			MAC:          mac,
+			PrefixLength: prefixLength,
+			PrefixValue:  prefix,
+		}
+		return func() error { return client.SetLanIP6StaticRouter(channelNumber, index, router) }, nil
+
+	case "flow_label":
+		label, err := strconv.ParseUint(values[0], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid flow label (%s)", values[0])
+		}
+		return func() error { return client.SetLanIP6FlowLabel(channelNumber, uint32(label)) }, nil
+
+	case "hoplimit":
+		hopLimit, err := parseUint8("hop limit", values[0])
+		if err != nil {
+			return nil, err
+		}
+		return func() error { return client.SetLanIP6StaticHopLimit(channelNumber, hopLimit) }, nil
+
+	case "traffic_class":
+		trafficClass, err := parseUint8("traffic class", values[0])
+		if err != nil {
+			return nil, err
+		}
+		return func() error { return client.SetLanIP6StaticTrafficClass(channelNumber, trafficClass) }, nil
+	}
+
+	return nil, fmt.Errorf("unknown ipv6 param (%s)", param)
+}
+
+// lanSetAlertUpdater returns the function to update one field of the alert destination,
+// the other fields of the destination are read from BMC and kept unchanged.
+func lanSetAlertUpdater(channelNumber uint8, dest string, param string, value string) (func() error, error) {
+	destID, err := parseStringToInt64(dest)
+	if err != nil || destID < 0 || destID > 15 {
+		return nil, fmt.Errorf("invalid alert destination (%s)", dest)
+	}
+	setSelector := uint8(destID)
+
+	getDestinationType := func() (*ipmi.AlertDestinationType, error) {
+		lanConfig, err := client.GetLanConfigFor(channelNumber, ipmi.LanParam_AlertDestinationType, setSelector)
+		if err != nil {
+			return nil, fmt.Errorf("GetLanConfigFor failed, err: %s", err)
+		}
+		return &lanConfig.AlertDestinationType, nil
+	}
+
+	getDestinationAddress := func() (*ipmi.AlertDestinationAddress, error) {
+		lanConfig, err := client.GetLanConfigFor(channelNumber, ipmi.LanParam_AlertDestinationAddress, setSelector)
+		if err != nil {
+			return nil, fmt.Errorf("GetLanConfigFor failed, err: %s", err)
+		}
+		return &lanConfig.AlertDestinationAddress, nil
+	}
+
+	updateAddress := func(fn func(a *ipmi.AlertDestinationAddress)) func() error {
+		return func() error {
+			a, err := getDestinationAddress()
+			if err != nil {
+				return err
+			}
+			a.SetSelector = setSelector
+			a.AddressFormat = 0x00
+			fn(a)
+			return client.SetLanAlertDestinationAddress(channelNumber, a)
+		}
+	}
+
+	updateType := func(fn func(t *ipmi.AlertDestinationType)) func() error {
+		return func() error {
+			t, err := getDestinationType()
+			if err != nil {
+				return err
+			}
+			t.SetSelector = setSelector
+			fn(t)
+			return client.SetLanAlertDestinationType(channelNumber, t)
+		}
+	}
+
+	switch param {
+	case "ipaddr":
+		ip := net.ParseIP(value)
+		if ip == nil || ip.To4() == nil {
+			return nil, fmt.Errorf("invalid ipv4 address (%s)", value)
+		}
+		return updateAddress(func(a *ipmi.AlertDestinationAddress) { a.IP4IP = ip }), nil
+
+	case "macaddr":
+		mac, err := net.ParseMAC(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mac address (%s), err: %s", value, err)
+		}
+		return updateAddress(func(a *ipmi.AlertDestinationAddress) { a.IP4MAC = mac }), nil
+
+	case "gateway":
+		if value != "def" && value != "bak" {
+			return nil, fmt.Errorf("invalid gateway (%s), must be def or bak", value)
+		}
+		return updateAddress(func(a *ipmi.AlertDestinationAddress) { a.IP4UseBackupGateway = value == "bak" }), nil
+
+	case "ack":
+		var ack bool
+		switch value {
+		case "on":
+			ack = true
+		case "off":
+			ack = false
+		default:
+			return nil, fmt.Errorf("invalid value (%s), must be on or off", value)
+		}
+		return updateType(func(t *ipmi.AlertDestinationType) { t.AlertSupportAcknowledge = ack }), nil
+
+	case "type":
+		types := map[string]uint8{
+			"pet":  0x00,
+			"oem1": 0x06,
+			"oem2": 0x07,
+		}
+		destinationType, ok := types[value]
+		if !ok {
+			return nil, fmt.Errorf("invalid alert destination type (%s)", value)
+		}
+		return updateType(func(t *ipmi.AlertDestinationType) { t.DestinationType = destinationType }), nil
+
+	case "time":
+		timeout, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alert timeout (%s)", value)
+		}
+		return updateType(func(t *ipmi.AlertDestinationType) { t.AlertAcknowledgeTimeout = uint8(timeout) }), nil
+
+	case "retry":
+		retries, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alert retries (%s)", value)
+		}
+		return updateType(func(t *ipmi.AlertDestinationType) { t.Retries = uint8(retries) }), nil
+	}
+
+	return nil, fmt.Errorf("unknown alert param (%s)", param)
+}
@@ -2,7 +2,9 @@ package commands
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/bougou/go-ipmi"
 	"github.com/spf13/cobra"
 )
 
@@ -114,12 +116,22 @@ func NewCmdMC_GUID() *cobra.Command {
 }
 
 func NewCmdMC_Watchdog() *cobra.Command {
-	usage := `watchdog <get|reset|off>
+	usage := `watchdog <get|set|reset|off>
   get    :  Get Current Watchdog settings
+  set    :  Set Watchdog settings, the timer is stopped unless --dont-stop is specified
   reset  :  Restart Watchdog timer based on most recent settings
   off    :  Shut off a running Watchdog timer
 `
 
+	var timerUse string
+	var action string
+	var countdown time.Duration
+	var preTimeoutInterrupt string
+	var preTimeoutInterval uint8
+	var dontLog bool
+	var dontStop bool
+	var clearFlags []string
+
 	cmd := &cobra.Command{
 		Use:   "watchdog",
 		Short: "watchdog",
@@ -134,16 +146,61 @@ func NewCmdMC_Watchdog() *cobra.Command {
 					CheckErr(fmt.Errorf("GetWatchdogTimer failed, err: %s", err))
 				}
 				fmt.Println(res.Format())
+			case "set":
+				use, timeoutAction, interrupt, err := parseWatchdogOptions(timerUse, action, preTimeoutInterrupt)
+				if err != nil {
+					CheckErr(err)
+				}
+
+				initialCountdown, err := ipmi.WatchdogCountdown(countdown)
+				if err != nil {
+					CheckErr(err)
+				}
+
+				var expirationFlags uint8
+				for _, flag := range clearFlags {
+					use, err := parseWatchdogTimerUse(flag)
+					if err != nil {
+						CheckErr(fmt.Errorf("invalid timer use to clear, err: %s", err))
+					}
+					expirationFlags |= ipmi.WatchdogExpirationFlag(use)
+				}
+
+				request := &ipmi.SetWatchdogTimerRequest{
+					DontLog:              dontLog,
+					DontStopTimer:        dontStop,
+					TimerUse:             use,
+					PreTimeoutInterrupt:  interrupt,
+					TimeoutAction:        timeoutAction,
+					PreTimeoutIntevalSec: preTimeoutInterval,
+					ExpirationFlags:      expirationFlags,
+					InitialCountdown:     initialCountdown,
+				}
+				if _, err := client.SetWatchdogTimer(request); err != nil {
+					CheckErr(fmt.Errorf("SetWatchdogTimer failed, err: %s", err))
+				}
 			case "reset":
 				if _, err := client.ResetWatchdogTimer(); err != nil {
 					CheckErr(fmt.Errorf("ResetWatchdogTimer failed, err: %s", err))
 				}
 			case "off":
-				//
+				if err := client.StopWatchdogTimer(); err != nil {
+					CheckErr(fmt.Errorf("StopWatchdogTimer failed, err: %s", err))
+				}
 			default:
 				CheckErr(fmt.Errorf("usage: %s", usage))
 			}
 		},
 	}
+
+	cmd.Flags().StringVarP(&timerUse, "timer-use", "", "sms", "timer use for set, supported (frb2,post,osload,sms,oem)")
+	cmd.Flags().StringVarP(&action, "action", "", "none", "timeout action for set, supported (none,reset,poweroff,powercycle)")
+	cmd.Flags().DurationVarP(&countdown, "countdown", "", 5*time.Minute, "initial countdown for set, in 100ms resolution")
+	cmd.Flags().StringVarP(&preTimeoutInterrupt, "pretimeout-interrupt", "", "none", "pre-timeout interrupt for set, supported (none,smi,nmi,msg)")
+	cmd.Flags().Uint8VarP(&preTimeoutInterval, "pretimeout", "", 0, "pre-timeout interval in seconds for set")
+	cmd.Flags().BoolVarP(&dontLog, "dont-log", "", false, "don't log the timeout event for set")
+	cmd.Flags().BoolVarP(&dontStop, "dont-stop", "", false, "don't stop the timer if it is running for set")
+	cmd.Flags().StringSliceVarP(&clearFlags, "clear-flags", "", []string{}, "timer use expiration flags to clear for set, supported (frb2,post,osload,sms,oem)")
+
 	return cmd
 }
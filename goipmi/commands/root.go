@@ -12,11 +12,14 @@ const homePage = "https://github.com/bougou/go-ipmi"
 
 var (
 	host     string
+	preferIP string
 	port     int
 	username string
 	password string
 	intf     string
 	debug    bool
+	sdrFile  string
+	sdrCache string
 
 	showVersion bool
 
@@ -43,6 +46,15 @@ func initClient() error {
 		if err != nil {
 			return fmt.Errorf("create lan or lanplus client failed, err: %s", err)
 		}
+		switch preferIP {
+		case "", "any":
+		case "ipv4":
+			c.WithIPPreference(ipmi.IPPreferenceIPv4)
+		case "ipv6":
+			c.WithIPPreference(ipmi.IPPreferenceIPv6)
+		default:
+			return fmt.Errorf("invalid prefer-ip (%s), supported (any,ipv4,ipv6)", preferIP)
+		}
 		client = c
 	case "tool":
 		c, err := ipmi.NewToolClient(host)
@@ -54,6 +66,12 @@ func initClient() error {
 
 	client.WithDebug(debug)
 	client.WithInterface(ipmi.Interface(intf))
+	if sdrFile != "" {
+		client.WithSDRFile(sdrFile)
+	}
+	if sdrCache != "" {
+		client.WithSDRCache(ipmi.NewSDRFileCache(sdrCache))
+	}
 
 	if err := client.Connect(); err != nil {
 		return fmt.Errorf("client connect failed, err: %s", err)
@@ -85,12 +103,15 @@ func NewRootCommand() *cobra.Command {
 		},
 	}
 
-	rootCmd.PersistentFlags().StringVarP(&host, "host", "H", "", "host")
+	rootCmd.PersistentFlags().StringVarP(&host, "host", "H", "", "host, hostname or IPv4/IPv6 address, link-local IPv6 address can be zone-scoped like fe80::1%eth0")
+	rootCmd.PersistentFlags().StringVarP(&preferIP, "prefer-ip", "", "any", "IP address family used if host resolves to both, supported (any,ipv4,ipv6), any races the two families like happy eyeballs")
 	rootCmd.PersistentFlags().IntVarP(&port, "port", "p", 623, "port")
 	rootCmd.PersistentFlags().StringVarP(&username, "user", "U", "", "username")
 	rootCmd.PersistentFlags().StringVarP(&password, "pass", "P", "", "password")
 	rootCmd.PersistentFlags().StringVarP(&intf, "interface", "I", "open", "interface, supported (open,lan,lanplus)")
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "debug")
+	rootCmd.PersistentFlags().StringVarP(&sdrFile, "sdr-file", "S", "", "load SDRs from the file (generated by sdr dump) instead of the BMC")
+	rootCmd.PersistentFlags().StringVarP(&sdrCache, "sdr-cache", "", "", "cache SDRs of the BMC under the directory, reused until the SDR Repository changes")
 	rootCmd.PersistentFlags().BoolVarP(&showVersion, "version", "V", false, "version")
 
 	rootCmd.Flags().AddGoFlagSet(flag.CommandLine)
@@ -107,6 +128,7 @@ func NewRootCommand() *cobra.Command {
 	rootCmd.AddCommand(NewCmdFRU())
 	rootCmd.AddCommand(NewCmdSOL())
 	rootCmd.AddCommand(NewCmdPEF())
+	rootCmd.AddCommand(NewCmdWatchdog())
 
 	rootCmd.SilenceUsage = true
 	rootCmd.SilenceErrors = true
@@ -25,6 +25,7 @@ func NewCmdSDR() *cobra.Command {
 	cmd.AddCommand(NewCmdSDRInfo())
 	cmd.AddCommand(NewCmdSDRGet())
 	cmd.AddCommand(NewCmdSDRList())
+	cmd.AddCommand(NewCmdSDRDump())
 
 	return cmd
 }
@@ -144,3 +145,24 @@ func NewCmdSDRList() *cobra.Command {
 
 	return cmd
 }
+
+func NewCmdSDRDump() *cobra.Command {
+	usage := `sdr dump <file>`
+
+	cmd := &cobra.Command{
+		Use:   "dump",
+		Short: "dump raw SDR records to file",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("no file supplied, usage: %s", usage))
+			}
+
+			if err := client.DumpSDRsToFile(args[0]); err != nil {
+				CheckErr(fmt.Errorf("DumpSDRsToFile failed, err: %s", err))
+			}
+			fmt.Printf("Dumped SDRs to %s\n", args[0])
+		},
+	}
+
+	return cmd
+}
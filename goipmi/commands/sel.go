@@ -1,8 +1,16 @@
 package commands
 
 import (
+	"bufio"
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/bougou/go-ipmi"
 	"github.com/spf13/cobra"
@@ -25,6 +33,12 @@ func NewCmdSEL() *cobra.Command {
 	cmd.AddCommand(NewCmdSELGet())
 	cmd.AddCommand(NewCmdSELList())
 	cmd.AddCommand(NewCmdSELElist())
+	cmd.AddCommand(NewCmdSELTail())
+	cmd.AddCommand(NewCmdSELClear())
+	cmd.AddCommand(NewCmdSELDelete())
+	cmd.AddCommand(NewCmdSELAdd())
+	cmd.AddCommand(NewCmdSELTime())
+	cmd.AddCommand(NewCmdSELSave())
 
 	return cmd
 }
@@ -73,13 +87,15 @@ func NewCmdSELGet() *cobra.Command {
 			if err != nil {
 				CheckErr(fmt.Errorf("ParseSEL failed, err: %s", err))
 			}
-			fmt.Println(ipmi.FormatSELs([]*ipmi.SEL{sel}, nil))
+			fmt.Println(ipmi.FormatSELsFor([]*ipmi.SEL{sel}, nil, bmcManufacturerID()))
 		},
 	}
 	return cmd
 }
 
 func NewCmdSELList() *cobra.Command {
+	var output string
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "list",
@@ -89,13 +105,18 @@ func NewCmdSELList() *cobra.Command {
 				CheckErr(fmt.Errorf("GetSELInfo failed, err: %s", err))
 			}
 
-			fmt.Println(ipmi.FormatSELs(selEntries, nil))
+			printSELs(selEntries, nil, output)
 		},
 	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "output format, supported (table,json,csv,syslog,ipmitool)")
+
 	return cmd
 }
 
 func NewCmdSELElist() *cobra.Command {
+	var output string
+
 	cmd := &cobra.Command{
 		Use:   "elist",
 		Short: "elist",
@@ -110,8 +131,361 @@ func NewCmdSELElist() *cobra.Command {
 				CheckErr(fmt.Errorf("GetSELInfo failed, err: %s", err))
 			}
 
-			fmt.Println(ipmi.FormatSELs(selEntries, sdrsMap))
+			printSELs(selEntries, sdrsMap, output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "output format, supported (table,json,csv,syslog,ipmitool)")
+
+	return cmd
+}
+
+// printSELs prints the SEL entries in the output format.
+func printSELs(selEntries []*ipmi.SEL, sdrsMap ipmi.SDRMapBySensorNumber, output string) {
+	manufacturerID := bmcManufacturerID()
+	if output == "table" {
+		fmt.Println(ipmi.FormatSELsFor(selEntries, sdrsMap, manufacturerID))
+		return
+	}
+
+	if err := ipmi.ExportSELs(os.Stdout, selEntries, sdrsMap, manufacturerID, ipmi.SELExportFormat(output)); err != nil {
+		CheckErr(fmt.Errorf("ExportSELs failed, err: %s", err))
+	}
+}
+
+func NewCmdSELTail() *cobra.Command {
+	var follow bool
+	var lines int
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "tail",
+		Short: "print the last SEL entries, and wait for the new entries if -f is specified",
+		Run: func(cmd *cobra.Command, args []string) {
+			sdrsMap, err := client.GetSDRsMap()
+			if err != nil {
+				CheckErr(fmt.Errorf("GetSDRsMap failed, err: %s", err))
+			}
+			manufacturerID := bmcManufacturerID()
+
+			selInfo, err := client.GetSELInfo()
+			if err != nil {
+				CheckErr(fmt.Errorf("GetSELInfo failed, err: %s", err))
+			}
+
+			selEntries := make([]*ipmi.SEL, 0)
+			if selInfo.Entries > 0 {
+				selEntries, err = client.GetSELEntries(0)
+				if err != nil {
+					CheckErr(fmt.Errorf("GetSELEntries failed, err: %s", err))
+				}
+			}
+			// follow from the last entry, so the entries added after GetSELEntries are not lost.
+			var lastRecordID uint16
+			if len(selEntries) > 0 {
+				lastRecordID = selEntries[len(selEntries)-1].RecordID
+			}
+
+			if len(selEntries) > lines {
+				selEntries = selEntries[len(selEntries)-lines:]
+			}
+			for _, sel := range selEntries {
+				fmt.Println(ipmi.FormatSELIPMITool(sel, sdrsMap, manufacturerID))
+			}
+
+			if !follow {
+				return
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sigChan := make(chan os.Signal, 1)
+			signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+			go func() {
+				<-sigChan
+				cancel()
+			}()
+
+			watcher := ipmi.NewSELWatcher(client, ipmi.SELWatcherConfig{
+				Interval:     interval,
+				FromStart:    true,
+				LastRecordID: lastRecordID,
+				OnCleared: func(eraseTime time.Time) {
+					fmt.Printf("SEL cleared at %v\n", eraseTime)
+				},
+				OnOverflow: func() {
+					fmt.Println("SEL overflow, events are dropped")
+				},
+				OnWrapped: func() {
+					fmt.Println("SEL wrapped around")
+				},
+				OnError: func(err error) {
+					fmt.Fprintf(os.Stderr, "%s\n", err)
+				},
+			})
+			for sel := range watcher.Watch(ctx) {
+				fmt.Println(ipmi.FormatSELIPMITool(sel, sdrsMap, manufacturerID))
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "wait for and print the new SEL entries")
+	cmd.Flags().IntVarP(&lines, "lines", "n", 10, "number of the last SEL entries to print")
+	cmd.Flags().DurationVarP(&interval, "interval", "", 5*time.Second, "interval to poll the SEL")
+
+	return cmd
+}
+
+func NewCmdSELClear() *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "clear",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := client.ClearSELAndWait(timeout); err != nil {
+				CheckErr(fmt.Errorf("ClearSELAndWait failed, err: %s", err))
+			}
+			fmt.Println("Clearing SEL completed")
+		},
+	}
+
+	cmd.Flags().DurationVarP(&timeout, "timeout", "", 60*time.Second, "timeout to wait for the SEL erasure to complete")
+
+	return cmd
+}
+
+func NewCmdSELDelete() *cobra.Command {
+	usage := "delete <id>..."
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "delete",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			recordIDs := make([]uint16, 0)
+			for _, arg := range args {
+				id, err := parseStringToInt64(arg)
+				if err != nil || id <= 0 || id >= 0xffff {
+					CheckErr(fmt.Errorf("invalid Record ID (%s)", arg))
+				}
+				recordIDs = append(recordIDs, uint16(id))
+			}
+
+			for _, recordID := range recordIDs {
+				// the reservation is canceled once an entry is deleted, so reserve for each deletion.
+				reserveRes, err := client.ReserveSEL()
+				if err != nil {
+					CheckErr(fmt.Errorf("ReserveSEL failed, err: %s", err))
+				}
+				if _, err := client.DeleteSELEntry(recordID, reserveRes.ReservationID); err != nil {
+					CheckErr(fmt.Errorf("DeleteSELEntry (%#04x) failed, err: %s", recordID, err))
+				}
+				fmt.Printf("Deleted entry %d\n", recordID)
+			}
+		},
+	}
+	return cmd
+}
+
+func NewCmdSELAdd() *cobra.Command {
+	usage := `add <file>
+  Add SEL entries from the file, each line contains the 16 bytes of a SEL record, each byte in 2 hex digits (0x prefix is optional),
+  like the file generated by "sel save". The record ID (first two bytes) is assigned by BMC.
+  Empty lines and the content after # are ignored.
+`
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "add",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			sels, err := readSELFile(args[0])
+			if err != nil {
+				CheckErr(fmt.Errorf("read SEL file failed, err: %s", err))
+			}
+
+			for _, sel := range sels {
+				res, err := client.AddSELEntry(sel)
+				if err != nil {
+					CheckErr(fmt.Errorf("AddSELEntry failed, err: %s", err))
+				}
+				fmt.Printf("Added entry %d\n", res.RecordID)
+			}
+		},
+	}
+	return cmd
+}
+
+func NewCmdSELTime() *cobra.Command {
+	usage := `time <get|set>
+  get                             Get the SEL time
+  set <now|"MM/DD/YYYY HH:MM:SS"> Set the SEL time, the time is in local time zone
+`
+
+	cmd := &cobra.Command{
+		Use:   "time",
+		Short: "time",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			switch args[0] {
+			case "get":
+				res, err := client.GetSELTime()
+				if err != nil {
+					CheckErr(fmt.Errorf("GetSELTime failed, err: %s", err))
+				}
+				fmt.Println(res.Time.Local().Format("01/02/2006 15:04:05"))
+
+			case "set":
+				if len(args) < 2 {
+					CheckErr(fmt.Errorf("usage: %s", usage))
+				}
+
+				var t time.Time
+				if args[1] == "now" {
+					t = time.Now()
+				} else {
+					var err error
+					t, err = time.ParseInLocation("01/02/2006 15:04:05", strings.Join(args[1:], " "), time.Local)
+					if err != nil {
+						CheckErr(fmt.Errorf("invalid time (%s), err: %s", strings.Join(args[1:], " "), err))
+					}
+				}
+
+				if _, err := client.SetSELTime(t); err != nil {
+					CheckErr(fmt.Errorf("SetSELTime failed, err: %s", err))
+				}
+				fmt.Println(t.Format("01/02/2006 15:04:05"))
+
+			default:
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+		},
+	}
+	return cmd
+}
+
+func NewCmdSELSave() *cobra.Command {
+	usage := "save <file>"
+	var clear bool
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "save",
+		Short: "save SEL entries to the file, which can be added back by sel add",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			selInfo, err := client.GetSELInfo()
+			if err != nil {
+				CheckErr(fmt.Errorf("GetSELInfo failed, err: %s", err))
+			}
+
+			selEntries := make([]*ipmi.SEL, 0)
+			if selInfo.Entries > 0 {
+				selEntries, err = client.GetSELEntries(0)
+				if err != nil {
+					CheckErr(fmt.Errorf("GetSELEntries failed, err: %s", err))
+				}
+			}
+
+			if err := writeSELFile(args[0], selEntries, bmcManufacturerID()); err != nil {
+				CheckErr(fmt.Errorf("write SEL file failed, err: %s", err))
+			}
+			fmt.Printf("Saved %d entries to %s\n", len(selEntries), args[0])
+
+			if clear {
+				if err := client.ClearSELAndWait(timeout); err != nil {
+					CheckErr(fmt.Errorf("ClearSELAndWait failed, err: %s", err))
+				}
+				fmt.Println("Clearing SEL completed")
+			}
 		},
 	}
+
+	cmd.Flags().BoolVarP(&clear, "clear", "", false, "clear the SEL after the entries are saved")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "", 60*time.Second, "timeout to wait for the SEL erasure to complete")
+
 	return cmd
 }
+
+// readSELFile reads the SEL records from the file, see NewCmdSELAdd.
+func readSELFile(path string) ([]*ipmi.SEL, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	out := make([]*ipmi.SEL, 0)
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 16 {
+			return nil, fmt.Errorf("line %d: expect 16 bytes, got %d", lineNo, len(fields))
+		}
+
+		data := make([]byte, 16)
+		for i, field := range fields {
+			// each byte is 2 hex digits, a shorter one means the line is truncated.
+			hex := strings.TrimPrefix(strings.ToLower(field), "0x")
+			v, err := strconv.ParseUint(hex, 16, 8)
+			if err != nil || len(hex) != 2 {
+				return nil, fmt.Errorf("line %d: invalid byte (%s)", lineNo, field)
+			}
+			data[i] = uint8(v)
+		}
+
+		sel, err := ipmi.ParseSEL(data)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: ParseSEL failed, err: %s", lineNo, err)
+		}
+		out = append(out, sel)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// writeSELFile writes the SEL records to the file, one record per line in hex,
+// followed by the description as comment.
+func writeSELFile(path string, sels []*ipmi.SEL, bmcManufacturerID ipmi.OEM) error {
+	var buf strings.Builder
+	for _, sel := range sels {
+		buf.WriteString(fmt.Sprintf("% x # %s\n", sel.Pack(), ipmi.FormatSELIPMITool(sel, nil, bmcManufacturerID)))
+	}
+	return os.WriteFile(path, []byte(buf.String()), 0644)
+}
+
+// bmcManufacturerID returns the manufacturer of the BMC, used to decode the non-timestamped OEM SEL records.
+// It returns 0 (unknown) if Get Device ID fails.
+func bmcManufacturerID() ipmi.OEM {
+	res, err := client.GetDeviceID()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "GetDeviceID failed, OEM SEL records are decoded without the BMC manufacturer, err: %s\n", err)
+		return 0
+	}
+	return ipmi.OEM(res.ManufacturerID)
+}
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+)
+
+func Test_SELFile(t *testing.T) {
+	records := [][]byte{
+		// standard, threshold event
+		{0x01, 0x00, 0x02, 0x6d, 0x8e, 0x91, 0x5f, 0x20, 0x00, 0x04, 0x01, 0x30, 0x01, 0x57, 0x5a, 0x50},
+		// standard, deassertion
+		{0x02, 0x00, 0x02, 0x70, 0x8e, 0x91, 0x5f, 0x41, 0x00, 0x04, 0x0c, 0x10, 0xef, 0xa0, 0x00, 0x01},
+		// timestamped OEM
+		{0x03, 0x00, 0xc1, 0x71, 0x8e, 0x91, 0x5f, 0x57, 0x01, 0x00, 0xde, 0xad, 0xbe, 0xef, 0x00, 0xff},
+		// non-timestamped OEM
+		{0x04, 0x00, 0xe0, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d},
+	}
+
+	sels := make([]*ipmi.SEL, 0, len(records))
+	for _, record := range records {
+		sel, err := ipmi.ParseSEL(record)
+		if err != nil {
+			t.Fatalf("ParseSEL failed, err: %s", err)
+		}
+		sels = append(sels, sel)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sel.txt")
+	if err := writeSELFile(path, sels, ipmi.OEM_DELL); err != nil {
+		t.Fatalf("writeSELFile failed, err: %s", err)
+	}
+
+	loaded, err := readSELFile(path)
+	if err != nil {
+		t.Fatalf("readSELFile failed, err: %s", err)
+	}
+	if len(loaded) != len(records) {
+		t.Fatalf("loaded records not matched, got: %d, expected: %d", len(loaded), len(records))
+	}
+	for i, sel := range loaded {
+		if got := sel.Pack(); !bytes.Equal(got, records[i]) {
+			t.Errorf("record %d not matched, got: % x, expected: % x", i, got, records[i])
+		}
+	}
+
+	// saving the loaded records produces the same file
+	path2 := filepath.Join(dir, "sel2.txt")
+	if err := writeSELFile(path2, loaded, ipmi.OEM_DELL); err != nil {
+		t.Fatalf("writeSELFile failed, err: %s", err)
+	}
+	saved, _ := os.ReadFile(path)
+	saved2, _ := os.ReadFile(path2)
+	if !bytes.Equal(saved, saved2) {
+		t.Errorf("saved files not matched, got:\n%s\nexpected:\n%s", saved2, saved)
+	}
+}
+
+func Test_readSELFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    int
+		expectedErr string
+	}{
+		{
+			name:     "comments and blank lines",
+			content:  "# saved SEL\n\n0x01 0x00 0x02 0x6d 0x8e 0x91 0x5f 0x20 0x00 0x04 0x01 0x30 0x01 0x57 0x5a 0x50\n",
+			expected: 1,
+		},
+		{
+			name:        "truncated",
+			content:     "01 00 02 6d 8e 91 5f 20 00 04 01 30 01 57 5a 50 # ok\n02 00 02 70 8e 91 5f 41 00",
+			expectedErr: "line 2: expect 16 bytes, got 9",
+		},
+		{
+			name:        "truncated in byte",
+			content:     "01 00 02 6d 8e 91 5f 20 00 04 01 30 01 57 5a 5",
+			expectedErr: "line 1: invalid byte (5)",
+		},
+		{
+			name:        "invalid byte",
+			content:     "01 00 02 6d 8e 91 5f 20 00 04 01 30 01 57 5a 5g\n",
+			expectedErr: "line 1: invalid byte (5g)",
+		},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "sel.txt")
+		if err := os.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatalf("write file failed, err: %s", err)
+		}
+
+		sels, err := readSELFile(path)
+		if test.expectedErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+				t.Errorf("test %s: error not matched, got: %v, expected: %s", test.name, err, test.expectedErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %s: readSELFile failed, err: %s", test.name, err)
+			continue
+		}
+		if len(sels) != test.expected {
+			t.Errorf("test %s not matched, got: %d, expected: %d", test.name, len(sels), test.expected)
+		}
+	}
+}
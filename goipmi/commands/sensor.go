@@ -2,11 +2,23 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/bougou/go-ipmi"
 	"github.com/spf13/cobra"
 )
 
+// sensorOwner and sensorOwnerLUN select the sensor by the sensor owner,
+// as the sensor numbers are only unique per sensor owner.
+var (
+	sensorOwner    string
+	sensorOwnerLUN uint8
+)
+
 func NewCmdSensor() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "sensor",
@@ -24,12 +36,19 @@ func NewCmdSensor() *cobra.Command {
 	cmd.AddCommand(NewCmdSensorGet())
 	cmd.AddCommand(NewCmdSensorList())
 	cmd.AddCommand(NewCmdSensorThreshold())
+	cmd.AddCommand(NewCmdSensorHysteresis())
+	cmd.AddCommand(NewCmdSensorProfile())
 	cmd.AddCommand(NewCmdSensorEventEnable())
 	cmd.AddCommand(NewCmdSensorEventStatus())
+	cmd.AddCommand(NewCmdSensorRearm())
 	cmd.AddCommand(NewCmdSensorReading())
 	cmd.AddCommand(NewCmdSensorReadingFactors())
+	cmd.AddCommand(NewCmdSensorSample())
 	cmd.AddCommand(NewCmdSensorDetail())
 
+	cmd.PersistentFlags().StringVarP(&sensorOwner, "owner", "", "", "sensor owner ID (slave address) of the sensor number, required if the sensor number is used by several controllers")
+	cmd.PersistentFlags().Uint8VarP(&sensorOwnerLUN, "owner-lun", "", 0, "sensor owner LUN of the sensor number, used with --owner")
+
 	return cmd
 }
 
@@ -74,6 +93,12 @@ func NewCmdSensorList() *cobra.Command {
 			}
 
 			fmt.Println(ipmi.FormatSensors(extended, sensors...))
+
+			for _, sensor := range sensors {
+				if sensor.Err != nil {
+					fmt.Fprintf(os.Stderr, "%s\n", sensor.Err)
+				}
+			}
 		},
 	}
 
@@ -91,78 +116,263 @@ func NewCmdSensorGet() *cobra.Command {
 		Use:   "get",
 		Short: "get",
 		Run: func(cmd *cobra.Command, args []string) {
-			var sensorNumber uint8
-
 			if len(args) < 1 {
 				CheckErr(fmt.Errorf("no Sensor ID or Sensor Name supplied, usage: %s", usage))
 			}
 
-			var sensor *ipmi.Sensor
-			var err error
+			sensor := getSensor(args[0])
 
-			id, err := parseStringToInt64(args[0])
-			if err != nil {
-				// suppose args is sensor name
-				sensor, err = client.GetSensorByName(args[0])
+			client.Debug("sensor", sensor)
+			fmt.Println(sensor)
+		},
+	}
+	return cmd
+}
+
+func NewCmdSensorThreshold() *cobra.Command {
+	usage := `
+sensor threshold get <sensor>
+sensor threshold set <sensor> <lnr|lcr|lnc|unc|ucr|unr> <value>
+sensor threshold set <sensor> lower <lnr> <lcr> <lnc>
+sensor threshold set <sensor> upper <unc> <ucr> <unr>
+
+<sensor> is the sensor number or name, sensor name should be quoted if contains space
+	`
+	cmd := &cobra.Command{
+		Use:     "threshold",
+		Aliases: []string{"thresh"},
+		Short:   "threshold",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 2 {
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			action := args[0]
+
+			switch action {
+			case "get":
+				sensor := getSensor(args[1])
+
+				var res *ipmi.GetSensorThresholdsResponse
+				err := client.WithSensorOwner(sensor, func() (err error) {
+					res, err = client.GetSensorThresholds(sensor.Number)
+					return
+				})
 				if err != nil {
-					CheckErr(fmt.Errorf("GetSensorByName failed, err: %s", err))
+					CheckErr(fmt.Errorf("GetSensorThresholds failed, err: %s", err))
+				}
+				fmt.Println(res.Format())
+			case "set":
+				if len(args) < 4 {
+					CheckErr(fmt.Errorf("usage: %s", usage))
 				}
-			} else {
-				sensorNumber = uint8(id)
-				sensor, err = client.GetSensorByID(sensorNumber)
+				sensor := getSensor(args[1])
+
+				values, err := parseThresholdValues(args[2], args[3:])
 				if err != nil {
-					CheckErr(fmt.Errorf("GetSensorByID failed, err: %s", err))
+					CheckErr(fmt.Errorf("%s, usage: %s", err, usage))
+				}
+
+				if err := client.SetSensorThresholdValuesFor(sensor, values); err != nil {
+					CheckErr(fmt.Errorf("SetSensorThresholdValues failed, err: %s", err))
 				}
+
+				fmt.Println(ipmi.FormatSensors(false, getSensor(args[1])))
+			default:
+				CheckErr(fmt.Errorf("usage: %s", usage))
 			}
 
-			client.Debug("sensor", sensor)
-			fmt.Println(sensor)
 		},
 	}
 	return cmd
 }
 
-func NewCmdSensorThreshold() *cobra.Command {
+// parseThresholdValues parses the threshold values of "sensor threshold set".
+func parseThresholdValues(name string, args []string) (map[ipmi.SensorThresholdType]float64, error) {
+	var thresholdTypes []ipmi.SensorThresholdType
+
+	switch name {
+	case "lower":
+		thresholdTypes = []ipmi.SensorThresholdType{ipmi.SensorThresholdType_LNR, ipmi.SensorThresholdType_LCR, ipmi.SensorThresholdType_LNC}
+	case "upper":
+		thresholdTypes = []ipmi.SensorThresholdType{ipmi.SensorThresholdType_UNC, ipmi.SensorThresholdType_UCR, ipmi.SensorThresholdType_UNR}
+	default:
+		for _, t := range []ipmi.SensorThresholdType{
+			ipmi.SensorThresholdType_LNR, ipmi.SensorThresholdType_LCR, ipmi.SensorThresholdType_LNC,
+			ipmi.SensorThresholdType_UNC, ipmi.SensorThresholdType_UCR, ipmi.SensorThresholdType_UNR,
+		} {
+			if t.Abbr() == name {
+				thresholdTypes = []ipmi.SensorThresholdType{t}
+			}
+		}
+		if len(thresholdTypes) == 0 {
+			return nil, fmt.Errorf("unknown threshold %s", name)
+		}
+	}
+
+	if len(args) != len(thresholdTypes) {
+		return nil, fmt.Errorf("%s requires %d values", name, len(thresholdTypes))
+	}
+
+	values := make(map[ipmi.SensorThresholdType]float64)
+	for i, t := range thresholdTypes {
+		v, err := strconv.ParseFloat(args[i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %s", t.Abbr(), args[i])
+		}
+		values[t] = v
+	}
+	return values, nil
+}
+
+func NewCmdSensorHysteresis() *cobra.Command {
 	usage := `
-sensor threshold get <sensor_number>
+sensor hysteresis get <sensor>
+sensor hysteresis set <sensor> <positive> <negative>
+
+<sensor> is the sensor number or name, sensor name should be quoted if contains space
 	`
 	cmd := &cobra.Command{
-		Use:   "threshold",
-		Short: "threshold",
+		Use:   "hysteresis",
+		Short: "hysteresis",
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) < 2 {
 				CheckErr(fmt.Errorf("usage: %s", usage))
 			}
 
 			action := args[0]
-
-			var sensorNumber uint8
-			i, err := parseStringToInt64(args[1])
-			if err != nil {
-				CheckErr(fmt.Errorf("invalid sensor number, err: %s", err))
-			}
-			sensorNumber = uint8(i)
+			sensor := getSensor(args[1])
 
 			switch action {
 			case "get":
-				res, err := client.GetSensorThresholds(sensorNumber)
+				var res *ipmi.GetSensorHysteresisResponse
+				err := client.WithSensorOwner(sensor, func() (err error) {
+					res, err = client.GetSensorHysteresis(sensor.Number)
+					return
+				})
 				if err != nil {
-					CheckErr(fmt.Errorf("GetSensorThresholds failed, err: %s", err))
+					CheckErr(fmt.Errorf("GetSensorHysteresis failed, err: %s", err))
 				}
 				fmt.Println(res.Format())
+				fmt.Printf("Positive Hysteresis: %s\n", sensor.HysteresisStr(res.PositiveRaw))
+				fmt.Printf("Negative Hysteresis: %s\n", sensor.HysteresisStr(res.NegativeRaw))
 			case "set":
+				if len(args) < 4 {
+					CheckErr(fmt.Errorf("usage: %s", usage))
+				}
+				positive, err := strconv.ParseFloat(args[2], 64)
+				if err != nil {
+					CheckErr(fmt.Errorf("invalid positive hysteresis value, err: %s", err))
+				}
+				negative, err := strconv.ParseFloat(args[3], 64)
+				if err != nil {
+					CheckErr(fmt.Errorf("invalid negative hysteresis value, err: %s", err))
+				}
+
+				if err := client.SetSensorHysteresisValuesFor(sensor, positive, negative); err != nil {
+					CheckErr(fmt.Errorf("SetSensorHysteresisValues failed, err: %s", err))
+				}
 			default:
 				CheckErr(fmt.Errorf("usage: %s", usage))
 			}
+		},
+	}
+	return cmd
+}
 
+func NewCmdSensorProfile() *cobra.Command {
+	usage := `
+sensor profile diff <profile.json>
+sensor profile apply <profile.json>
+	`
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "compare or apply the sensor thresholds, hysteresis and event enables described by a json profile",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 2 {
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			action := args[0]
+			if action != "diff" && action != "apply" {
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			data, err := os.ReadFile(args[1])
+			if err != nil {
+				CheckErr(fmt.Errorf("read profile file failed, err: %s", err))
+			}
+			profile, err := ipmi.ParseSensorProfile(data)
+			if err != nil {
+				CheckErr(fmt.Errorf("ParseSensorProfile failed, err: %s", err))
+			}
+
+			diffs, err := client.DiffSensorProfile(profile)
+			if err != nil {
+				CheckErr(fmt.Errorf("DiffSensorProfile failed, err: %s", err))
+			}
+			if len(diffs) == 0 {
+				fmt.Println("sensors match the profile, no changes")
+				return
+			}
+			fmt.Println(ipmi.FormatSensorProfileDiffs(diffs))
+
+			var errCount int
+			for _, diff := range diffs {
+				if diff.Err != nil {
+					errCount++
+				}
+			}
+
+			if action == "apply" {
+				if err := client.ApplySensorProfile(diffs); err != nil {
+					CheckErr(fmt.Errorf("ApplySensorProfile failed, err: %s", err))
+				}
+				fmt.Println("profile applied")
+			}
+			if errCount > 0 {
+				CheckErr(fmt.Errorf("%d sensors can not be compared with the profile", errCount))
+			}
 		},
 	}
 	return cmd
 }
 
+// getSensor returns the sensor by the sensor number or sensor name.
+// The sensor number is looked up with the sensor owner if --owner is specified.
+func getSensor(arg string) *ipmi.Sensor {
+	id, err := parseStringToInt64(arg)
+	if err != nil {
+		// suppose arg is sensor name
+		sensor, err := client.GetSensorByName(arg)
+		if err != nil {
+			CheckErr(fmt.Errorf("GetSensorByName failed, err: %s", err))
+		}
+		return sensor
+	}
+
+	if sensorOwner != "" {
+		owner, err := parseStringToInt64(sensorOwner)
+		if err != nil {
+			CheckErr(fmt.Errorf("invalid sensor owner, err: %s", err))
+		}
+		sensor, err := client.GetSensorByOwner(uint8(owner), sensorOwnerLUN, uint8(id))
+		if err != nil {
+			CheckErr(fmt.Errorf("GetSensorByOwner failed, err: %s", err))
+		}
+		return sensor
+	}
+
+	sensor, err := client.GetSensorByID(uint8(id))
+	if err != nil {
+		CheckErr(fmt.Errorf("GetSensorByID failed, err: %s", err))
+	}
+	return sensor
+}
+
 func NewCmdSensorEventStatus() *cobra.Command {
 	usage := `
-sensor event-status get <sensor_number>
+sensor event-status get <sensor>
 	`
 	cmd := &cobra.Command{
 		Use:   "event-status ",
@@ -173,17 +383,15 @@ sensor event-status get <sensor_number>
 			}
 
 			action := args[0]
-
-			var sensorNumber uint8
-			i, err := parseStringToInt64(args[1])
-			if err != nil {
-				CheckErr(fmt.Errorf("invalid sensor number, err: %s", err))
-			}
-			sensorNumber = uint8(i)
+			sensor := getSensor(args[1])
 
 			switch action {
 			case "get":
-				res, err := client.GetSensorEventStatus(sensorNumber)
+				var res *ipmi.GetSensorEventStatusResponse
+				err := client.WithSensorOwner(sensor, func() (err error) {
+					res, err = client.GetSensorEventStatus(sensor.Number)
+					return
+				})
 				if err != nil {
 					CheckErr(fmt.Errorf("GetSensorEventStatus failed, err: %s", err))
 				}
@@ -199,8 +407,18 @@ sensor event-status get <sensor_number>
 
 func NewCmdSensorEventEnable() *cobra.Command {
 	usage := `
-sensor event-enable get <sensor_number>
+sensor event-enable get <sensor>
+sensor event-enable set <sensor> [--messages on|off] [--scanning on|off]
+	[--enable <events>] [--disable <events>] [--enable-deassert <events>] [--disable-deassert <events>]
+
+<events> is a comma separated list of threshold events like "unc+" (going high), "lcr-" (going low),
+or discrete events like "state0" - "state14".
+<sensor> is the sensor number or name, sensor name should be quoted if contains space
 	`
+	var messages string
+	var scanning string
+	var enable, disable, enableDeassert, disableDeassert []string
+
 	cmd := &cobra.Command{
 		Use:   "event-enable ",
 		Short: "event-enable ",
@@ -210,33 +428,124 @@ sensor event-enable get <sensor_number>
 			}
 
 			action := args[0]
-
-			var sensorNumber uint8
-			i, err := parseStringToInt64(args[1])
-			if err != nil {
-				CheckErr(fmt.Errorf("invalid sensor number, err: %s", err))
+			sensor := getSensor(args[1])
+
+			getEventEnable := func() *ipmi.GetSensorEventEnableResponse {
+				var res *ipmi.GetSensorEventEnableResponse
+				err := client.WithSensorOwner(sensor, func() (err error) {
+					res, err = client.GetSensorEventEnable(sensor.Number)
+					return
+				})
+				if err != nil {
+					CheckErr(fmt.Errorf("GetSensorEventEnable failed, err: %s", err))
+				}
+				return res
 			}
-			sensorNumber = uint8(i)
 
 			switch action {
 			case "get":
-				res, err := client.GetSensorEventEnable(sensorNumber)
+				fmt.Println(getEventEnable().Format())
+			case "set":
+				update := ipmi.SensorEventEnableUpdate{
+					EventMessagesEnabled:  parseOnOff("messages", messages),
+					SensorScanningEnabled: parseOnOff("scanning", scanning),
+					Enable:                append(parseSensorEvents(enable, true), parseSensorEvents(enableDeassert, false)...),
+					Disable:               append(parseSensorEvents(disable, true), parseSensorEvents(disableDeassert, false)...),
+				}
+				err := client.WithSensorOwner(sensor, func() error {
+					return client.UpdateSensorEventEnable(sensor.Number, update)
+				})
 				if err != nil {
-					CheckErr(fmt.Errorf("GetSensorEventEnable failed, err: %s", err))
+					CheckErr(fmt.Errorf("UpdateSensorEventEnable failed, err: %s", err))
 				}
-				fmt.Println(res.Format())
-			case "set":
+
+				fmt.Println(getEventEnable().Format())
 			default:
 				CheckErr(fmt.Errorf("usage: %s", usage))
 			}
 		},
 	}
+
+	cmd.Flags().StringVarP(&messages, "messages", "", "", "enable (on) or disable (off) all event messages from the sensor")
+	cmd.Flags().StringVarP(&scanning, "scanning", "", "", "enable (on) or disable (off) the sensor scanning")
+	cmd.Flags().StringSliceVarP(&enable, "enable", "", nil, "assertion events to enable")
+	cmd.Flags().StringSliceVarP(&disable, "disable", "", nil, "assertion events to disable")
+	cmd.Flags().StringSliceVarP(&enableDeassert, "enable-deassert", "", nil, "deassertion events to enable")
+	cmd.Flags().StringSliceVarP(&disableDeassert, "disable-deassert", "", nil, "deassertion events to disable")
+
 	return cmd
 }
 
+func NewCmdSensorRearm() *cobra.Command {
+	usage := `
+sensor rearm <sensor> [--assert <events>] [--deassert <events>]
+
+All event status of the sensor are re-armed if no events are specified.
+<events> is a comma separated list of threshold events like "unc+" (going high), "lcr-" (going low),
+or discrete events like "state0" - "state14".
+<sensor> is the sensor number or name, sensor name should be quoted if contains space
+	`
+	var assert, deassert []string
+
+	cmd := &cobra.Command{
+		Use:   "rearm",
+		Short: "re-arm the events of the sensor",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 {
+				CheckErr(fmt.Errorf("usage: %s", usage))
+			}
+
+			sensor := getSensor(args[0])
+
+			events := append(parseSensorEvents(assert, true), parseSensorEvents(deassert, false)...)
+			err := client.WithSensorOwner(sensor, func() error {
+				_, err := client.RearmSensorEvents(sensor.Number, events...)
+				return err
+			})
+			if err != nil {
+				CheckErr(fmt.Errorf("RearmSensorEvents failed, err: %s", err))
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&assert, "assert", "", nil, "assertion events to re-arm")
+	cmd.Flags().StringSliceVarP(&deassert, "deassert", "", nil, "deassertion events to re-arm")
+
+	return cmd
+}
+
+func parseSensorEvents(list []string, assert bool) []ipmi.SensorEvent {
+	out := make([]ipmi.SensorEvent, 0)
+	for _, s := range list {
+		event, err := ipmi.ParseSensorEvent(s, assert)
+		if err != nil {
+			CheckErr(err)
+		}
+		out = append(out, event)
+	}
+	return out
+}
+
+// parseOnOff returns the bool of on or off value, or the defaultValue if value is empty.
+// parseOnOff returns nil if the value is empty (not changed).
+func parseOnOff(name string, value string) *bool {
+	var b bool
+	switch value {
+	case "":
+		return nil
+	case "on":
+		b = true
+	case "off":
+		b = false
+	default:
+		CheckErr(fmt.Errorf("invalid %s value %s, must be on or off", name, value))
+	}
+	return &b
+}
+
 func NewCmdSensorReading() *cobra.Command {
 	usage := `
-sensor reading get <sensor_number>
+sensor reading get <sensor>
 	`
 	cmd := &cobra.Command{
 		Use:   "reading",
@@ -247,17 +556,15 @@ sensor reading get <sensor_number>
 			}
 
 			action := args[0]
-
-			var sensorNumber uint8
-			i, err := parseStringToInt64(args[1])
-			if err != nil {
-				CheckErr(fmt.Errorf("invalid sensor number, err: %s", err))
-			}
-			sensorNumber = uint8(i)
+			sensor := getSensor(args[1])
 
 			switch action {
 			case "get":
-				res, err := client.GetSensorReading(sensorNumber)
+				var res *ipmi.GetSensorReadingResponse
+				err := client.WithSensorOwner(sensor, func() (err error) {
+					res, err = client.GetSensorReading(sensor.Number)
+					return
+				})
 				if err != nil {
 					CheckErr(fmt.Errorf("GetSensorReading failed, err: %s", err))
 				}
@@ -273,7 +580,7 @@ sensor reading get <sensor_number>
 
 func NewCmdSensorReadingFactors() *cobra.Command {
 	usage := `
-sensor reading-factors get <sensor_number>
+sensor reading-factors get <sensor>
 	`
 	cmd := &cobra.Command{
 		Use:   "reading-factors",
@@ -284,27 +591,27 @@ sensor reading-factors get <sensor_number>
 			}
 
 			action := args[0]
-
-			var sensorNumber uint8
-			i, err := parseStringToInt64(args[1])
-			if err != nil {
-				CheckErr(fmt.Errorf("invalid sensor number, err: %s", err))
-			}
-			sensorNumber = uint8(i)
+			sensor := getSensor(args[1])
 
 			switch action {
 			case "get":
-				res0, err := client.GetSensorReading(sensorNumber)
+				err := client.WithSensorOwner(sensor, func() error {
+					res0, err := client.GetSensorReading(sensor.Number)
+					if err != nil {
+						return fmt.Errorf("GetSensorReading failed, err: %s", err)
+					}
+					fmt.Println(res0.Format())
+
+					res, err := client.GetSensorReadingFactors(sensor.Number, res0.Reading)
+					if err != nil {
+						return fmt.Errorf("GetSensorReadingFactors failed, err: %s", err)
+					}
+					fmt.Println(res.Format())
+					return nil
+				})
 				if err != nil {
-					CheckErr(fmt.Errorf("GetSensorReading failed, err: %s", err))
+					CheckErr(err)
 				}
-				fmt.Println(res0.Format())
-
-				res, err := client.GetSensorReadingFactors(sensorNumber, res0.Reading)
-				if err != nil {
-					CheckErr(fmt.Errorf("GetSensorReadingFactors failed, err: %s", err))
-				}
-				fmt.Println(res.Format())
 			case "set":
 			default:
 				CheckErr(fmt.Errorf("usage: %s", usage))
@@ -316,7 +623,7 @@ sensor reading-factors get <sensor_number>
 
 func NewCmdSensorDetail() *cobra.Command {
 	usage := `
-sensor detail <sensor_number>
+sensor detail <sensor>
 	`
 
 	cmd := &cobra.Command{
@@ -328,24 +635,59 @@ sensor detail <sensor_number>
 				CheckErr(fmt.Errorf("usage: %s", usage))
 			}
 
-			var sensorNumber uint8
+			fmt.Println(getSensor(args[0]))
+		},
+	}
+	return cmd
+}
+
+func NewCmdSensorSample() *cobra.Command {
+	var interval time.Duration
+	var count int
+	var window int
+	var filterThreshold bool
+
+	cmd := &cobra.Command{
+		Use:   "sample",
+		Short: "sample the sensor readings periodically, and print the readings with min/max/avg statistics",
+		Run: func(cmd *cobra.Command, args []string) {
+			filterOptions := make([]ipmi.SensorFilterOption, 0)
+			if filterThreshold {
+				filterOptions = append(filterOptions, ipmi.SensorFilterOptionIsThreshold)
+			}
+
+			sampler := ipmi.NewSensorSampler(client, ipmi.SensorSamplerConfig{
+				Filters: filterOptions,
+				Window:  window,
+			})
 
-			if len(args) >= 1 {
-				i, err := parseStringToInt64(args[0])
-				if err != nil {
-					CheckErr(fmt.Errorf("invalid sensor number, err: %s", err))
+			sigChan := make(chan os.Signal, 1)
+			signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for n := 0; count <= 0 || n < count; n++ {
+				if n > 0 {
+					select {
+					case <-sigChan:
+						return
+					case <-ticker.C:
+					}
 				}
-				sensorNumber = uint8(i)
-			}
 
-			sensor, err := client.GetSensorByID(sensorNumber)
-			if err != nil {
-				if err != nil {
-					CheckErr(fmt.Errorf("GetSensorByID failed, err: %s", err))
+				if _, err := sampler.Sample(); err != nil {
+					CheckErr(fmt.Errorf("Sample failed, err: %s", err))
 				}
+				fmt.Println(ipmi.FormatSensorStats(sampler.Stats()))
 			}
-			fmt.Println(sensor)
 		},
 	}
+
+	cmd.Flags().DurationVarP(&interval, "interval", "", 10*time.Second, "interval to sample the sensors")
+	cmd.Flags().IntVarP(&count, "count", "c", 0, "number of samples to take, 0 means until interrupted")
+	cmd.Flags().IntVarP(&window, "window", "", 60, "number of the most recent samples the statistics are computed over")
+	cmd.Flags().BoolVarP(&filterThreshold, "threshold", "", false, "sample only threshold sensors")
+
 	return cmd
 }
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bougou/go-ipmi"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdWatchdog() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watchdog",
+		Short: "watchdog",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return initClient()
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			return closeClient()
+		},
+	}
+	cmd.AddCommand(NewCmdWatchdogDaemon())
+
+	return cmd
+}
+
+var (
+	watchdogTimerUses = map[string]ipmi.TimerUse{
+		"frb2":   ipmi.TimerUseBIOSFRB2,
+		"post":   ipmi.TimerUseBIOSPOST,
+		"osload": ipmi.TimerUseOSLoad,
+		"sms":    ipmi.TimerUseSMSOS,
+		"oem":    ipmi.TimerUseOEM,
+	}
+
+	watchdogTimeoutActions = map[string]ipmi.TimeoutAction{
+		"none":       ipmi.TimeoutActionNoAction,
+		"reset":      ipmi.TimeoutActionHardReset,
+		"poweroff":   ipmi.TimeoutActionPowerDown,
+		"powercycle": ipmi.TimeoutActionPowerCycle,
+	}
+
+	watchdogPreTimeoutInterrupts = map[string]ipmi.PreTimeoutInterrupt{
+		"none": ipmi.PreTimeoutInterruptNone,
+		"smi":  ipmi.PreTimeoutInterruptSMI,
+		"nmi":  ipmi.PreTimeoutInterruptNMI,
+		"msg":  ipmi.PreTimeoutInterruptMessaging,
+	}
+)
+
+// parseWatchdogTimerUse parses the timer use option of the watchdog commands.
+func parseWatchdogTimerUse(timerUse string) (ipmi.TimerUse, error) {
+	use, ok := watchdogTimerUses[timerUse]
+	if !ok {
+		return 0, fmt.Errorf("invalid timer use (%s), supported (frb2,post,osload,sms,oem)", timerUse)
+	}
+	return use, nil
+}
+
+// parseWatchdogOptions parses the timer use, timeout action and pre-timeout interrupt
+// options shared by "mc watchdog set" and "watchdog daemon".
+func parseWatchdogOptions(timerUse string, action string, preTimeoutInterrupt string) (ipmi.TimerUse, ipmi.TimeoutAction, ipmi.PreTimeoutInterrupt, error) {
+	use, err := parseWatchdogTimerUse(timerUse)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	timeoutAction, ok := watchdogTimeoutActions[action]
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid action (%s), supported (none,reset,poweroff,powercycle)", action)
+	}
+
+	interrupt, ok := watchdogPreTimeoutInterrupts[preTimeoutInterrupt]
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid pre-timeout interrupt (%s), supported (none,smi,nmi,msg)", preTimeoutInterrupt)
+	}
+
+	return use, timeoutAction, interrupt, nil
+}
+
+func NewCmdWatchdogDaemon() *cobra.Command {
+	var timerUse string
+	var action string
+	var timeout time.Duration
+	var interval time.Duration
+	var preTimeoutInterrupt string
+	var preTimeout time.Duration
+	var dontLog bool
+
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "arm the BMC watchdog timer and reset it periodically, disarm it on SIGTERM or SIGINT",
+		Run: func(cmd *cobra.Command, args []string) {
+			use, timeoutAction, interrupt, err := parseWatchdogOptions(timerUse, action, preTimeoutInterrupt)
+			if err != nil {
+				CheckErr(err)
+			}
+
+			config := ipmi.WatchdogDaemonConfig{
+				TimerUse:            use,
+				TimeoutAction:       timeoutAction,
+				Timeout:             timeout,
+				Interval:            interval,
+				PreTimeoutInterrupt: interrupt,
+				PreTimeout:          preTimeout,
+				DontLog:             dontLog,
+				OnExpired: func(timerUses []ipmi.TimerUse) {
+					names := make([]string, len(timerUses))
+					for i, timerUse := range timerUses {
+						names[i] = timerUse.String()
+					}
+					fmt.Printf("watchdog timer was found expired, timer use: %s\n", strings.Join(names, ", "))
+				},
+				OnPreTimeout: func(presentCountdown time.Duration) {
+					fmt.Printf("watchdog pre-timeout occurred, present countdown: %s\n", presentCountdown)
+				},
+				OnError: func(err error) {
+					fmt.Fprintf(os.Stderr, "watchdog daemon: %s\n", err)
+				},
+			}
+
+			daemon, err := ipmi.NewWatchdogDaemon(client, config)
+			if err != nil {
+				CheckErr(fmt.Errorf("NewWatchdogDaemon failed, err: %s", err))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sigChan := make(chan os.Signal, 1)
+			signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+			go func() {
+				sig := <-sigChan
+				fmt.Printf("received signal %s, disarm watchdog timer\n", sig)
+				cancel()
+			}()
+
+			if err := daemon.Run(ctx); err != nil {
+				CheckErr(fmt.Errorf("watchdog daemon failed, err: %s", err))
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&timerUse, "timer-use", "", "sms", "timer use, supported (frb2,post,osload,sms,oem)")
+	cmd.Flags().StringVarP(&action, "action", "", "reset", "timeout action, supported (none,reset,poweroff,powercycle)")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "", 15*time.Minute, "watchdog timeout, in 100ms resolution")
+	cmd.Flags().DurationVarP(&interval, "interval", "", 0, "interval to reset the watchdog timer, defaults to one third of the timeout")
+	cmd.Flags().StringVarP(&preTimeoutInterrupt, "pretimeout-interrupt", "", "none", "pre-timeout interrupt, supported (none,smi,nmi,msg)")
+	cmd.Flags().DurationVarP(&preTimeout, "pretimeout", "", 0, "pre-timeout interval, in seconds resolution")
+	cmd.Flags().BoolVarP(&dontLog, "dont-log", "", false, "don't log the timeout event")
+
+	return cmd
+}
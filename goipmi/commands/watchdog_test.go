@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/bougou/go-ipmi"
+)
+
+func Test_parseWatchdogOptions(t *testing.T) {
+	tests := []struct {
+		name                string
+		timerUse            string
+		action              string
+		preTimeoutInterrupt string
+		expectedUse         ipmi.TimerUse
+		expectedAction      ipmi.TimeoutAction
+		expectedInterrupt   ipmi.PreTimeoutInterrupt
+		expectedErr         bool
+	}{
+		{"defaults", "sms", "reset", "none", ipmi.TimerUseSMSOS, ipmi.TimeoutActionHardReset, ipmi.PreTimeoutInterruptNone, false},
+		{"frb2 powercycle nmi", "frb2", "powercycle", "nmi", ipmi.TimerUseBIOSFRB2, ipmi.TimeoutActionPowerCycle, ipmi.PreTimeoutInterruptNMI, false},
+		{"osload poweroff msg", "osload", "poweroff", "msg", ipmi.TimerUseOSLoad, ipmi.TimeoutActionPowerDown, ipmi.PreTimeoutInterruptMessaging, false},
+		{"invalid timer use", "bios", "reset", "none", 0, 0, 0, true},
+		{"invalid action", "sms", "halt", "none", 0, 0, 0, true},
+		{"invalid interrupt", "sms", "reset", "irq", 0, 0, 0, true},
+	}
+
+	for _, test := range tests {
+		use, action, interrupt, err := parseWatchdogOptions(test.timerUse, test.action, test.preTimeoutInterrupt)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("test %s failed, got err: %v, expected err: %v", test.name, err, test.expectedErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if use != test.expectedUse || action != test.expectedAction || interrupt != test.expectedInterrupt {
+			t.Errorf("test %s not matched, got: %v/%v/%v, expected: %v/%v/%v", test.name,
+				use, action, interrupt, test.expectedUse, test.expectedAction, test.expectedInterrupt)
+		}
+	}
+}
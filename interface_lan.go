@@ -132,6 +132,22 @@ func (c *Client) buildRawPayload(reqCmd Request) (PayloadType, []byte, error) {
 func (c *Client) exchangeLAN(request Request, response Response) error {
 	c.Debug(">> Command Request", request)
 
+	if c.target.bridged() {
+		if err := c.exchangeLANBridged(request, response); err != nil {
+			return err
+		}
+	} else {
+		if err := c.exchangeLANMsg(request, response); err != nil {
+			return err
+		}
+	}
+
+	c.Debug("<< Commmand Response", response)
+	return nil
+}
+
+// exchangeLANMsg sends the request to the BMC in one RMCP message and parses the response.
+func (c *Client) exchangeLANMsg(request Request, response Response) error {
 	rmcp, err := c.BuildRmcpRequest(request)
 	if err != nil {
 		return fmt.Errorf("build RMCP+ request msg failed, err: %s", err)
@@ -147,15 +163,79 @@ func (c *Client) exchangeLAN(request Request, response Response) error {
 	}
 	c.DebugBytes("recv", recv, 16)
 
-	if err := c.ParseRmcpResponse(recv, response); err != nil {
-		// Warn, must directly return err.
-		// The error returned by ParseRmcpResponse might be of *ResponseError type.
-		return err
+	// Warn, must directly return err.
+	// The error returned by ParseRmcpResponse might be of *ResponseError type.
+	return c.ParseRmcpResponse(recv, response)
+}
+
+// exchangeLANBridged sends the request to the target on IPMB (or other channels) through the BMC,
+// the request is encapsulated in Send Message command with tracking, like "ipmitool -b -t" does.
+//
+// The BMC returns the response of Send Message first, and the response of the target
+// is returned in a separate message. Some BMCs return the response of the target
+// in the response data of Send Message instead.
+func (c *Client) exchangeLANBridged(request Request, response Response) error {
+	t := c.target
+
+	ipmbReq := c.buildIPMBRequest(request, t)
+	c.Debug(">>>> IPMB Request", ipmbReq)
+
+	sendReq := &SendMessageRequest{
+		TrackMask:     0x01,
+		ChannelNumber: t.channel,
+		MessageData:   ipmbReq.Pack(),
 	}
+	sendRes := &SendMessageResponse{}
 
-	c.Debug("<< Commmand Response", response)
-	return nil
+	// the Send Message command itself is addressed to the BMC
+	c.target = target{}
+	err := c.exchangeLANMsg(sendReq, sendRes)
+	c.target = t
+	if err != nil {
+		return fmt.Errorf("SendMessage to target (%s) failed, err: %w", t, err)
+	}
 
+	data := sendRes.Data
+	if len(data) == 0 {
+		recv, err := c.udpClient.Receive(context.Background())
+		if err != nil {
+			return fmt.Errorf("receive bridged response from target (%s) failed, err: %s", t, err)
+		}
+		c.DebugBytes("recv bridged", recv, 16)
+
+		bridgedRes := &SendMessageResponse{}
+		if err := c.ParseRmcpResponse(recv, bridgedRes); err != nil {
+			return fmt.Errorf("parse bridged response from target (%s) failed, err: %w", t, err)
+		}
+		data = bridgedRes.Data
+	}
+
+	ipmbRes := &IPMIResponse{}
+	if err := ipmbRes.Unpack(data); err != nil {
+		return fmt.Errorf("unpack bridged response from target (%s) failed, err: %s", t, err)
+	}
+	c.Debug("<<<< IPMB Response", ipmbRes)
+
+	if ipmbRes.Command != request.Command().ID || ipmbRes.RequesterSequence != ipmbReq.RequesterSequence {
+		return fmt.Errorf("bridged response from target (%s) not matched, got command (%#02x) seq (%d), expected command (%#02x) seq (%d)",
+			t, ipmbRes.Command, ipmbRes.RequesterSequence, request.Command().ID, ipmbReq.RequesterSequence)
+	}
+
+	ccode := ipmbRes.CompletionCode
+	if ccode != 0x00 {
+		return &ResponseError{
+			completionCode: CompletionCode(ccode),
+			description:    fmt.Sprintf("ipmiRes CompletaionCode (%#02x) is not normal: %s", ccode, StrCC(response, ccode)),
+		}
+	}
+
+	if err := response.Unpack(ipmbRes.Data); err != nil {
+		return &ResponseError{
+			completionCode: 0x00,
+			description:    fmt.Sprintf("unpack response failed, err: %s", err),
+		}
+	}
+	return nil
 }
 
 // 13.14
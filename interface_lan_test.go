@@ -0,0 +1,209 @@
+package ipmi
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+// packTestIPMIMsg packs an IPMI message in the LAN/IPMB message format, the data of
+// responses starts with the completion code.
+func packTestIPMIMsg(addr1 uint8, netFn NetFn, lun1 uint8, addr2 uint8, seq uint8, lun2 uint8, cmd uint8, data []byte) []byte {
+	msg := &IPMIRequest{
+		ResponderAddr:     addr1,
+		NetFn:             netFn,
+		ResponderLUN:      lun1,
+		RequesterAddr:     addr2,
+		RequesterSequence: seq,
+		RequesterLUN:      lun2,
+		Command:           cmd,
+		CommandData:       data,
+	}
+	msg.ComputeChecksum()
+	return msg.Pack()
+}
+
+func packTestRmcp15(payload []byte) []byte {
+	rmcp := &Rmcp{
+		RmcpHeader: NewRmcpHeader(),
+		Session15: &Session15{
+			SessionHeader15: &SessionHeader15{
+				AuthType:      AuthTypeNone,
+				PayloadLength: uint8(len(payload)),
+			},
+			Payload: payload,
+		},
+	}
+	return rmcp.Pack()
+}
+
+// fakeBridgingBMC serves one bridged request on the conn. The bridged response is
+// returned in a separate message, or in the Send Message response if inline is true.
+func fakeBridgingBMC(t *testing.T, conn *net.UDPConn, inline bool, innerCC uint8, innerData []byte) {
+	buf := make([]byte, 1024)
+	n, clientAddr, err := conn.ReadFromUDP(buf)
+	if err != nil {
+		t.Errorf("fake BMC read failed, err: %s", err)
+		return
+	}
+
+	rmcp := &Rmcp{}
+	if err := rmcp.Unpack(buf[:n]); err != nil || rmcp.Session15 == nil {
+		t.Errorf("fake BMC unpack rmcp failed, err: %v", err)
+		return
+	}
+	outer := rmcp.Session15.Payload
+	// rsAddr, netFn/rsLUN, checksum, rqAddr, rqSeq/rqLUN, cmd, data, checksum
+	if outer[5] != CommandSendMessage.ID || outer[1]&0x03 != 0 {
+		t.Errorf("fake BMC expected Send Message to LUN 0, got cmd (%#02x) netFn/lun (%#02x)", outer[5], outer[1])
+		return
+	}
+	outerSeq := outer[4] >> 2
+	sendData := outer[6 : len(outer)-1]
+	if sendData[0] != 0x40|0x06 {
+		t.Errorf("fake BMC expected tracking on channel 6, got (%#02x)", sendData[0])
+		return
+	}
+	inner := sendData[1:]
+	if inner[0] != 0x2c || inner[1]&0x03 != 0x01 || inner[3] != BMC_SA {
+		t.Errorf("fake BMC expected IPMB request to (0x2c) LUN 1 from the BMC, got: %v", inner)
+		return
+	}
+	innerNetFn := NetFn(inner[1]>>2) + 1
+	innerSeq := inner[4] >> 2
+	innerCmd := inner[5]
+
+	innerRes := packTestIPMIMsg(BMC_SA, innerNetFn, 0, 0x2c, innerSeq, 0x01, innerCmd, append([]byte{innerCC}, innerData...))
+
+	sendResData := []byte{0x00}
+	if inline {
+		sendResData = append(sendResData, innerRes...)
+	}
+	sendRes := packTestIPMIMsg(RemoteConsole_SWID, NetFnAppResponse, 0, BMC_SA, outerSeq, 0, CommandSendMessage.ID, sendResData)
+	if _, err := conn.WriteToUDP(packTestRmcp15(sendRes), clientAddr); err != nil {
+		t.Errorf("fake BMC write failed, err: %s", err)
+		return
+	}
+	if inline {
+		return
+	}
+
+	bridgedRes := packTestIPMIMsg(RemoteConsole_SWID, NetFnAppResponse, 0, BMC_SA, outerSeq, 0, CommandSendMessage.ID, append([]byte{0x00}, innerRes...))
+	if _, err := conn.WriteToUDP(packTestRmcp15(bridgedRes), clientAddr); err != nil {
+		t.Errorf("fake BMC write failed, err: %s", err)
+	}
+}
+
+func Test_exchangeLANBridged(t *testing.T) {
+	tests := []struct {
+		name            string
+		inline          bool
+		innerCC         uint8
+		expectedReading uint8
+		expectedCC      uint8
+	}{
+		{"separate response", false, 0x00, 0x42, 0x00},
+		{"inline response", true, 0x00, 0x42, 0x00},
+		{"target error", false, 0xcb, 0, 0xcb},
+	}
+
+	for _, test := range tests {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			t.Fatalf("listen failed, err: %s", err)
+		}
+
+		c, err := NewClient("127.0.0.1", conn.LocalAddr().(*net.UDPAddr).Port, "admin", "admin")
+		if err != nil {
+			t.Fatalf("NewClient failed, err: %s", err)
+		}
+		c.Interface = InterfaceLan
+		c.v20 = false
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			fakeBridgingBMC(t, conn, test.inline, test.innerCC, []byte{0x42, 0xc0, 0x00})
+		}()
+
+		var res *GetSensorReadingResponse
+		err = c.withTarget(0x2c, 6, 1, func() (err error) {
+			res, err = c.GetSensorReading(0x05)
+			return
+		})
+		<-done
+		conn.Close()
+		c.udpClient.Close()
+
+		if test.expectedCC != 0 {
+			var resErr *ResponseError
+			if !errors.As(err, &resErr) || uint8(resErr.CompletionCode()) != test.expectedCC {
+				t.Errorf("test %s: expected completion code (%#02x), got err: %v", test.name, test.expectedCC, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %s: GetSensorReading failed, err: %s", test.name, err)
+			continue
+		}
+		if res.Reading != test.expectedReading {
+			t.Errorf("test %s: reading not matched, got: %#02x, expected: %#02x", test.name, res.Reading, test.expectedReading)
+		}
+	}
+}
+
+// testBMCRequest is a request received by the fake BMC of newTestLANClient.
+type testBMCRequest struct {
+	NetFn NetFn
+	LUN   uint8
+	Cmd   uint8
+	Data  []byte
+}
+
+// newTestLANClient returns a lan client connected to a fake BMC (IPMI v1.5 without authentication),
+// which replies each request with the completion code and the data returned by the handler.
+func newTestLANClient(t *testing.T, handler func(req *testBMCRequest) (cc uint8, data []byte)) *Client {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("listen failed, err: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, clientAddr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			rmcp := &Rmcp{}
+			if err := rmcp.Unpack(buf[:n]); err != nil || rmcp.Session15 == nil {
+				continue
+			}
+			msg := rmcp.Session15.Payload
+			if len(msg) < 7 {
+				continue
+			}
+			req := &testBMCRequest{
+				NetFn: NetFn(msg[1] >> 2),
+				LUN:   msg[1] & 0x03,
+				Cmd:   msg[5],
+				Data:  append([]byte{}, msg[6:len(msg)-1]...),
+			}
+			cc, data := handler(req)
+			res := packTestIPMIMsg(RemoteConsole_SWID, req.NetFn+1, 0, BMC_SA, msg[4]>>2, req.LUN, req.Cmd, append([]byte{cc}, data...))
+			if _, err := conn.WriteToUDP(packTestRmcp15(res), clientAddr); err != nil {
+				return
+			}
+		}
+	}()
+
+	c, err := NewClient("127.0.0.1", conn.LocalAddr().(*net.UDPAddr).Port, "admin", "admin")
+	if err != nil {
+		t.Fatalf("NewClient failed, err: %s", err)
+	}
+	c.Interface = InterfaceLan
+	c.v20 = false
+	t.Cleanup(func() { c.udpClient.Close() })
+	return c
+}
@@ -73,8 +73,8 @@ func (c *Client) closeOpen() error {
 }
 
 func (c *Client) exchangeOpen(request Request, response Response) error {
-	if c.openipmi.targetAddr != 0 && c.openipmi.targetAddr != c.openipmi.myAddr {
-
+	if c.target.bridged() {
+		c.Debugf("\nSending request [%s] (%#02x) to IPMB target (%s)\n", request.Command().Name, request.Command().ID, c.target)
 	} else {
 		// otherwise use system interface
 		c.Debugf("\nSending request [%s] (%#02x) to System Interface\n", request.Command().Name, request.Command().ID)
@@ -136,11 +136,25 @@ func (c *Client) openSendRequest(request Request) ([]byte, error) {
 	addr := &open.IPMI_SYSTEM_INTERFACE_ADDR{
 		AddrType: open.IPMI_SYSTEM_INTERFACE_ADDR_TYPE,
 		Channel:  open.IPMI_BMC_CHANNEL,
+		LUN:      c.target.lun,
+	}
+	addrLen := int(unsafe.Sizeof(addr))
+
+	if c.target.bridged() {
+		// the kernel driver bridges the request to the IPMB address
+		ipmbAddr := &open.IPMI_IPMB_ADDR{
+			AddrType:  open.IPMI_IPMB_ADDR_TYPE,
+			Channel:   uint16(c.target.channel),
+			SlaveAddr: c.target.addr,
+			LUN:       c.target.lun,
+		}
+		addr = (*open.IPMI_SYSTEM_INTERFACE_ADDR)(unsafe.Pointer(ipmbAddr))
+		addrLen = int(unsafe.Sizeof(*ipmbAddr))
 	}
 
 	req := &open.IPMI_REQ{
 		Addr:    addr,
-		AddrLen: int(unsafe.Sizeof(addr)),
+		AddrLen: addrLen,
 		MsgID:   rand.Int63(),
 		Msg:     *msg,
 	}
@@ -31,7 +31,15 @@ func (c *Client) exchangeTool(request Request, response Response) error {
 	msg[1] = uint8(request.Command().ID)
 	copy(msg[2:], data)
 
-	args := append([]string{"raw"}, rawEncode(msg)...)
+	args := []string{}
+	if c.target.bridged() {
+		args = append(args, "-b", strconv.Itoa(int(c.target.channel)), "-t", fmt.Sprintf("%#02x", c.target.addr))
+	}
+	if c.target.lun != 0 {
+		args = append(args, "-l", strconv.Itoa(int(c.target.lun)))
+	}
+	args = append(args, "raw")
+	args = append(args, rawEncode(msg)...)
 
 	path := c.Host
 	if path == "" {
@@ -0,0 +1,299 @@
+package ipmi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+)
+
+// SELWatcherConfig is the configuration of SELWatcher.
+type SELWatcherConfig struct {
+	// Interval is how often the SEL Info is polled, defaults to 5 seconds.
+	Interval time.Duration
+
+	// FromStart delivers the existing SEL entries on the first poll,
+	// otherwise only the entries added after the watcher started are delivered.
+	FromStart bool
+
+	// LastRecordID is the record ID of the last entry the caller has already seen, if not 0,
+	// the entries after it are delivered on the first poll and FromStart is ignored.
+	// It is used to follow the SEL without a gap after reading the existing entries.
+	LastRecordID uint16
+
+	// OnSEL is called for each new SEL entry, in the order of the SEL.
+	OnSEL func(sel *SEL)
+
+	// OnCleared is called when the SEL is found cleared (the most recent erase time changed).
+	OnCleared func(eraseTime time.Time)
+
+	// OnOverflow is called when the SEL overflow flag is found set,
+	// which means events were dropped because the SEL is full.
+	OnOverflow func()
+
+	// OnWrapped is called when the last seen entry was overwritten or deleted,
+	// which happens if the SEL is circular and wrapped around.
+	OnWrapped func()
+
+	// OnError is called when a poll fails, the watcher keeps polling.
+	// If it is nil, Run returns on the error.
+	OnError func(err error)
+}
+
+// SELWatcher follows the SEL and delivers the new entries.
+// It remembers the last seen record ID and the timestamps of SEL Info,
+// so only the new entries are fetched.
+type SELWatcher struct {
+	client *Client
+	config SELWatcherConfig
+
+	started      bool
+	lastRecordID uint16
+	lastData     []byte
+	additionTime time.Time
+	eraseTime    time.Time
+	overflow     bool
+
+	err error
+}
+
+func NewSELWatcher(client *Client, config SELWatcherConfig) *SELWatcher {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+	}
+	return &SELWatcher{
+		client: client,
+		config: config,
+	}
+}
+
+// Run polls the SEL every Interval and calls the callbacks until ctx is done.
+// The errors of polling are passed to OnError and the next poll retries,
+// or returned if OnError is not set.
+func (w *SELWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		// the entries fetched before the poll failed are still delivered.
+		sels, err := w.Poll()
+		if w.config.OnSEL != nil {
+			for _, sel := range sels {
+				w.config.OnSEL(sel)
+			}
+		}
+		if err != nil {
+			err = fmt.Errorf("poll sel failed, err: %w", err)
+			if w.config.OnError == nil {
+				return err
+			}
+			w.config.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Watch runs the watcher in background and delivers the new SEL entries on the returned channel,
+// the OnSEL callback of the config is not used. The channel is closed when ctx is done,
+// or when a poll fails if OnError is not set, then Err returns the error.
+func (w *SELWatcher) Watch(ctx context.Context) <-chan *SEL {
+	out := make(chan *SEL)
+
+	w.config.OnSEL = func(sel *SEL) {
+		select {
+		case out <- sel:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(out)
+		w.err = w.Run(ctx)
+	}()
+
+	return out
+}
+
+// Err returns the error which stopped Watch, it must be called after the channel of Watch is closed.
+func (w *SELWatcher) Err() error {
+	return w.err
+}
+
+// Poll fetches and returns the SEL entries added since the last poll.
+// The callbacks except OnSEL are called during polling.
+func (w *SELWatcher) Poll() ([]*SEL, error) {
+	selInfo, err := w.client.GetSELInfo()
+	if err != nil {
+		return nil, fmt.Errorf("GetSELInfo failed, err: %s", err)
+	}
+
+	if selInfo.OperationSupport.Overflow && !w.overflow && w.config.OnOverflow != nil {
+		w.config.OnOverflow()
+	}
+	w.overflow = selInfo.OperationSupport.Overflow
+
+	if !w.started {
+		return w.start(selInfo)
+	}
+
+	if !selInfo.RecentEraseTime.Equal(w.eraseTime) {
+		w.eraseTime = selInfo.RecentEraseTime
+		if w.config.OnCleared != nil {
+			w.config.OnCleared(selInfo.RecentEraseTime)
+		}
+		w.additionTime = time.Time{}
+		return w.fetch(selInfo, 0, false)
+	}
+
+	if selInfo.RecentAdditionTime.Equal(w.additionTime) {
+		return nil, nil
+	}
+
+	if w.lastRecordID == 0 {
+		return w.fetch(selInfo, 0, false)
+	}
+
+	// check whether the last seen entry is still there, and find the entry after it.
+	selEntry, err := w.client.GetSELEntry(0, w.lastRecordID)
+	if err != nil && !isResponseErrorCC(err, uint8(CompletionCodeRequestedDataNotPresent)) {
+		return nil, fmt.Errorf("GetSELEntry failed, err: %s", err)
+	}
+	if err != nil || !bytes.Equal(selEntry.Data, w.lastData) {
+		if w.config.OnWrapped != nil {
+			w.config.OnWrapped()
+		}
+		return w.fetch(selInfo, 0, true)
+	}
+	if selEntry.NextRecordID == 0xffff {
+		w.additionTime = selInfo.RecentAdditionTime
+		return nil, nil
+	}
+	return w.fetch(selInfo, selEntry.NextRecordID, false)
+}
+
+// start initializes the watcher state on the first poll.
+func (w *SELWatcher) start(selInfo *GetSELInfoResponse) ([]*SEL, error) {
+	w.eraseTime = selInfo.RecentEraseTime
+
+	if w.config.LastRecordID != 0 {
+		return w.startAfter(selInfo, w.config.LastRecordID)
+	}
+
+	if w.config.FromStart || selInfo.Entries == 0 {
+		sels, err := w.fetch(selInfo, 0, false)
+		if err != nil {
+			return nil, err
+		}
+		w.started = true
+		return sels, nil
+	}
+
+	// remember the last entry
+	selEntry, err := w.client.GetSELEntry(0, 0xffff)
+	if err != nil {
+		return nil, fmt.Errorf("GetSELEntry failed, err: %s", err)
+	}
+	sel, err := ParseSEL(selEntry.Data)
+	if err != nil {
+		return nil, fmt.Errorf("ParseSEL failed, err: %s", err)
+	}
+	w.lastRecordID = sel.RecordID
+	w.lastData = selEntry.Data
+	w.additionTime = selInfo.RecentAdditionTime
+	w.started = true
+	return nil, nil
+}
+
+// startAfter initializes the watcher state after the entry with the record ID,
+// and returns the entries after it.
+func (w *SELWatcher) startAfter(selInfo *GetSELInfoResponse, lastRecordID uint16) ([]*SEL, error) {
+	selEntry, err := w.client.GetSELEntry(0, lastRecordID)
+	if err != nil && !isResponseErrorCC(err, uint8(CompletionCodeRequestedDataNotPresent)) {
+		return nil, fmt.Errorf("GetSELEntry failed, err: %s", err)
+	}
+
+	var sels []*SEL
+	if err != nil {
+		// the entry was deleted, or the SEL was cleared
+		if w.config.OnWrapped != nil {
+			w.config.OnWrapped()
+		}
+		sels, err = w.fetch(selInfo, 0, false)
+	} else {
+		w.lastRecordID = lastRecordID
+		w.lastData = selEntry.Data
+		w.additionTime = selInfo.RecentAdditionTime
+		if selEntry.NextRecordID != 0xffff {
+			sels, err = w.fetch(selInfo, selEntry.NextRecordID, false)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	w.started = true
+	return sels, nil
+}
+
+// fetch gets the SEL entries starting from the record ID.
+// If afterLastSeen is true, only the entries which are not logged before the last seen addition time
+// are returned, and the non-timestamped OEM entries are skipped.
+func (w *SELWatcher) fetch(selInfo *GetSELInfoResponse, startRecordID uint16, afterLastSeen bool) ([]*SEL, error) {
+	out := make([]*SEL, 0)
+	if selInfo.Entries == 0 {
+		w.lastRecordID = 0
+		w.lastData = nil
+		w.additionTime = selInfo.RecentAdditionTime
+		return out, nil
+	}
+
+	lastData := w.lastData
+	recordID := startRecordID
+	for {
+		selEntry, err := w.client.GetSELEntry(0, recordID)
+		if err != nil {
+			return out, fmt.Errorf("GetSELEntry failed, err: %s", err)
+		}
+
+		sel, err := ParseSEL(selEntry.Data)
+		if err != nil {
+			return out, fmt.Errorf("ParseSEL failed, err: %s", err)
+		}
+
+		if !afterLastSeen || w.isNew(sel, selEntry.Data, lastData) {
+			out = append(out, sel)
+		}
+		w.lastRecordID = sel.RecordID
+		w.lastData = selEntry.Data
+
+		recordID = selEntry.NextRecordID
+		if recordID == 0xffff {
+			break
+		}
+	}
+
+	w.additionTime = selInfo.RecentAdditionTime
+	return out, nil
+}
+
+// isNew checks whether the entry was added after the last seen entry, used after the SEL wrapped.
+func (w *SELWatcher) isNew(sel *SEL, data []byte, lastData []byte) bool {
+	if bytes.Equal(data, lastData) {
+		return false
+	}
+
+	var timestamp time.Time
+	switch sel.RecordType.Range() {
+	case SELRecordTypeRangeStandard:
+		timestamp = sel.Standard.Timestamp
+	case SELRecordTypeRangeTimestampedOEM:
+		timestamp = sel.OEMTimestamped.Timestamp
+	default:
+		return false
+	}
+	return !timestamp.Before(w.additionTime)
+}
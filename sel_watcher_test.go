@@ -0,0 +1,323 @@
+package ipmi
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSEL is a fake SEL served by the fake BMC.
+type fakeSEL struct {
+	records      [][]byte
+	additionTime uint32
+	eraseTime    uint32
+	overflow     bool
+	// the number of Get SEL Entry failures to reply
+	failures int
+}
+
+// add appends a standard SEL record with the record ID and timestamp.
+func (s *fakeSEL) add(recordID uint16, timestamp uint32) {
+	record := make([]byte, 16)
+	packUint16L(recordID, record, 0)
+	record[2] = 0x02
+	packUint32L(timestamp, record, 3)
+	record[7] = 0x20
+	record[9] = 0x04
+	record[10] = 0x01
+	record[11] = uint8(recordID)
+	record[12] = 0x01
+	s.records = append(s.records, record)
+	s.additionTime = timestamp
+}
+
+func (s *fakeSEL) clear(timestamp uint32) {
+	s.records = nil
+	s.eraseTime = timestamp
+}
+
+func (s *fakeSEL) handle(req *testBMCRequest) (uint8, []byte) {
+	switch req.Cmd {
+	case CommandGetSELInfo.ID:
+		out := make([]byte, 14)
+		out[0] = 0x51
+		packUint16L(uint16(len(s.records)), out, 1)
+		packUint32L(s.additionTime, out, 5)
+		packUint32L(s.eraseTime, out, 9)
+		if s.overflow {
+			out[13] = setBit7(out[13])
+		}
+		return 0x00, out
+
+	case CommandGetSELEntry.ID:
+		if s.failures > 0 {
+			s.failures--
+			return 0xff, nil
+		}
+		if len(s.records) == 0 {
+			return uint8(CompletionCodeRequestedDataNotPresent), nil
+		}
+		recordID, _, _ := unpackUint16L(req.Data, 2)
+		index := -1
+		switch recordID {
+		case 0x0000:
+			index = 0
+		case 0xffff:
+			index = len(s.records) - 1
+		default:
+			for i, record := range s.records {
+				if id, _, _ := unpackUint16L(record, 0); id == recordID {
+					index = i
+				}
+			}
+		}
+		if index < 0 {
+			return uint8(CompletionCodeRequestedDataNotPresent), nil
+		}
+		out := make([]byte, 2)
+		packUint16L(0xffff, out, 0)
+		if index+1 < len(s.records) {
+			copy(out, s.records[index+1][0:2])
+		}
+		return 0x00, append(out, s.records[index]...)
+	}
+	return 0xc1, nil
+}
+
+func recordIDs(sels []*SEL) []uint16 {
+	out := make([]uint16, len(sels))
+	for i, sel := range sels {
+		out[i] = sel.RecordID
+	}
+	return out
+}
+
+func Test_SELWatcher_Poll(t *testing.T) {
+	sel := &fakeSEL{}
+	sel.add(1, 0x60000001)
+	sel.add(2, 0x60000002)
+
+	var cleared, overflows, wraps int
+	c := newTestLANClient(t, sel.handle)
+	w := NewSELWatcher(c, SELWatcherConfig{
+		OnCleared:  func(eraseTime time.Time) { cleared++ },
+		OnOverflow: func() { overflows++ },
+		OnWrapped:  func() { wraps++ },
+	})
+
+	tests := []struct {
+		name   string
+		change func()
+
+		expected          []uint16
+		expectedCleared   int
+		expectedOverflows int
+		expectedWraps     int
+	}{
+		{
+			name:     "start",
+			change:   func() {},
+			expected: []uint16{},
+		},
+		{
+			name: "added",
+			change: func() {
+				sel.add(3, 0x60000003)
+				sel.add(4, 0x60000004)
+			},
+			expected: []uint16{3, 4},
+		},
+		{
+			name:     "not changed",
+			change:   func() {},
+			expected: []uint16{},
+		},
+		{
+			name: "cleared",
+			change: func() {
+				sel.clear(0x60000005)
+				sel.add(1, 0x60000006)
+			},
+			expected:        []uint16{1},
+			expectedCleared: 1,
+		},
+		{
+			name: "wrapped",
+			change: func() {
+				// the circular SEL overwrites the oldest entries, including the last seen one
+				sel.records = sel.records[1:]
+				sel.add(2, 0x60000007)
+				sel.add(3, 0x60000008)
+			},
+			expected:        []uint16{2, 3},
+			expectedCleared: 1,
+			expectedWraps:   1,
+		},
+		{
+			name: "overflow",
+			change: func() {
+				sel.overflow = true
+			},
+			expected:          []uint16{},
+			expectedCleared:   1,
+			expectedOverflows: 1,
+			expectedWraps:     1,
+		},
+		{
+			name: "still overflow",
+			change: func() {
+				sel.add(4, 0x60000009)
+			},
+			expected:          []uint16{4},
+			expectedCleared:   1,
+			expectedOverflows: 1,
+			expectedWraps:     1,
+		},
+	}
+
+	for _, test := range tests {
+		test.change()
+		sels, err := w.Poll()
+		if err != nil {
+			t.Errorf("test %s: Poll failed, err: %s", test.name, err)
+			continue
+		}
+		if got := recordIDs(sels); !equalRecordIDs(got, test.expected) {
+			t.Errorf("test %s not matched, got: %v, expected: %v", test.name, got, test.expected)
+		}
+		if cleared != test.expectedCleared || overflows != test.expectedOverflows || wraps != test.expectedWraps {
+			t.Errorf("test %s callbacks not matched, got: cleared %d overflows %d wraps %d, expected: cleared %d overflows %d wraps %d",
+				test.name, cleared, overflows, wraps, test.expectedCleared, test.expectedOverflows, test.expectedWraps)
+		}
+	}
+}
+
+func Test_SELWatcher_LastRecordID(t *testing.T) {
+	tests := []struct {
+		name         string
+		lastRecordID uint16
+		expected     []uint16
+		expectedWrap bool
+	}{
+		{
+			name:         "after the last printed",
+			lastRecordID: 2,
+			expected:     []uint16{3, 4},
+		},
+		{
+			name:         "nothing added",
+			lastRecordID: 4,
+			expected:     []uint16{},
+		},
+		{
+			name:         "last printed deleted",
+			lastRecordID: 9,
+			expected:     []uint16{1, 2, 3, 4},
+			expectedWrap: true,
+		},
+	}
+
+	for _, test := range tests {
+		sel := &fakeSEL{}
+		for i := uint16(1); i <= 4; i++ {
+			sel.add(i, 0x60000000+uint32(i))
+		}
+
+		wrapped := false
+		c := newTestLANClient(t, sel.handle)
+		w := NewSELWatcher(c, SELWatcherConfig{
+			LastRecordID: test.lastRecordID,
+			OnWrapped:    func() { wrapped = true },
+		})
+
+		sels, err := w.Poll()
+		if err != nil {
+			t.Errorf("test %s: Poll failed, err: %s", test.name, err)
+			continue
+		}
+		got := recordIDs(sels)
+		if !equalRecordIDs(got, test.expected) {
+			t.Errorf("test %s not matched, got: %v, expected: %v", test.name, got, test.expected)
+		}
+		if wrapped != test.expectedWrap {
+			t.Errorf("test %s wrapped not matched, got: %v, expected: %v", test.name, wrapped, test.expectedWrap)
+		}
+
+		sel.add(5, 0x60000005)
+		sels, err = w.Poll()
+		if err != nil {
+			t.Errorf("test %s: Poll failed, err: %s", test.name, err)
+			continue
+		}
+		if got := recordIDs(sels); !equalRecordIDs(got, []uint16{5}) {
+			t.Errorf("test %s next poll not matched, got: %v, expected: %v", test.name, got, []uint16{5})
+		}
+	}
+}
+
+func Test_SELWatcher_Run(t *testing.T) {
+	tests := []struct {
+		name        string
+		onError     bool
+		expectedErr string
+	}{
+		{
+			name:    "reported",
+			onError: true,
+		},
+		{
+			name:        "returned",
+			expectedErr: "poll sel failed",
+		},
+	}
+
+	for _, test := range tests {
+		sel := &fakeSEL{failures: 1}
+		sel.add(1, 0x60000001)
+
+		var errs []error
+		var sels []uint16
+		c := newTestLANClient(t, sel.handle)
+		config := SELWatcherConfig{
+			Interval:  10 * time.Millisecond,
+			FromStart: true,
+			OnSEL:     func(sel *SEL) { sels = append(sels, sel.RecordID) },
+		}
+		if test.onError {
+			config.OnError = func(err error) { errs = append(errs, err) }
+		}
+		w := NewSELWatcher(c, config)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		err := w.Run(ctx)
+		cancel()
+
+		if test.expectedErr == "" {
+			if err != nil {
+				t.Errorf("test %s: Run failed, err: %s", test.name, err)
+			}
+			if len(errs) != 1 {
+				t.Errorf("test %s: reported errors not matched, got: %v, expected: %d", test.name, errs, 1)
+			}
+			// the failed poll is retried
+			if !equalRecordIDs(sels, []uint16{1}) {
+				t.Errorf("test %s: delivered not matched, got: %v, expected: %v", test.name, sels, []uint16{1})
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+			t.Errorf("test %s: error not matched, got: %v, expected: %s", test.name, err, test.expectedErr)
+		}
+	}
+}
+
+func equalRecordIDs(a []uint16, b []uint16) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,362 @@
+package ipmi
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// SensorSamplerConfig is the configuration of SensorSampler.
+type SensorSamplerConfig struct {
+	// Filters selects the sensors to sample, see GetSensors.
+	Filters []SensorFilterOption
+
+	// Clients are the extra clients used to sample the sensors in parallel with the client of the sampler.
+	// A client can not issue requests concurrently, so each client (which holds its own session)
+	// samples a part of the sensors. The clients must be connected to the same BMC.
+	Clients []*Client
+
+	// Window is the number of the most recent valid samples of each sensor
+	// the statistics are computed over, defaults to 60.
+	Window int
+}
+
+// SensorSample is a reading of a sensor at a time.
+type SensorSample struct {
+	Sensor *Sensor
+	Time   time.Time
+
+	// Raw reading value before conversion
+	Raw uint8
+	// reading value after conversion
+	Value float64
+
+	ThresholdStatus SensorThresholdStatus
+	ActiveStates    Mask_DiscreteEvent
+
+	ScanningDisabled   bool
+	ReadingUnavailable bool
+
+	// Err is the error of Get Sensor Reading, the other fields are not meaningful if it is set.
+	Err error
+
+	optionalData1 uint8
+	optionalData2 uint8
+}
+
+// IsValid returns whether the sample holds a valid reading.
+func (sample *SensorSample) IsValid() bool {
+	return sample.Err == nil && !sample.ScanningDisabled && !sample.ReadingUnavailable
+}
+
+// Status returns the threshold status for threshold sensors, or the optional data for discrete sensors,
+// the same as Sensor.Status.
+func (sample *SensorSample) Status() string {
+	if !sample.IsValid() {
+		return "N/A"
+	}
+	if sample.Sensor.IsThreshold() {
+		return string(sample.ThresholdStatus)
+	}
+	return fmt.Sprintf("0x%02x%02x", sample.optionalData1, sample.optionalData2)
+}
+
+func (sample *SensorSample) ReadingStr() string {
+	if !sample.IsValid() {
+		return "N/A"
+	}
+	if sample.Sensor.IsThreshold() {
+		return fmt.Sprintf("%.3f", sample.Value)
+	}
+	return fmt.Sprintf("%d", sample.Raw)
+}
+
+// SensorStats holds the statistics over the most recent valid samples of an analog sensor.
+type SensorStats struct {
+	Sensor *Sensor
+
+	// Count is the number of samples the statistics are computed over, it is at most the Window.
+	Count int
+	Min   float64
+	Max   float64
+	Avg   float64
+
+	// Last is the last sample of the sensor, valid or not. It is nil if the sensor is never sampled.
+	Last *SensorSample
+}
+
+// SensorSampler samples the readings of the sensors periodically.
+//
+// The static metadata of the sensors (SDRs, reading factors, thresholds) is resolved
+// once by GetSensors, then each Sample only issues Get Sensor Reading to each selected sensor.
+// The reading factors of non-linear sensors are fetched once per raw reading and cached.
+type SensorSampler struct {
+	client *Client
+	config SensorSamplerConfig
+
+	initialized bool
+	entries     []*sensorSamplerEntry
+
+	// protects the windows and last samples of the entries
+	l sync.Mutex
+}
+
+type sensorSamplerEntry struct {
+	sensor *Sensor
+
+	// reading factors of non-linear sensor keyed by the raw reading
+	factors map[uint8]ReadingFactors
+
+	window *sensorWindow
+	last   *SensorSample
+}
+
+func NewSensorSampler(client *Client, config SensorSamplerConfig) *SensorSampler {
+	if config.Window <= 0 {
+		config.Window = 60
+	}
+	return &SensorSampler{
+		client: client,
+		config: config,
+	}
+}
+
+// Refresh resolves the metadata of the sensors again, eg: after the SDR Repository changed.
+// The statistics are reset.
+func (s *SensorSampler) Refresh() error {
+	sensors, err := s.client.GetSensors(s.config.Filters...)
+	if err != nil {
+		return fmt.Errorf("GetSensors failed, err: %s", err)
+	}
+
+	entries := make([]*sensorSamplerEntry, 0, len(sensors))
+	for _, sensor := range sensors {
+		entry := &sensorSamplerEntry{
+			sensor: sensor,
+			window: newSensorWindow(s.config.Window),
+		}
+		if sensor.Threshold.LinearizationFunc.IsNonLinear() {
+			entry.factors = map[uint8]ReadingFactors{
+				sensor.Raw: sensor.Threshold.ReadingFactors,
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	s.l.Lock()
+	s.entries = entries
+	s.initialized = true
+	s.l.Unlock()
+
+	return nil
+}
+
+// Sensors returns the sensors selected by the sampler, with the metadata resolved.
+func (s *SensorSampler) Sensors() []*Sensor {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	out := make([]*Sensor, 0, len(s.entries))
+	for _, entry := range s.entries {
+		out = append(out, entry.sensor)
+	}
+	return out
+}
+
+// Sample reads all the selected sensors and returns the samples in the order of Sensors.
+// The metadata of the sensors is resolved on the first call.
+//
+// The failure of reading a sensor does not fail the Sample, it is returned as SensorSample.Err.
+// Sample must not be called concurrently, or concurrently with Refresh.
+func (s *SensorSampler) Sample() ([]*SensorSample, error) {
+	if !s.initialized {
+		if err := s.Refresh(); err != nil {
+			return nil, err
+		}
+	}
+
+	entries := s.entries
+	samples := make([]*SensorSample, len(entries))
+
+	clients := append([]*Client{s.client}, s.config.Clients...)
+	if len(clients) > len(entries) {
+		clients = clients[:len(entries)]
+	}
+
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for _, c := range clients {
+		wg.Add(1)
+		go func(c *Client) {
+			defer wg.Done()
+			for i := range indexes {
+				samples[i] = c.sampleSensor(entries[i])
+			}
+		}(c)
+	}
+	for i := range entries {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	s.l.Lock()
+	for i, entry := range entries {
+		sample := samples[i]
+		entry.last = sample
+		if sample.IsValid() && entry.sensor.HasAnalogReading {
+			entry.window.add(sample.Value)
+		}
+	}
+	s.l.Unlock()
+
+	return samples, nil
+}
+
+// Stats returns the statistics of the sensors in the order of Sensors.
+// The Count is zero for the discrete sensors and the sensors without valid samples.
+func (s *SensorSampler) Stats() []*SensorStats {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	out := make([]*SensorStats, 0, len(s.entries))
+	for _, entry := range s.entries {
+		stats := &SensorStats{
+			Sensor: entry.sensor,
+			Last:   entry.last,
+		}
+		stats.Count, stats.Min, stats.Max, stats.Avg = entry.window.stats()
+		out = append(out, stats)
+	}
+	return out
+}
+
+// sampleSensor issues Get Sensor Reading to the owner of the sensor and converts the reading.
+func (c *Client) sampleSensor(entry *sensorSamplerEntry) *SensorSample {
+	sensor := entry.sensor
+	sample := &SensorSample{
+		Sensor: sensor,
+		Time:   time.Now(),
+	}
+
+	err := c.WithSensorOwner(sensor, func() error {
+		readingRes, err := c.GetSensorReading(sensor.Number)
+		if err != nil {
+			return fmt.Errorf("GetSensorReading for sensor %#02x failed, err: %s", sensor.Number, err)
+		}
+
+		sample.Raw = readingRes.Reading
+		sample.ScanningDisabled = readingRes.SensorScanningDisabled
+		sample.ReadingUnavailable = readingRes.ReadingUnavailable
+		sample.ThresholdStatus = readingRes.ThresholdStatus()
+		sample.ActiveStates = readingRes.ActiveStates
+		sample.optionalData1 = readingRes.optionalData1
+		sample.optionalData2 = readingRes.optionalData2
+
+		if !sensor.HasAnalogReading {
+			sample.Value = float64(readingRes.Reading)
+			return nil
+		}
+
+		factors := sensor.Threshold.ReadingFactors
+		if entry.factors != nil {
+			var ok bool
+			factors, ok = entry.factors[readingRes.Reading]
+			if !ok {
+				factorsRes, err := c.GetSensorReadingFactors(sensor.Number, readingRes.Reading)
+				if err != nil {
+					return fmt.Errorf("GetSensorReadingFactors for sensor %#02x failed, err: %s", sensor.Number, err)
+				}
+				factors = factorsRes.ReadingFactors
+				entry.factors[readingRes.Reading] = factors
+			}
+		}
+		sample.Value = ConvertReading(readingRes.Reading, sensor.SensorUnit.AnalogDataFormat, factors, sensor.Threshold.LinearizationFunc)
+		return nil
+	})
+	if err != nil {
+		sample.Err = err
+	}
+
+	return sample
+}
+
+// sensorWindow holds the most recent values in a ring buffer.
+type sensorWindow struct {
+	values []float64
+	next   int
+	full   bool
+}
+
+func newSensorWindow(size int) *sensorWindow {
+	return &sensorWindow{
+		values: make([]float64, size),
+	}
+}
+
+func (w *sensorWindow) add(value float64) {
+	w.values[w.next] = value
+	w.next++
+	if w.next == len(w.values) {
+		w.next = 0
+		w.full = true
+	}
+}
+
+func (w *sensorWindow) stats() (count int, min float64, max float64, avg float64) {
+	values := w.values[:w.next]
+	if w.full {
+		values = w.values
+	}
+	if len(values) == 0 {
+		return 0, 0, 0, 0
+	}
+
+	min, max = math.Inf(1), math.Inf(-1)
+	var sum float64
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+		sum += v
+	}
+	return len(values), min, max, sum / float64(len(values))
+}
+
+// FormatSensorStats returns a table of the last readings and the statistics of the sensors.
+func FormatSensorStats(stats []*SensorStats) string {
+	var buf = new(bytes.Buffer)
+	table := tablewriter.NewWriter(buf)
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"SensorNumber", "SensorName", "Reading", "Unit", "Status", "Min", "Max", "Avg", "Samples", "Time"})
+
+	for _, s := range stats {
+		row := []string{
+			fmt.Sprintf("%#02x", s.Sensor.Number),
+			s.Sensor.Name,
+			"N/A",
+			s.Sensor.SensorUnit.String(),
+			"N/A",
+			"", "", "",
+			fmt.Sprintf("%d", s.Count),
+			"",
+		}
+		if s.Last != nil {
+			row[2] = s.Last.ReadingStr()
+			row[4] = s.Last.Status()
+			row[9] = s.Last.Time.Format(time.RFC3339)
+		}
+		if s.Count > 0 {
+			row[5] = fmt.Sprintf("%.3f", s.Min)
+			row[6] = fmt.Sprintf("%.3f", s.Max)
+			row[7] = fmt.Sprintf("%.3f", s.Avg)
+		}
+		table.Append(row)
+	}
+
+	table.Render()
+	return buf.String()
+}
@@ -0,0 +1,210 @@
+package ipmi
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_sensorWindow(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		values []float64
+		count  int
+		min    float64
+		max    float64
+		avg    float64
+	}{
+		{"empty", 3, nil, 0, 0, 0, 0},
+		{"partial", 3, []float64{2, 4}, 2, 2, 4, 3},
+		{"full", 3, []float64{1, 5, 3}, 3, 1, 5, 3},
+		{"wrapped", 3, []float64{100, -100, 1, 2, 3}, 3, 1, 3, 2},
+	}
+
+	for _, test := range tests {
+		w := newSensorWindow(test.size)
+		for _, v := range test.values {
+			w.add(v)
+		}
+		count, min, max, avg := w.stats()
+		if count != test.count || min != test.min || max != test.max || avg != test.avg {
+			t.Errorf("test %s not matched, got: %d %v %v %v, expected: %d %v %v %v",
+				test.name, count, min, max, avg, test.count, test.min, test.max, test.avg)
+		}
+	}
+}
+
+// newTestSensorSampler returns a sampler of the sensors, without resolving them by GetSensors.
+func newTestSensorSampler(c *Client, config SensorSamplerConfig, sensors ...*Sensor) *SensorSampler {
+	s := NewSensorSampler(c, config)
+	for _, sensor := range sensors {
+		entry := &sensorSamplerEntry{
+			sensor: sensor,
+			window: newSensorWindow(s.config.Window),
+		}
+		if sensor.Threshold.LinearizationFunc.IsNonLinear() {
+			entry.factors = map[uint8]ReadingFactors{
+				sensor.Raw: sensor.Threshold.ReadingFactors,
+			}
+		}
+		s.entries = append(s.entries, entry)
+	}
+	s.initialized = true
+	return s
+}
+
+func Test_SensorSampler_Sample(t *testing.T) {
+	// each client blocks its first reading until all the clients got one,
+	// so the sensors must be split across the clients.
+	var l sync.Mutex
+	arrived := 0
+	ready := make(chan struct{})
+	sampled := make(map[int][]uint8)
+
+	newClient := func(id int) *Client {
+		first := true
+		return newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+			if req.Cmd != CommandGetSensorReading.ID {
+				return 0xc1, nil
+			}
+			l.Lock()
+			sampled[id] = append(sampled[id], req.Data[0])
+			if first {
+				first = false
+				arrived++
+				if arrived == 2 {
+					close(ready)
+				}
+			}
+			l.Unlock()
+			select {
+			case <-ready:
+			case <-time.After(3 * time.Second):
+			}
+			// reading is ten times the sensor number, event messages and scanning enabled
+			return 0x00, []byte{req.Data[0] * 10, 0xc0, 0x00}
+		})
+	}
+
+	sensors := make([]*Sensor, 0)
+	for number := uint8(1); number <= 4; number++ {
+		sensor := &Sensor{Number: number, EventReadingType: EventReadingTypeThreshold, HasAnalogReading: true}
+		sensor.Threshold.ReadingFactors.M = 1
+		sensors = append(sensors, sensor)
+	}
+
+	s := newTestSensorSampler(newClient(0), SensorSamplerConfig{Clients: []*Client{newClient(1)}}, sensors...)
+	samples, err := s.Sample()
+	if err != nil {
+		t.Fatalf("Sample failed, err: %s", err)
+	}
+
+	for i, sample := range samples {
+		expected := float64(sensors[i].Number) * 10
+		if sample.Err != nil || sample.Sensor != sensors[i] || sample.Value != expected {
+			t.Errorf("test sample %d not matched, got: %v (err: %v), expected: %v", i, sample.Value, sample.Err, expected)
+		}
+	}
+	l.Lock()
+	if len(sampled[0]) == 0 || len(sampled[1]) == 0 || len(sampled[0])+len(sampled[1]) != len(sensors) {
+		t.Errorf("test split not matched, got: %v, expected the %d sensors split across 2 clients", sampled, len(sensors))
+	}
+	l.Unlock()
+
+	for _, stats := range s.Stats() {
+		expected := float64(stats.Sensor.Number) * 10
+		if stats.Count != 1 || stats.Min != expected || stats.Max != expected || stats.Avg != expected {
+			t.Errorf("test stats %#02x not matched, got: %d %v %v %v, expected: 1 %v", stats.Sensor.Number, stats.Count, stats.Min, stats.Max, stats.Avg, expected)
+		}
+	}
+}
+
+func Test_SensorSampler_NonLinearFactors(t *testing.T) {
+	readings := []uint8{0x10, 0x20, 0x20, 0x10}
+	var factorsRequested []uint8
+
+	n := 0
+	c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+		switch req.Cmd {
+		case CommandGetSensorReading.ID:
+			reading := readings[n]
+			n++
+			return 0x00, []byte{reading, 0xc0, 0x00}
+		case CommandGetSensorReadingFactors.ID:
+			factorsRequested = append(factorsRequested, req.Data[1])
+			// M = 2 for the reading 0x20
+			return 0x00, []byte{0x21, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00}
+		}
+		return 0xc1, nil
+	})
+
+	sensor := &Sensor{Number: 0x30, EventReadingType: EventReadingTypeThreshold, HasAnalogReading: true, Raw: 0x10}
+	sensor.Threshold.LinearizationFunc = LinearizationFunc_NonLinear
+	sensor.Threshold.ReadingFactors.M = 1
+
+	s := newTestSensorSampler(c, SensorSamplerConfig{}, sensor)
+	for i, reading := range readings {
+		samples, err := s.Sample()
+		if err != nil {
+			t.Fatalf("Sample failed, err: %s", err)
+		}
+
+		factors := ReadingFactors{M: 1}
+		if reading == 0x20 {
+			factors.M = 2
+		}
+		expected := ConvertReading(reading, sensor.SensorUnit.AnalogDataFormat, factors, LinearizationFunc_NonLinear)
+		if got := samples[0].Value; samples[0].Err != nil || got != expected {
+			t.Errorf("test sample %d not matched, got: %v (err: %v), expected: %v", i, got, samples[0].Err, expected)
+		}
+	}
+
+	// the factors of 0x10 are resolved with the sensor, and of 0x20 are requested once
+	if len(factorsRequested) != 1 || factorsRequested[0] != 0x20 {
+		t.Errorf("test factors requests not matched, got: % x, expected: 20", factorsRequested)
+	}
+}
+
+func Test_FormatSensorStats(t *testing.T) {
+	sensor := &Sensor{Number: 0x30, Name: "Temp", EventReadingType: EventReadingTypeThreshold, HasAnalogReading: true}
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		stats    *SensorStats
+		expected []string
+	}{
+		{
+			name:     "never sampled",
+			stats:    &SensorStats{Sensor: sensor},
+			expected: []string{" 0x30 ", " Temp ", " N/A ", " 0 "},
+		},
+		{
+			name: "sampled",
+			stats: &SensorStats{
+				Sensor: sensor, Count: 2, Min: 1, Max: 3, Avg: 2,
+				Last: &SensorSample{Sensor: sensor, Time: now, Value: 3, ThresholdStatus: SensorThresholdStatus_OK},
+			},
+			expected: []string{" 3.000 ", " 1.000 ", " 2.000 ", " 2 ", " " + now.Format(time.RFC3339) + " ", " " + string(SensorThresholdStatus_OK) + " "},
+		},
+		{
+			name: "last sample failed",
+			stats: &SensorStats{
+				Sensor: sensor, Count: 1, Min: 1, Max: 1, Avg: 1,
+				Last: &SensorSample{Sensor: sensor, Time: now, Err: ErrUnpackedDataTooShort},
+			},
+			expected: []string{" N/A ", " 1.000 ", " 1 "},
+		},
+	}
+
+	for _, test := range tests {
+		got := FormatSensorStats([]*SensorStats{test.stats})
+		for _, expected := range test.expected {
+			if !strings.Contains(got, expected) {
+				t.Errorf("test %s not matched, got: %s, expected: %s", test.name, got, expected)
+			}
+		}
+	}
+}
@@ -18,9 +18,20 @@ const (
 )
 
 const (
-	FRUFormatVersion     uint8 = 0x01
-	FRUAreaFieldsEndMark uint8 = 0xc1
-	FRUCommonHeaderSize  uint8 = 8
+	FRUFormatVersion            uint8 = 0x01
+	FRUAreaFieldsEndMark        uint8 = 0xc1
+	FRUCommonHeaderSize         uint8 = 8
+	FRUMultiRecordFormatVersion uint8 = 0x02
+)
+
+// The type codes of TypeLength.
+//
+// see: FRU/13. TYPE/LENGTH BYTE FORMAT
+const (
+	TypeCodeBinary    uint8 = 0x00
+	TypeCodeBCDPlus   uint8 = 0x01
+	TypeCode6BitASCII uint8 = 0x02
+	TypeCode8BitASCII uint8 = 0x03
 )
 
 type FRU struct {
@@ -37,6 +48,10 @@ type FRU struct {
 	BoardInfoArea   *FRUBoardInfoArea
 	ProductInfoArea *FRUProductInfoArea
 	MultiRecords    []*FRUMultiRecord
+
+	// SPD is only present for the DIMM memory FRU devices, which hold
+	// SPD contents instead of the FRU information layout.
+	SPD *SPD
 }
 
 func (fru *FRU) Present() bool {
@@ -51,12 +66,201 @@ func (fru *FRU) DeviceID() uint8 {
 	return fru.deviceID
 }
 
+// Pack encodes the FRU into a FRU Information Device image. The areas are laid
+// out after the Common Header in the order of Internal Use, Chassis, Board, Product
+// and MultiRecord. The Common Header is generated, so CommonHeader is ignored,
+// and the End of List bit is only set on the last MultiRecord.
+func (fru *FRU) Pack() ([]byte, error) {
+	header := &FRUCommonHeader{FormatVersion: FRUFormatVersion}
+	out := make([]byte, FRUCommonHeaderSize)
+
+	var appendArea = func(area []byte) (uint8, error) {
+		offset8B := len(out) / 8
+		if offset8B > 0xff {
+			return 0, fmt.Errorf("area offset %d exceeds the common header limit", len(out))
+		}
+		out = append(out, area...)
+		return uint8(offset8B), nil
+	}
+
+	var err error
+
+	if fru.InternalUseArea != nil {
+		header.InternalOffset8B, err = appendArea(fru.InternalUseArea.Pack())
+		if err != nil {
+			return nil, fmt.Errorf("pack fru internal use area failed, err: %s", err)
+		}
+	}
+
+	if fru.ChassisInfoArea != nil {
+		area, err := fru.ChassisInfoArea.Pack()
+		if err != nil {
+			return nil, fmt.Errorf("pack fru chassis area failed, err: %s", err)
+		}
+		if header.ChassisOffset8B, err = appendArea(area); err != nil {
+			return nil, fmt.Errorf("pack fru chassis area failed, err: %s", err)
+		}
+	}
+
+	if fru.BoardInfoArea != nil {
+		area, err := fru.BoardInfoArea.Pack()
+		if err != nil {
+			return nil, fmt.Errorf("pack fru board area failed, err: %s", err)
+		}
+		if header.BoardOffset8B, err = appendArea(area); err != nil {
+			return nil, fmt.Errorf("pack fru board area failed, err: %s", err)
+		}
+	}
+
+	if fru.ProductInfoArea != nil {
+		area, err := fru.ProductInfoArea.Pack()
+		if err != nil {
+			return nil, fmt.Errorf("pack fru product area failed, err: %s", err)
+		}
+		if header.ProductOffset8B, err = appendArea(area); err != nil {
+			return nil, fmt.Errorf("pack fru product area failed, err: %s", err)
+		}
+	}
+
+	if len(fru.MultiRecords) != 0 {
+		var area []byte
+		for i, multiRecord := range fru.MultiRecords {
+			record := *multiRecord
+			record.EndOfList = i == len(fru.MultiRecords)-1
+			data, err := record.Pack()
+			if err != nil {
+				return nil, fmt.Errorf("pack fru multi record %d failed, err: %s", i, err)
+			}
+			area = append(area, data...)
+		}
+		if header.MultiRecordsOffset8B, err = appendArea(area); err != nil {
+			return nil, fmt.Errorf("pack fru multi record area failed, err: %s", err)
+		}
+	}
+
+	header.Checksum = fruChecksum(header.Pack()[0:7])
+	packBytes(header.Pack(), out, 0)
+
+	return out, nil
+}
+
+// ParseFRUData parses a whole FRU Information Device image, like the one
+// returned by GetFRUData or FRU.Pack.
+func ParseFRUData(data []byte) (*FRU, error) {
+	fruHeader := &FRUCommonHeader{}
+	if err := fruHeader.Unpack(data); err != nil {
+		return nil, fmt.Errorf("unpack fru data failed, err: %s", err)
+	}
+	if fruHeader.FormatVersion != FRUFormatVersion {
+		return nil, fmt.Errorf("unkown FRU header version %#02x", fruHeader.FormatVersion)
+	}
+
+	fru := &FRU{
+		CommonHeader: fruHeader,
+	}
+
+	// areaData returns the data of the area which starts at offset8B
+	// and whose length is held by the second byte of the area.
+	var areaData = func(offset8B uint8) ([]byte, error) {
+		start := int(offset8B) * 8
+		if len(data) < start+2 {
+			return nil, ErrUnpackedDataTooShort
+		}
+		end := start + int(data[start+1])*8
+		if len(data) < end {
+			return nil, ErrUnpackedDataTooShort
+		}
+		return data[start:end], nil
+	}
+
+	if fruHeader.InternalOffset8B != 0 {
+		// the length of the internal use area is determined by the offset of the next area
+		start := int(fruHeader.InternalOffset8B) * 8
+		end := len(data)
+		for _, offset8B := range []uint8{fruHeader.ChassisOffset8B, fruHeader.BoardOffset8B, fruHeader.ProductOffset8B, fruHeader.MultiRecordsOffset8B} {
+			if offset := int(offset8B) * 8; offset > start && offset < end {
+				end = offset
+			}
+		}
+		if len(data) < end || start >= end {
+			return nil, fmt.Errorf("unpack fru internal use area failed, err: %s", ErrUnpackedDataTooShort)
+		}
+		fruInternal := &FRUInternalUseArea{}
+		if err := fruInternal.Unpack(data[start:end]); err != nil {
+			return nil, fmt.Errorf("unpack fru internal use area failed, err: %s", err)
+		}
+		fru.InternalUseArea = fruInternal
+	}
+
+	if fruHeader.ChassisOffset8B != 0 {
+		area, err := areaData(fruHeader.ChassisOffset8B)
+		if err != nil {
+			return nil, fmt.Errorf("unpack fru chassis failed, err: %s", err)
+		}
+		fruChassis := &FRUChassisInfoArea{}
+		if err := fruChassis.Unpack(area); err != nil {
+			return nil, fmt.Errorf("unpack fru chassis failed, err: %s", err)
+		}
+		fru.ChassisInfoArea = fruChassis
+	}
+
+	if fruHeader.BoardOffset8B != 0 {
+		area, err := areaData(fruHeader.BoardOffset8B)
+		if err != nil {
+			return nil, fmt.Errorf("unpack fru board failed, err: %s", err)
+		}
+		fruBoard := &FRUBoardInfoArea{}
+		if err := fruBoard.Unpack(area); err != nil {
+			return nil, fmt.Errorf("unpack fru board failed, err: %s", err)
+		}
+		fru.BoardInfoArea = fruBoard
+	}
+
+	if fruHeader.ProductOffset8B != 0 {
+		area, err := areaData(fruHeader.ProductOffset8B)
+		if err != nil {
+			return nil, fmt.Errorf("unpack fru product failed, err: %s", err)
+		}
+		fruProduct := &FRUProductInfoArea{}
+		if err := fruProduct.Unpack(area); err != nil {
+			return nil, fmt.Errorf("unpack fru product failed, err: %s", err)
+		}
+		fru.ProductInfoArea = fruProduct
+	}
+
+	if fruHeader.MultiRecordsOffset8B != 0 {
+		offset := int(fruHeader.MultiRecordsOffset8B) * 8
+		for {
+			if len(data) < offset+5 {
+				return nil, fmt.Errorf("unpack fru multi record failed, err: %s", ErrUnpackedDataTooShort)
+			}
+			recordSize := 5 + int(data[offset+2]) // Record Header + Data Length
+			if len(data) < offset+recordSize {
+				return nil, fmt.Errorf("unpack fru multi record failed, err: %s", ErrUnpackedDataTooShort)
+			}
+
+			record := &FRUMultiRecord{}
+			if err := record.Unpack(data[offset : offset+recordSize]); err != nil {
+				return nil, fmt.Errorf("unpack fru multi record failed, err: %s", err)
+			}
+			fru.MultiRecords = append(fru.MultiRecords, record)
+
+			offset += recordSize
+			if record.EndOfList {
+				break
+			}
+		}
+	}
+
+	return fru, nil
+}
+
 func (fru *FRU) String() string {
 	var buf = new(bytes.Buffer)
 
 	buf.WriteString(fmt.Sprintf("FRU Device Description : %s (ID %d)\n", fru.deviceName, fru.deviceID))
 	if !fru.Present() {
-		buf.WriteString("  Device not present\n")
+		buf.WriteString(fmt.Sprintf("  Device not present (%s)\n", fru.deviceNotPresentReason))
 		return buf.String()
 	}
 
@@ -89,8 +293,15 @@ func (fru *FRU) String() string {
 			buf.WriteString(fmt.Sprintf("  Product Extra        : %s\n", v))
 		}
 	}
+	if fru.InternalUseArea != nil {
+		buf.WriteString(fmt.Sprintf("  Internal Use Area    : %x\n", fru.InternalUseArea.Data))
+	}
 	for _, multiRecord := range fru.MultiRecords {
-		buf.WriteString(fmt.Sprintf("  Multi Record         : %s\n", multiRecord.RecordType.String()))
+		buf.WriteString(fmt.Sprintf("  Multi Record         : %s\n", multiRecord.Summary()))
+	}
+
+	if fru.SPD != nil {
+		buf.WriteString(fru.SPD.String() + "\n")
 	}
 	return buf.String()
 }
@@ -182,6 +393,25 @@ type FRUInternalUseArea struct {
 	Data          []byte
 }
 
+func (fruInternal *FRUInternalUseArea) Unpack(msg []byte) error {
+	if len(msg) < 1 {
+		return ErrUnpackedDataTooShort
+	}
+	fruInternal.FormatVersion = msg[0]
+	fruInternal.Data, _, _ = unpackBytes(msg, 1, len(msg)-1)
+	return nil
+}
+
+// Pack encodes the Internal Use Area, the area is padded with zeros
+// to a multiple of 8 bytes.
+func (fruInternal *FRUInternalUseArea) Pack() []byte {
+	out := append([]byte{FRUFormatVersion}, fruInternal.Data...)
+	for len(out)%8 != 0 {
+		out = append(out, 0x00)
+	}
+	return out
+}
+
 // FRUChassisInfoArea is used to hold Serial Number, Part Number, and other
 // information about the system chassis. A system can have multiple FRU
 // Information Devices within a chassis, but only one device should provide
@@ -235,6 +465,31 @@ func (fruChassis *FRUChassisInfoArea) Unpack(msg []byte) error {
 	return nil
 }
 
+// Pack encodes the Chassis Info Area.
+//
+// The TypeLength fields only select the encoding of the corresponding fields,
+// the lengths are recalculated. A zero TypeLength selects the most compact
+// encoding that decodes back to the same chars. Custom fields are always
+// encoded that way. The area is padded to a multiple of 8 bytes and the
+// checksum is calculated, so Length8B, Unused and Checksum are ignored.
+func (fruChassis *FRUChassisInfoArea) Pack() ([]byte, error) {
+	out := []byte{FRUFormatVersion, 0x00, uint8(fruChassis.ChassisType)}
+
+	var err error
+
+	out, err = appendFRUTypeLengthField(out, fruChassis.PartNumberTypeLength, fruChassis.PartNumber)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru chassis part number field failed, err: %s", err)
+	}
+
+	out, err = appendFRUTypeLengthField(out, fruChassis.SerialNumberTypeLength, fruChassis.SerialNumber)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru chassis serial number field failed, err: %s", err)
+	}
+
+	return packFRUCustomUnusedChecksumFields(out, fruChassis.Custom)
+}
+
 type ChassisType uint8
 
 func (chassisType ChassisType) String() string {
@@ -406,6 +661,45 @@ func (fruBoard *FRUBoardInfoArea) Unpack(msg []byte) error {
 	return nil
 }
 
+// Pack encodes the Board Info Area, see FRUChassisInfoArea.Pack for how the
+// fields are encoded. A zero MfgDateTime is encoded as unspecified.
+func (fruBoard *FRUBoardInfoArea) Pack() ([]byte, error) {
+	out := []byte{FRUFormatVersion, 0x00, fruBoard.LanguageCode, 0x00, 0x00, 0x00}
+
+	m, err := fruMfgDateTimeMinutes(fruBoard.MfgDateTime)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru board mfg date time failed, err: %s", err)
+	}
+	packUint24L(m, out, 3)
+
+	out, err = appendFRUTypeLengthField(out, fruBoard.ManufacturerTypeLength, fruBoard.Manufacturer)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru board manufacturer field failed, err: %s", err)
+	}
+
+	out, err = appendFRUTypeLengthField(out, fruBoard.ProductNameTypeLength, fruBoard.ProductName)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru board product name field failed, err: %s", err)
+	}
+
+	out, err = appendFRUTypeLengthField(out, fruBoard.SerialNumberTypeLength, fruBoard.SerialNumber)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru board serial number field failed, err: %s", err)
+	}
+
+	out, err = appendFRUTypeLengthField(out, fruBoard.PartNumberTypeLength, fruBoard.PartNumber)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru board part number field failed, err: %s", err)
+	}
+
+	out, err = appendFRUTypeLengthField(out, fruBoard.FRUFileIDTypeLength, fruBoard.FRUFileID)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru board file id field failed, err: %s", err)
+	}
+
+	return packFRUCustomUnusedChecksumFields(out, fruBoard.Custom)
+}
+
 type BoardType uint8
 
 func (boardType BoardType) String() string {
@@ -520,6 +814,51 @@ func (fruProduct *FRUProductInfoArea) Unpack(msg []byte) error {
 	return nil
 }
 
+// Pack encodes the Product Info Area, see FRUChassisInfoArea.Pack for how the
+// fields are encoded.
+func (fruProduct *FRUProductInfoArea) Pack() ([]byte, error) {
+	out := []byte{FRUFormatVersion, 0x00, fruProduct.LanguageCode}
+
+	var err error
+
+	out, err = appendFRUTypeLengthField(out, fruProduct.ManufacturerTypeLength, fruProduct.Manufacturer)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru product manufacturer field failed, err: %s", err)
+	}
+
+	out, err = appendFRUTypeLengthField(out, fruProduct.NameTypeLength, fruProduct.Name)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru product name field failed, err: %s", err)
+	}
+
+	out, err = appendFRUTypeLengthField(out, fruProduct.PartModelTypeLength, fruProduct.PartModel)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru product part model field failed, err: %s", err)
+	}
+
+	out, err = appendFRUTypeLengthField(out, fruProduct.VersionTypeLength, fruProduct.Version)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru product version field failed, err: %s", err)
+	}
+
+	out, err = appendFRUTypeLengthField(out, fruProduct.SerialNumberTypeLength, fruProduct.SerialNumber)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru product serial number field failed, err: %s", err)
+	}
+
+	out, err = appendFRUTypeLengthField(out, fruProduct.AssetTagTypeLength, fruProduct.AssetTag)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru product asset tag field failed, err: %s", err)
+	}
+
+	out, err = appendFRUTypeLengthField(out, fruProduct.FRUFileIDTypeLength, fruProduct.FRUFileID)
+	if err != nil {
+		return nil, fmt.Errorf("pack fru product file id field failed, err: %s", err)
+	}
+
+	return packFRUCustomUnusedChecksumFields(out, fruProduct.Custom)
+}
+
 // The MultiRecord Info Area provides a region that holds one or more records
 // where the type and format of the information is specified in the individual
 // headers for the records.
@@ -546,14 +885,18 @@ type FRUMultiRecord struct {
 	HeaderChecksum uint8
 
 	RecordData []byte
+
+	// Record holds the decoded RecordData, it is nil if the record type is
+	// not known or the RecordData can not be decoded.
+	Record FRURecord
 }
 
 func (fruMultiRecord *FRUMultiRecord) Unpack(msg []byte) error {
-	if len(msg) < 3 {
+	if len(msg) < 5 {
 		return ErrUnpackedDataTooShort
 	}
-	// RecordLength
-	if len(msg) < int(msg[2]) {
+	// Record Header + RecordLength
+	if len(msg) < 5+int(msg[2]) {
 		return ErrUnpackedDataTooShort
 	}
 
@@ -570,9 +913,47 @@ func (fruMultiRecord *FRUMultiRecord) Unpack(msg []byte) error {
 	dataLen := int(fruMultiRecord.RecordLength)
 	fruMultiRecord.RecordData, _, _ = unpackBytes(msg, 5, dataLen)
 
+	// a malformed record should not fail the whole FRU
+	fruMultiRecord.Record, _ = decodeFRURecord(fruMultiRecord.RecordType, fruMultiRecord.RecordData)
+
 	return nil
 }
 
+// NewFRUMultiRecord returns a FRUMultiRecord holding the packed data of a record,
+// like the one returned by FRURecordTypeDCOutput.Pack.
+func NewFRUMultiRecord(recordType FRURecordType, recordData []byte) *FRUMultiRecord {
+	return &FRUMultiRecord{
+		RecordType:    recordType,
+		FormatVersion: FRUMultiRecordFormatVersion,
+		RecordLength:  uint8(len(recordData)),
+		RecordData:    recordData,
+	}
+}
+
+// Pack encodes the record with its Record Header. The format version is always
+// packed as FRUMultiRecordFormatVersion, RecordLength and the checksums are
+// calculated from RecordData.
+func (fruMultiRecord *FRUMultiRecord) Pack() ([]byte, error) {
+	if len(fruMultiRecord.RecordData) > 0xff {
+		return nil, fmt.Errorf("record data too long (%d), max is 255", len(fruMultiRecord.RecordData))
+	}
+
+	out := make([]byte, 5+len(fruMultiRecord.RecordData))
+	packUint8(uint8(fruMultiRecord.RecordType), out, 0)
+
+	b1 := FRUMultiRecordFormatVersion
+	if fruMultiRecord.EndOfList {
+		b1 = setBit7(b1)
+	}
+	packUint8(b1, out, 1)
+	packUint8(uint8(len(fruMultiRecord.RecordData)), out, 2)
+	packUint8(fruChecksum(fruMultiRecord.RecordData), out, 3)
+	packUint8(fruChecksum(out[0:4]), out, 4)
+	packBytes(fruMultiRecord.RecordData, out, 5)
+
+	return out, nil
+}
+
 type FRURecordType uint8
 
 func (t FRURecordType) String() string {
@@ -597,52 +978,59 @@ func (t FRURecordType) String() string {
 	if ok {
 		return s
 	}
+	if t >= 0xc0 {
+		return "OEM"
+	}
 	return ""
 }
 
 // fru: 18.1 Power Supply Information (Record Type 0x00)
 type FRURecordTypePowerSupply struct {
 	// This field allows for Power Supplies with capacities from 0 to 4095 watts.
-	OverallCapacity uint16
+	OverallCapacity uint16 `json:"overall_capacity"`
 	// The highest instantaneous VA value that this supply draws during operation (other than during Inrush). In integer units. FFFFh if not specified.
-	PeakVA uint16
+	PeakVA uint16 `json:"peak_va"`
 	// Maximum inrush of current, in Amps, into the power supply. FFh if not specified.
-	InrushCurrent uint8 // 涌入电流
+	InrushCurrent uint8 `json:"inrush_current"` // 涌入电流
 	// Number of milliseconds before power supply loading enters non-startup operating range. Set to 0 if no inrush current specified.
-	InrushIntervalMilliSecond uint8
+	InrushIntervalMilliSecond uint8 `json:"inrush_interval_ms"`
 	// This specifies the low end of acceptable voltage into the power supply. The units are 10mV.
-	LowEndInputVoltageRange1 uint16
+	LowEndInputVoltageRange1 uint16 `json:"low_end_input_voltage_range_1"`
 	// This specifies the high end of acceptable voltage into the power supply. The units are 10mV.
-	HighEndInputVoltageRange1 uint16
+	HighEndInputVoltageRange1 uint16 `json:"high_end_input_voltage_range_1"`
 	// This specifies the low end of acceptable voltage into the power supply. This field would be used if the power supply did not support autoswitch. Range 1 would define the 110V range, while range 2 would be used for 220V. The units are 10mV.
-	LowEndInputVoltageRange2 uint16
+	LowEndInputVoltageRange2 uint16 `json:"low_end_input_voltage_range_2"`
 	// This specifies the high end of acceptable voltage into the power supply. This field would be used if the power supply did not support autoswitch. Range 1 would define the 110V range, while range 2 would be used for 220V. The units are 10mV.
-	HighEndInputVoltageRange2 uint16
+	HighEndInputVoltageRange2 uint16 `json:"high_end_input_voltage_range_2"`
 	// This specifies the low end of acceptable frequency range into the power supply. Use 00h if supply accepts a DC input.
-	LowEndInputFrequencyRange uint8
+	LowEndInputFrequencyRange uint8 `json:"low_end_input_frequency_range"`
 	// This specifies the high end of acceptable frequency range into the power supply. Use 00h for both Low End and High End frequency range if supply only takes a DC input.
-	HighEndInputFrequencyRange uint8
+	HighEndInputFrequencyRange uint8 `json:"high_end_input_frequency_range"`
 	// Minimum number of milliseconds the power supply can hold up POWERGOOD (and maintain valid DC output) after input power is lost.
-	InputDropoutToleranceMilliSecond uint8
+	InputDropoutToleranceMilliSecond uint8 `json:"input_dropout_tolerance_ms"`
 
-	HotSwapSuppot         bool
-	Autoswitch            bool
-	PowerFactorCorrection bool
-	PredictiveFailSupport bool
+	HotSwapSuppot         bool `json:"hot_swap_support"`
+	Autoswitch            bool `json:"autoswitch"`
+	PowerFactorCorrection bool `json:"power_factor_correction"`
+	PredictiveFailSupport bool `json:"predictive_fail_support"`
+	// If PredictiveFailTachometerLowerThreshold is zero, true means the predictive
+	// fail pin indicates failure when asserted, otherwise true means the tachometer
+	// generates two pulses per rotation.
+	PredictiveFailPolarity bool `json:"predictive_fail_polarity"`
 
 	// the number of seconds peak wattage can be sustained (0-15 seconds)
-	PeakWattageHoldupSecond uint8
+	PeakWattageHoldupSecond uint8 `json:"peak_wattage_holdup_second"`
 	// the peak wattage the power supply can produce during this time period
-	PeakCapacity uint16
+	PeakCapacity uint16 `json:"peak_capacity"`
 
-	CombinedWattageVoltage1 uint8 // bit 7:4 - Voltage 1
-	CombinedWattageVoltage2 uint8 // bit 3:0 - Voltage 2
+	CombinedWattageVoltage1 uint8 `json:"combined_wattage_voltage_1"` // bit 7:4 - Voltage 1
+	CombinedWattageVoltage2 uint8 `json:"combined_wattage_voltage_2"` // bit 3:0 - Voltage 2
 	// 0000b (0) 12V
 	// 0001b (1) -12V
 	// 0010b (2) 5V
 	// 0011b (3) 3.3V
 
-	TotalCombinedWattage uint16
+	TotalCombinedWattage uint16 `json:"total_combined_wattage"`
 
 	// This field serves two purposes.
 	// It clarifies what type of predictive fail the power supply supports
@@ -653,34 +1041,110 @@ type FRURecordTypePowerSupply struct {
 	//
 	//  0x00 Predictive fail pin indicates pass/fail
 	//  0x01 - 0xFF Lower threshold to indicate predictive failure (Rotations per second)
-	PredictiveFailTachometerLowerThreshold uint8 // RPS
+	PredictiveFailTachometerLowerThreshold uint8 `json:"predictive_fail_tachometer_lower_threshold"` // RPS
+}
+
+func (f *FRURecordTypePowerSupply) Unpack(msg []byte) error {
+	if len(msg) < 24 {
+		return ErrUnpackedDataTooShort
+	}
+	b0, _, _ := unpackUint16L(msg, 0)
+	f.OverallCapacity = b0 & 0x0fff
+	f.PeakVA, _, _ = unpackUint16L(msg, 2)
+	f.InrushCurrent = msg[4]
+	f.InrushIntervalMilliSecond = msg[5]
+	f.LowEndInputVoltageRange1, _, _ = unpackUint16L(msg, 6)
+	f.HighEndInputVoltageRange1, _, _ = unpackUint16L(msg, 8)
+	f.LowEndInputVoltageRange2, _, _ = unpackUint16L(msg, 10)
+	f.HighEndInputVoltageRange2, _, _ = unpackUint16L(msg, 12)
+	f.LowEndInputFrequencyRange = msg[14]
+	f.HighEndInputFrequencyRange = msg[15]
+	f.InputDropoutToleranceMilliSecond = msg[16]
+
+	b17 := msg[17]
+	f.PredictiveFailPolarity = isBit4Set(b17)
+	f.HotSwapSuppot = isBit3Set(b17)
+	f.Autoswitch = isBit2Set(b17)
+	f.PowerFactorCorrection = isBit1Set(b17)
+	f.PredictiveFailSupport = isBit0Set(b17)
+
+	b18, _, _ := unpackUint16L(msg, 18)
+	f.PeakWattageHoldupSecond = uint8(b18 >> 12)
+	f.PeakCapacity = b18 & 0x0fff
+
+	f.CombinedWattageVoltage1 = msg[20] >> 4
+	f.CombinedWattageVoltage2 = msg[20] & 0x0f
+	f.TotalCombinedWattage, _, _ = unpackUint16L(msg, 21)
+	f.PredictiveFailTachometerLowerThreshold = msg[23]
+
+	return nil
+}
+
+func (f *FRURecordTypePowerSupply) Pack() []byte {
+	out := make([]byte, 24)
+	packUint16L(f.OverallCapacity&0x0fff, out, 0)
+	packUint16L(f.PeakVA, out, 2)
+	packUint8(f.InrushCurrent, out, 4)
+	packUint8(f.InrushIntervalMilliSecond, out, 5)
+	packUint16L(f.LowEndInputVoltageRange1, out, 6)
+	packUint16L(f.HighEndInputVoltageRange1, out, 8)
+	packUint16L(f.LowEndInputVoltageRange2, out, 10)
+	packUint16L(f.HighEndInputVoltageRange2, out, 12)
+	packUint8(f.LowEndInputFrequencyRange, out, 14)
+	packUint8(f.HighEndInputFrequencyRange, out, 15)
+	packUint8(f.InputDropoutToleranceMilliSecond, out, 16)
+
+	var b17 uint8
+	if f.PredictiveFailPolarity {
+		b17 = setBit4(b17)
+	}
+	if f.HotSwapSuppot {
+		b17 = setBit3(b17)
+	}
+	if f.Autoswitch {
+		b17 = setBit2(b17)
+	}
+	if f.PowerFactorCorrection {
+		b17 = setBit1(b17)
+	}
+	if f.PredictiveFailSupport {
+		b17 = setBit0(b17)
+	}
+	packUint8(b17, out, 17)
+
+	packUint16L(uint16(f.PeakWattageHoldupSecond&0x0f)<<12|f.PeakCapacity&0x0fff, out, 18)
+	packUint8(f.CombinedWattageVoltage1<<4|f.CombinedWattageVoltage2&0x0f, out, 20)
+	packUint16L(f.TotalCombinedWattage, out, 21)
+	packUint8(f.PredictiveFailTachometerLowerThreshold, out, 23)
+
+	return out
 }
 
 // FRU: 18.2 DC Output (Record Type 0x01)
 type FRURecordTypeDCOutput struct {
 	//  if the power supply provides this output even when the power supply is switched off.
-	OutputWhenOff bool
+	OutputWhenOff bool `json:"output_when_off"`
 
-	OutputNumber uint8
+	OutputNumber uint8 `json:"output_number"`
 
 	// Expected voltage from the power supply. Value is a signed short given in 10 millivolt increments.
 	// 额定电压 毫-伏特
-	NominalVoltage10mV int16
+	NominalVoltage10mV int16 `json:"nominal_voltage_10mv"`
 
-	MaxNegativeVoltage10mV int16
+	MaxNegativeVoltage10mV int16 `json:"max_negative_voltage_10mv"`
 
-	MaxPositiveVoltage10mV int16
+	MaxPositiveVoltage10mV int16 `json:"max_positive_voltage_10mv"`
 
-	RippleNoise1mV uint16
+	RippleNoise1mV uint16 `json:"ripple_noise_1mv"`
 
 	// 毫-安培
-	MinCurrentDraw1mA uint16
+	MinCurrentDraw1mA uint16 `json:"min_current_draw_1ma"`
 
-	MaxCurrentDraw1mA uint16
+	MaxCurrentDraw1mA uint16 `json:"max_current_draw_1ma"`
 }
 
 func (output *FRURecordTypeDCOutput) Unpack(msg []byte) error {
-	if len(msg) < 12 {
+	if len(msg) < 13 {
 		return ErrUnpackedDataTooShort
 	}
 	b, _, _ := unpackUint8(msg, 0)
@@ -703,35 +1167,53 @@ func (output *FRURecordTypeDCOutput) Unpack(msg []byte) error {
 	return nil
 }
 
+func (output *FRURecordTypeDCOutput) Pack() []byte {
+	out := make([]byte, 13)
+
+	b := output.OutputNumber & 0x0f
+	if output.OutputWhenOff {
+		b = setBit7(b)
+	}
+	packUint8(b, out, 0)
+	packUint16L(uint16(output.NominalVoltage10mV), out, 1)
+	packUint16L(uint16(output.MaxNegativeVoltage10mV), out, 3)
+	packUint16L(uint16(output.MaxPositiveVoltage10mV), out, 5)
+	packUint16L(output.RippleNoise1mV, out, 7)
+	packUint16L(output.MinCurrentDraw1mA, out, 9)
+	packUint16L(output.MaxCurrentDraw1mA, out, 11)
+
+	return out
+}
+
 // FRU: 18.2a Extended DC Output (Record Type 0x09)
 type FRURecordTypeExtenedDCOutput struct {
 	//  if the power supply provides this output even when the power supply is switched off.
-	OutputWhenOff bool
+	OutputWhenOff bool `json:"output_when_off"`
 
 	// This record can be used to support power supplies with outputs that exceed 65.535 Amps.
 	// 0b = 10 mA
 	// 1b = 100 mA
-	CurrentUnits100 bool
+	CurrentUnits100 bool `json:"current_units_100ma"`
 
-	OutputNumber uint8
+	OutputNumber uint8 `json:"output_number"`
 
 	// Expected voltage from the power supply. Value is a signed short given in 10 millivolt increments.
 	// 毫-伏特
-	NominalVoltage10mV int16
+	NominalVoltage10mV int16 `json:"nominal_voltage_10mv"`
 
-	MaxNegativeVoltage10mV int16
+	MaxNegativeVoltage10mV int16 `json:"max_negative_voltage_10mv"`
 
-	MaxPositiveVoltage10mV int16
+	MaxPositiveVoltage10mV int16 `json:"max_positive_voltage_10mv"`
 
-	RippleNoise uint16
+	RippleNoise uint16 `json:"ripple_noise"`
 
 	// The unit is determined by CurrentUnits100 field.
-	MinCurrentDraw uint16
-	MaxCurrentDraw uint16
+	MinCurrentDraw uint16 `json:"min_current_draw"`
+	MaxCurrentDraw uint16 `json:"max_current_draw"`
 }
 
 func (output *FRURecordTypeExtenedDCOutput) Unpack(msg []byte) error {
-	if len(msg) < 12 {
+	if len(msg) < 13 {
 		return ErrUnpackedDataTooShort
 	}
 	b, _, _ := unpackUint8(msg, 0)
@@ -755,19 +1237,40 @@ func (output *FRURecordTypeExtenedDCOutput) Unpack(msg []byte) error {
 	return nil
 }
 
+func (output *FRURecordTypeExtenedDCOutput) Pack() []byte {
+	out := make([]byte, 13)
+
+	b := output.OutputNumber & 0x0f
+	if output.OutputWhenOff {
+		b = setBit7(b)
+	}
+	if output.CurrentUnits100 {
+		b = setBit4(b)
+	}
+	packUint8(b, out, 0)
+	packUint16L(uint16(output.NominalVoltage10mV), out, 1)
+	packUint16L(uint16(output.MaxNegativeVoltage10mV), out, 3)
+	packUint16L(uint16(output.MaxPositiveVoltage10mV), out, 5)
+	packUint16L(output.RippleNoise, out, 7)
+	packUint16L(output.MinCurrentDraw, out, 9)
+	packUint16L(output.MaxCurrentDraw, out, 11)
+
+	return out
+}
+
 // FRU: 18.3 DC Load (Record Type 0x02)
 type FRURecordTypeDCLoad struct {
-	OutputNumber            uint8
-	NominalVoltage10mV      int16
-	MinTolerableVoltage10mV int16
-	MaxTolerableVoltage10mV int16
-	RippleNoise1mV          uint16
-	MinCurrentLoad1mA       uint16
-	MaxCurrentLoad1mA       uint16
+	OutputNumber            uint8  `json:"output_number"`
+	NominalVoltage10mV      int16  `json:"nominal_voltage_10mv"`
+	MinTolerableVoltage10mV int16  `json:"min_tolerable_voltage_10mv"`
+	MaxTolerableVoltage10mV int16  `json:"max_tolerable_voltage_10mv"`
+	RippleNoise1mV          uint16 `json:"ripple_noise_1mv"`
+	MinCurrentLoad1mA       uint16 `json:"min_current_load_1ma"`
+	MaxCurrentLoad1mA       uint16 `json:"max_current_load_1ma"`
 }
 
 func (output *FRURecordTypeDCLoad) Unpack(msg []byte) error {
-	if len(msg) < 12 {
+	if len(msg) < 13 {
 		return ErrUnpackedDataTooShort
 	}
 	b, _, _ := unpackUint8(msg, 0)
@@ -789,16 +1292,29 @@ func (output *FRURecordTypeDCLoad) Unpack(msg []byte) error {
 	return nil
 }
 
+func (output *FRURecordTypeDCLoad) Pack() []byte {
+	out := make([]byte, 13)
+	packUint8(output.OutputNumber&0x0f, out, 0)
+	packUint16L(uint16(output.NominalVoltage10mV), out, 1)
+	packUint16L(uint16(output.MinTolerableVoltage10mV), out, 3)
+	packUint16L(uint16(output.MaxTolerableVoltage10mV), out, 5)
+	packUint16L(output.RippleNoise1mV, out, 7)
+	packUint16L(output.MinCurrentLoad1mA, out, 9)
+	packUint16L(output.MaxCurrentLoad1mA, out, 11)
+
+	return out
+}
+
 // FRU: 18.3a Extended DC Load (Record Type 0x0A)
 type FRURecordTypeExtendedDCLoad struct {
-	IsCurrrentUnit100mA bool // current units: true = 100 mA , false = 10 mA
-	OutputNumber        uint8
-	NominalVoltage10mV  int16
-	MinVoltage10mV      int16
-	MaxVoltage10mV      int16
-	RippleNoise1mV      int16
-	MinCurrentLoad      uint16 // units is determined by IsCurrentUnit100mA field
-	MaxCurrentLoad      uint16 // units is determined by IsCurrentUnit100mA field
+	IsCurrrentUnit100mA bool   `json:"current_unit_100ma"` // current units: true = 100 mA , false = 10 mA
+	OutputNumber        uint8  `json:"output_number"`
+	NominalVoltage10mV  int16  `json:"nominal_voltage_10mv"`
+	MinVoltage10mV      int16  `json:"min_voltage_10mv"`
+	MaxVoltage10mV      int16  `json:"max_voltage_10mv"`
+	RippleNoise1mV      int16  `json:"ripple_noise_1mv"`
+	MinCurrentLoad      uint16 `json:"min_current_load"` // units is determined by IsCurrentUnit100mA field
+	MaxCurrentLoad      uint16 `json:"max_current_load"` // units is determined by IsCurrentUnit100mA field
 }
 
 func (f *FRURecordTypeExtendedDCLoad) Unpack(msg []byte) error {
@@ -826,6 +1342,24 @@ func (f *FRURecordTypeExtendedDCLoad) Unpack(msg []byte) error {
 	return nil
 }
 
+func (f *FRURecordTypeExtendedDCLoad) Pack() []byte {
+	out := make([]byte, 13)
+
+	b := f.OutputNumber & 0x0f
+	if f.IsCurrrentUnit100mA {
+		b = setBit7(b)
+	}
+	packUint8(b, out, 0)
+	packUint16L(uint16(f.NominalVoltage10mV), out, 1)
+	packUint16L(uint16(f.MinVoltage10mV), out, 3)
+	packUint16L(uint16(f.MaxVoltage10mV), out, 5)
+	packUint16L(uint16(f.RippleNoise1mV), out, 7)
+	packUint16L(f.MinCurrentLoad, out, 9)
+	packUint16L(f.MaxCurrentLoad, out, 11)
+
+	return out
+}
+
 type ManagementAccessSubRecordType uint8
 
 func (t ManagementAccessSubRecordType) String() string {
@@ -880,13 +1414,17 @@ func (f *FRURecordTypeManagementAccess) Unpack(msg []byte) error {
 	return nil
 }
 
+func (f *FRURecordTypeManagementAccess) Pack() []byte {
+	return append([]byte{uint8(f.SubRecordType)}, f.Data...)
+}
+
 // FRU: 18.5 Base Compatibility Record (Record Type 0x04)
 type FRURecordTypeBaseCompatibility struct {
-	ManufacturerID         uint32
-	EntityID               EntityID
-	CompatibilityBase      uint8
-	CompatibilityCodeStart uint8
-	CodeRangeMask          uint8
+	ManufacturerID         uint32   `json:"manufacturer_id"`
+	EntityID               EntityID `json:"entity_id"`
+	CompatibilityBase      uint8    `json:"compatibility_base"`
+	CompatibilityCodeStart uint8    `json:"compatibility_code_start"`
+	CodeRangeMask          uint8    `json:"code_range_mask"`
 }
 
 func (f *FRURecordTypeBaseCompatibility) Unpack(msg []byte) error {
@@ -901,13 +1439,23 @@ func (f *FRURecordTypeBaseCompatibility) Unpack(msg []byte) error {
 	return nil
 }
 
+func (f *FRURecordTypeBaseCompatibility) Pack() []byte {
+	out := make([]byte, 7)
+	packUint24L(f.ManufacturerID, out, 0)
+	packUint8(uint8(f.EntityID), out, 3)
+	packUint8(f.CompatibilityBase, out, 4)
+	packUint8(f.CompatibilityCodeStart, out, 5)
+	packUint8(f.CodeRangeMask, out, 6)
+	return out
+}
+
 // FRU: 18.6 Extended Compatibility Record (Record Type 0x05)
 type FRURecordTypeExtendedCompatiblityRecord struct {
-	ManufacturerID         uint32
-	EntityID               EntityID
-	CompatibilityBase      uint8
-	CompatibilityCodeStart uint8
-	CodeRangeMask          uint8
+	ManufacturerID         uint32   `json:"manufacturer_id"`
+	EntityID               EntityID `json:"entity_id"`
+	CompatibilityBase      uint8    `json:"compatibility_base"`
+	CompatibilityCodeStart uint8    `json:"compatibility_code_start"`
+	CodeRangeMask          uint8    `json:"code_range_mask"`
 }
 
 func (f *FRURecordTypeExtendedCompatiblityRecord) Unpack(msg []byte) error {
@@ -922,10 +1470,24 @@ func (f *FRURecordTypeExtendedCompatiblityRecord) Unpack(msg []byte) error {
 	return nil
 }
 
+func (f *FRURecordTypeExtendedCompatiblityRecord) Pack() []byte {
+	out := make([]byte, 7)
+	packUint24L(f.ManufacturerID, out, 0)
+	packUint8(uint8(f.EntityID), out, 3)
+	packUint8(f.CompatibilityBase, out, 4)
+	packUint8(f.CompatibilityCodeStart, out, 5)
+	packUint8(f.CodeRangeMask, out, 6)
+	return out
+}
+
 // FRU: 18.7 OEM Record (Record Types 0xC0-0xFF)
 type FRURecordTypeOEM struct {
 	ManufacturerID uint32
 	Data           []byte
+
+	// Decoded holds the Data decoded by the FRUOEMRecordDecoder registered
+	// for the manufacturer, it is nil if there is none or it fails.
+	Decoded interface{}
 }
 
 func (f *FRURecordTypeOEM) Unpack(msg []byte) error {
@@ -934,9 +1496,20 @@ func (f *FRURecordTypeOEM) Unpack(msg []byte) error {
 	}
 	f.ManufacturerID, _, _ = unpackUint24L(msg, 0)
 	f.Data, _, _ = unpackBytes(msg, 3, len(msg)-3)
+
+	if decoder := lookupFRUOEMRecordDecoder(OEM(f.ManufacturerID)); decoder != nil {
+		f.Decoded, _ = decoder(f.Data)
+	}
 	return nil
 }
 
+func (f *FRURecordTypeOEM) Pack() []byte {
+	out := make([]byte, 3+len(f.Data))
+	packUint24L(f.ManufacturerID, out, 0)
+	packBytes(f.Data, out, 3)
+	return out
+}
+
 // getFRUTypeLengthField return a field data bytes whose length is determined by
 // a TypeLength byte. The offset index SHOULD points to the TypeLength field.
 func getFRUTypeLengthField(fruData []byte, offset uint16) (nextOffset uint16, typeLength TypeLength, fieldData []byte, err error) {
@@ -999,3 +1572,177 @@ func getFRUCustomUnusedChecksumFields(fruData []byte, offset uint16) (custom [][
 	checksum = fruData[len(fruData)-1]
 	return
 }
+
+// NewTypeLength returns the TypeLength of a field whose chars are encoded
+// with typeCode and occupy length bytes.
+func NewTypeLength(typeCode uint8, length uint8) TypeLength {
+	return TypeLength(typeCode<<6 | length&0x3f)
+}
+
+// PackTypeLengthField encodes chars into a type/length byte followed by the
+// encoded bytes, with the encoding selected by typeCode.
+//
+// BCD plus chars are padded with a space to an even number of chars, and 6-bit
+// ASCII chars may decode with a trailing space, see TypeLength.Size.
+func PackTypeLengthField(typeCode uint8, chars []byte) ([]byte, error) {
+	var raw []byte
+
+	switch typeCode {
+	case TypeCodeBinary:
+		raw = chars
+
+	case TypeCodeBCDPlus:
+		raw = make([]byte, (len(chars)+1)/2)
+		for i := 0; i < len(raw)*2; i++ {
+			var c byte = ' '
+			if i < len(chars) {
+				c = chars[i]
+			}
+			idx := bytes.IndexByte(bcdPlusChars, c)
+			if idx < 0 {
+				return nil, fmt.Errorf("char %q can not be encoded as BCD plus", c)
+			}
+			if i%2 == 0 {
+				raw[i/2] |= uint8(idx)
+			} else {
+				raw[i/2] |= uint8(idx) << 4
+			}
+		}
+
+	case TypeCode6BitASCII:
+		raw = make([]byte, (len(chars)*6+7)/8)
+		for i, c := range chars {
+			if c < 0x20 || c > 0x5f {
+				return nil, fmt.Errorf("char %q can not be encoded as 6-bit ASCII", c)
+			}
+			// the chars are packed from the least significant bit of the first byte
+			bit := i * 6
+			v := uint16(c-0x20) << (bit % 8)
+			raw[bit/8] |= uint8(v)
+			if bit/8+1 < len(raw) {
+				raw[bit/8+1] |= uint8(v >> 8)
+			}
+		}
+
+	case TypeCode8BitASCII:
+		// the type/length byte 0xc1 is reserved as the end mark of the area fields
+		if len(chars) == 1 {
+			return nil, fmt.Errorf("single char %q can not be encoded as 8-bit ASCII", chars[0])
+		}
+		raw = chars
+
+	default:
+		return nil, fmt.Errorf("unknown type code %#02x", typeCode)
+	}
+
+	if len(raw) > 0x3f {
+		return nil, fmt.Errorf("encoded length %d exceeds the max length 63", len(raw))
+	}
+
+	out := []byte{uint8(NewTypeLength(typeCode, uint8(len(raw))))}
+	return append(out, raw...), nil
+}
+
+var bcdPlusChars = []byte("0123456789 -.:,_")
+
+// fruFieldTypeCode returns the type code of the most compact encoding
+// which decodes back to exactly the same chars.
+func fruFieldTypeCode(chars []byte) uint8 {
+	if len(chars) == 0 {
+		return TypeCode8BitASCII
+	}
+
+	isBCDPlus, is6BitASCII := true, true
+	for _, c := range chars {
+		if bytes.IndexByte(bcdPlusChars, c) < 0 {
+			isBCDPlus = false
+		}
+		if c < 0x20 || c > 0x5f {
+			is6BitASCII = false
+		}
+	}
+
+	switch {
+	case isBCDPlus && len(chars)%2 == 0:
+		return TypeCodeBCDPlus
+	case is6BitASCII && len(chars)%4 != 3:
+		// 3 chars occupy 18 bits, which would decode as 4 chars from 3 bytes.
+		return TypeCode6BitASCII
+	case len(chars) == 1:
+		// a single char of 8-bit ASCII is not allowed, see PackTypeLengthField
+		return TypeCodeBinary
+	default:
+		return TypeCode8BitASCII
+	}
+}
+
+// appendFRUTypeLengthField appends the encoded field to out. The type code of
+// typeLength selects the encoding, a zero typeLength selects the most compact one.
+func appendFRUTypeLengthField(out []byte, typeLength TypeLength, chars []byte) ([]byte, error) {
+	typeCode := typeLength.TypeCode()
+	if typeLength == 0 {
+		typeCode = fruFieldTypeCode(chars)
+	}
+
+	field, err := PackTypeLengthField(typeCode, chars)
+	if err != nil {
+		return nil, err
+	}
+	return append(out, field...), nil
+}
+
+// packFRUCustomUnusedChecksumFields is the reverse of getFRUCustomUnusedChecksumFields.
+// It appends the custom fields and the end mark to area, pads the area to a
+// multiple of 8 bytes, and fills the area length and checksum.
+func packFRUCustomUnusedChecksumFields(area []byte, custom [][]byte) ([]byte, error) {
+	for i, v := range custom {
+		// an empty field is treated as the end of custom fields when unpacking.
+		if len(v) == 0 {
+			return nil, fmt.Errorf("custom field %d is empty", i)
+		}
+		var err error
+		area, err = appendFRUTypeLengthField(area, 0, v)
+		if err != nil {
+			return nil, fmt.Errorf("pack custom field %d failed, err: %s", i, err)
+		}
+	}
+	area = append(area, FRUAreaFieldsEndMark)
+
+	// reserve the last byte for checksum
+	for (len(area)+1)%8 != 0 {
+		area = append(area, 0x00)
+	}
+
+	length8B := (len(area) + 1) / 8
+	if length8B > 0xff {
+		return nil, fmt.Errorf("area length %d exceeds the max length 2040", len(area)+1)
+	}
+	area[1] = uint8(length8B)
+
+	return append(area, fruChecksum(area)), nil
+}
+
+// fruChecksum returns the zero checksum of data, that is the sum of data and
+// the checksum is zero (modulo 256).
+func fruChecksum(data []byte) uint8 {
+	var c uint8
+	for _, b := range data {
+		c += b
+	}
+	return -c
+}
+
+// fruMfgDateTimeMinutes returns the number of minutes from 0:00 hrs 1/1/96 to t,
+// a zero t returns 0 which means unspecified.
+func fruMfgDateTimeMinutes(t time.Time) (uint32, error) {
+	if t.IsZero() {
+		return 0, nil
+	}
+
+	const secsFrom1970To1996 int64 = 820454400
+	m := (t.Unix() - secsFrom1970To1996) / 60
+	if m < 0 || m > 0xffffff {
+		return 0, fmt.Errorf("time %s out of range", t)
+	}
+	return uint32(m), nil
+}
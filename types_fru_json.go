@@ -0,0 +1,118 @@
+package ipmi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// MarshalJSON encodes the decoded FRU, the text fields are encoded as strings,
+// and the binary fields and undecoded data are encoded as hex strings.
+func (fru *FRU) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{
+		"device_id":   fru.deviceID,
+		"device_name": fru.deviceName,
+		"present":     fru.Present(),
+	}
+	if !fru.Present() {
+		out["not_present_reason"] = fru.deviceNotPresentReason
+		return json.Marshal(out)
+	}
+
+	if fru.InternalUseArea != nil {
+		out["internal_use"] = map[string]interface{}{
+			"format_version": fru.InternalUseArea.FormatVersion,
+			"data":           hex.EncodeToString(fru.InternalUseArea.Data),
+		}
+	}
+
+	if a := fru.ChassisInfoArea; a != nil {
+		out["chassis"] = map[string]interface{}{
+			"type":          a.ChassisType.String(),
+			"type_code":     uint8(a.ChassisType),
+			"part_number":   fruFieldString(a.PartNumberTypeLength, a.PartNumber),
+			"serial_number": fruFieldString(a.SerialNumberTypeLength, a.SerialNumber),
+			"custom":        fruCustomStrings(a.Custom),
+		}
+	}
+
+	if a := fru.BoardInfoArea; a != nil {
+		out["board"] = map[string]interface{}{
+			"mfg_date":      a.MfgDateTime.Format(time.RFC3339),
+			"manufacturer":  fruFieldString(a.ManufacturerTypeLength, a.Manufacturer),
+			"product_name":  fruFieldString(a.ProductNameTypeLength, a.ProductName),
+			"serial_number": fruFieldString(a.SerialNumberTypeLength, a.SerialNumber),
+			"part_number":   fruFieldString(a.PartNumberTypeLength, a.PartNumber),
+			"fru_file_id":   fruFieldString(a.FRUFileIDTypeLength, a.FRUFileID),
+			"custom":        fruCustomStrings(a.Custom),
+		}
+	}
+
+	if a := fru.ProductInfoArea; a != nil {
+		out["product"] = map[string]interface{}{
+			"manufacturer":  fruFieldString(a.ManufacturerTypeLength, a.Manufacturer),
+			"name":          fruFieldString(a.NameTypeLength, a.Name),
+			"part_number":   fruFieldString(a.PartModelTypeLength, a.PartModel),
+			"version":       fruFieldString(a.VersionTypeLength, a.Version),
+			"serial_number": fruFieldString(a.SerialNumberTypeLength, a.SerialNumber),
+			"asset_tag":     fruFieldString(a.AssetTagTypeLength, a.AssetTag),
+			"fru_file_id":   fruFieldString(a.FRUFileIDTypeLength, a.FRUFileID),
+			"custom":        fruCustomStrings(a.Custom),
+		}
+	}
+
+	if len(fru.MultiRecords) > 0 {
+		records := make([]map[string]interface{}, 0, len(fru.MultiRecords))
+		for _, multiRecord := range fru.MultiRecords {
+			record := map[string]interface{}{
+				"type":           multiRecord.RecordType.String(),
+				"type_code":      uint8(multiRecord.RecordType),
+				"format_version": multiRecord.FormatVersion,
+			}
+			if multiRecord.Record != nil {
+				record["record"] = multiRecord.Record
+			} else {
+				record["data"] = hex.EncodeToString(multiRecord.RecordData)
+			}
+			records = append(records, record)
+		}
+		out["multirecords"] = records
+	}
+
+	if spd := fru.SPD; spd != nil {
+		out["spd"] = map[string]interface{}{
+			"memory_type":            spd.MemoryType.String(),
+			"module_type":            spd.ModuleType,
+			"capacity_mb":            spd.CapacityMB,
+			"speed_mts":              spd.SpeedMTs,
+			"ranks":                  spd.Ranks,
+			"bus_width":              spd.BusWidth,
+			"ecc":                    spd.ECC,
+			"module_manufacturer_id": spd.ModuleManufacturerID,
+			"module_manufacturer":    spd.ModuleManufacturer(),
+			"manufacturing_location": spd.ManufacturingLocation,
+			"manufacturing_date":     spd.ManufacturingDate(),
+			"serial_number":          spd.SerialNumber,
+			"part_number":            spd.PartNumber,
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+// fruFieldString returns the decoded characters of the field as string,
+// or hex string for the binary fields.
+func fruFieldString(typeLength TypeLength, chars []byte) string {
+	if typeLength.TypeCode() == TypeCodeBinary && len(chars) > 0 {
+		return hex.EncodeToString(chars)
+	}
+	return string(chars)
+}
+
+func fruCustomStrings(custom [][]byte) []string {
+	out := make([]string, 0, len(custom))
+	for _, v := range custom {
+		out = append(out, string(v))
+	}
+	return out
+}
@@ -0,0 +1,462 @@
+package ipmi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FRURecord is the decoded Record Data of a MultiRecord.
+type FRURecord interface {
+	Unpack(msg []byte) error
+	Pack() []byte
+}
+
+// decodeFRURecord decodes the Record Data of the MultiRecord by the record type.
+// It returns nil if the record type is not known.
+func decodeFRURecord(recordType FRURecordType, recordData []byte) (FRURecord, error) {
+	var record FRURecord
+
+	switch {
+	case recordType == 0x00:
+		record = &FRURecordTypePowerSupply{}
+	case recordType == 0x01:
+		record = &FRURecordTypeDCOutput{}
+	case recordType == 0x02:
+		record = &FRURecordTypeDCLoad{}
+	case recordType == 0x03:
+		record = &FRURecordTypeManagementAccess{}
+	case recordType == 0x04:
+		record = &FRURecordTypeBaseCompatibility{}
+	case recordType == 0x05:
+		record = &FRURecordTypeExtendedCompatiblityRecord{}
+	case recordType == 0x09:
+		record = &FRURecordTypeExtenedDCOutput{}
+	case recordType == 0x0a:
+		record = &FRURecordTypeExtendedDCLoad{}
+	case recordType >= 0xc0:
+		record = &FRURecordTypeOEM{}
+	default:
+		// the ASF records (06h-08h) are defined by the ASF specification.
+		return nil, nil
+	}
+
+	if err := record.Unpack(recordData); err != nil {
+		return nil, fmt.Errorf("unpack %s record failed, err: %s", recordType, err)
+	}
+	return record, nil
+}
+
+// FRUOEMRecordDecoder decodes the Data (following the Manufacturer ID) of the OEM MultiRecord.
+type FRUOEMRecordDecoder func(data []byte) (interface{}, error)
+
+var (
+	fruOEMRecordDecoders     = map[OEM]FRUOEMRecordDecoder{}
+	fruOEMRecordDecodersLock sync.RWMutex
+)
+
+// RegisterFRUOEMRecordDecoder registers the decoder for the OEM MultiRecords (Record Types C0h-FFh)
+// of the manufacturer. The decoded value is set to FRURecordTypeOEM.Decoded.
+func RegisterFRUOEMRecordDecoder(manufacturerID OEM, decoder FRUOEMRecordDecoder) {
+	fruOEMRecordDecodersLock.Lock()
+	defer fruOEMRecordDecodersLock.Unlock()
+
+	fruOEMRecordDecoders[manufacturerID] = decoder
+}
+
+func lookupFRUOEMRecordDecoder(manufacturerID OEM) FRUOEMRecordDecoder {
+	fruOEMRecordDecodersLock.RLock()
+	defer fruOEMRecordDecodersLock.RUnlock()
+
+	return fruOEMRecordDecoders[manufacturerID]
+}
+
+func init() {
+	RegisterFRUOEMRecordDecoder(OEM_PICMG, decodeFRURecordPICMG)
+}
+
+// PICMGRecordID identifies the PICMG (AdvancedTCA, AdvancedMC and MicroTCA) OEM MultiRecords.
+type PICMGRecordID uint8
+
+func (id PICMGRecordID) String() string {
+	m := map[PICMGRecordID]string{
+		0x04: "Backplane Point-to-Point Connectivity",
+		0x10: "Address Table",
+		0x11: "Shelf Power Distribution",
+		0x12: "Shelf Activation and Power Management",
+		0x13: "Shelf Manager IP Connection",
+		0x14: "Board Point-to-Point Connectivity",
+		0x15: "Radial IPMB-0 Link Mapping",
+		0x16: "Module Current Requirements",
+		0x17: "Carrier Activation and Power Management",
+		0x18: "Carrier Point-to-Point Connectivity",
+		0x19: "AdvancedMC Point-to-Point Connectivity",
+		0x1a: "Carrier Information Table",
+		0x20: "MicroTCA Carrier Manager IP Link",
+		0x21: "Power Module Capability",
+		0x22: "MicroTCA Carrier Activation and Current Descriptor",
+		0x23: "MicroTCA Carrier Information Table",
+		0x24: "MicroTCA Shelf Manager IP Link",
+		0x25: "Power Policy Descriptor",
+		0x26: "MicroTCA Carrier Activation and Power Management",
+		0x27: "MicroTCA Shelf Fan Geography",
+		0x28: "MicroTCA Fan Geography",
+		0x29: "Power Channel Connectivity",
+		0x2a: "MicroTCA Carrier Power Policy",
+		0x2b: "MicroTCA Shelf Power Policy",
+		0x2c: "Clock Carrier Point-to-Point Connectivity",
+		0x2d: "Clock Configuration",
+	}
+	s, ok := m[id]
+	if ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown (%#02x)", uint8(id))
+}
+
+// FRURecordPICMG is the OEM MultiRecord defined by the PICMG specifications.
+//
+// The Board Point-to-Point Connectivity (14h), Module Current Requirements (16h)
+// and Address Table (10h) records are decoded into Record, the other records
+// are only split into the Record ID and the Format Version.
+//
+// see: PICMG 3.0 Table 3-10, OEM record format
+type FRURecordPICMG struct {
+	RecordID      PICMGRecordID
+	FormatVersion uint8
+	Data          []byte
+
+	// Record is one of *PICMGBoardP2PConnectivity, *PICMGModuleCurrentRequirements
+	// and *PICMGAddressTable, or nil if the record is not decoded.
+	Record interface{}
+}
+
+func decodeFRURecordPICMG(data []byte) (interface{}, error) {
+	if len(data) < 2 {
+		return nil, ErrUnpackedDataTooShort
+	}
+	r := &FRURecordPICMG{
+		RecordID:      PICMGRecordID(data[0]),
+		FormatVersion: data[1],
+		Data:          data[2:],
+	}
+
+	var record interface {
+		Unpack(msg []byte) error
+	}
+	switch r.RecordID {
+	case 0x10:
+		record = &PICMGAddressTable{}
+	case 0x14:
+		record = &PICMGBoardP2PConnectivity{}
+	case 0x16:
+		record = &PICMGModuleCurrentRequirements{}
+	default:
+		return r, nil
+	}
+	if err := record.Unpack(r.Data); err != nil {
+		return nil, fmt.Errorf("unpack PICMG %s record failed, err: %s", r.RecordID, err)
+	}
+	r.Record = record
+	return r, nil
+}
+
+func (r *FRURecordPICMG) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{
+		"record_id":      uint8(r.RecordID),
+		"record_name":    r.RecordID.String(),
+		"format_version": r.FormatVersion,
+		"data":           hex.EncodeToString(r.Data),
+	}
+	if r.Record != nil {
+		out["decoded"] = r.Record
+	}
+	return json.Marshal(out)
+}
+
+// PICMGLinkInterface is the interface of the Link Designator.
+type PICMGLinkInterface uint8
+
+func (i PICMGLinkInterface) String() string {
+	m := map[PICMGLinkInterface]string{
+		0x00: "Base Interface",
+		0x01: "Fabric Interface",
+		0x02: "Update Channel",
+	}
+	s, ok := m[i]
+	if ok {
+		return s
+	}
+	return "Reserved"
+}
+
+// PICMGLinkType is the Link Type of the Link Descriptor.
+type PICMGLinkType uint8
+
+func (t PICMGLinkType) String() string {
+	m := map[PICMGLinkType]string{
+		0x01: "PICMG 3.0 Base Interface 10/100/1000 BASE-T",
+		0x02: "PICMG 3.1 Ethernet Fabric Interface",
+		0x03: "PICMG 3.2 Infiniband Fabric Interface",
+		0x04: "PICMG 3.3 StarFabric Fabric Interface",
+		0x05: "PICMG 3.4 PCI Express Fabric Interface",
+	}
+	s, ok := m[t]
+	if ok {
+		return s
+	}
+	if t >= 0xf0 && t <= 0xfe {
+		return "OEM GUID Definition"
+	}
+	return "Reserved"
+}
+
+// PICMGLinkDescriptor describes a Point-to-Point link of the board.
+//
+// see: PICMG 3.0 Table 3-50, Link Descriptor
+type PICMGLinkDescriptor struct {
+	// Link Designator
+	ChannelNumber uint8
+	Interface     PICMGLinkInterface
+	// bitmask of the ports 0-3 of the channel
+	Ports uint8
+
+	LinkType          PICMGLinkType
+	LinkTypeExtension uint8
+	LinkGroupingID    uint8
+}
+
+func (d *PICMGLinkDescriptor) Unpack(msg []byte) error {
+	v, _, err := unpackUint32L(msg, 0)
+	if err != nil {
+		return err
+	}
+	d.ChannelNumber = uint8(v & 0x3f)
+	d.Interface = PICMGLinkInterface((v >> 6) & 0x03)
+	d.Ports = uint8((v >> 8) & 0x0f)
+	d.LinkType = PICMGLinkType((v >> 12) & 0xff)
+	d.LinkTypeExtension = uint8((v >> 20) & 0x0f)
+	d.LinkGroupingID = uint8(v >> 24)
+	return nil
+}
+
+func (d *PICMGLinkDescriptor) String() string {
+	return fmt.Sprintf("%s channel %d ports %#x, %s (%#02x), extension %#x, group %d",
+		d.Interface, d.ChannelNumber, d.Ports, d.LinkType, uint8(d.LinkType), d.LinkTypeExtension, d.LinkGroupingID)
+}
+
+// PICMGBoardP2PConnectivity is the Board Point-to-Point Connectivity record (14h).
+//
+// see: PICMG 3.0 Table 3-48, Board Point-to-Point Connectivity record
+type PICMGBoardP2PConnectivity struct {
+	OEMGUIDs        [][16]byte
+	LinkDescriptors []*PICMGLinkDescriptor
+}
+
+func (r *PICMGBoardP2PConnectivity) Unpack(msg []byte) error {
+	if len(msg) < 1 {
+		return ErrUnpackedDataTooShort
+	}
+	guidCount := int(msg[0])
+	offset := 1
+	if len(msg) < offset+16*guidCount {
+		return ErrUnpackedDataTooShort
+	}
+	r.OEMGUIDs = make([][16]byte, guidCount)
+	for i := 0; i < guidCount; i++ {
+		copy(r.OEMGUIDs[i][:], msg[offset:offset+16])
+		offset += 16
+	}
+
+	if (len(msg)-offset)%4 != 0 {
+		return fmt.Errorf("the link descriptors length (%d) is not a multiple of 4", len(msg)-offset)
+	}
+	r.LinkDescriptors = make([]*PICMGLinkDescriptor, 0, (len(msg)-offset)/4)
+	for ; offset < len(msg); offset += 4 {
+		d := &PICMGLinkDescriptor{}
+		if err := d.Unpack(msg[offset : offset+4]); err != nil {
+			return err
+		}
+		r.LinkDescriptors = append(r.LinkDescriptors, d)
+	}
+	return nil
+}
+
+// PICMGModuleCurrentRequirements is the Module Current Requirements record (16h).
+//
+// see: PICMG AMC.0 Table 3-10, Module Current Requirements record
+type PICMGModuleCurrentRequirements struct {
+	// the maximum current draw on the 12V payload power, in units of 0.1 A
+	CurrentDraw100mA uint8
+}
+
+func (r *PICMGModuleCurrentRequirements) Unpack(msg []byte) error {
+	if len(msg) < 1 {
+		return ErrUnpackedDataTooShort
+	}
+	r.CurrentDraw100mA = msg[0]
+	return nil
+}
+
+// PICMGSiteType is the Site Type of the Address Table entry.
+type PICMGSiteType uint8
+
+func (t PICMGSiteType) String() string {
+	m := map[PICMGSiteType]string{
+		0x00: "PICMG Board",
+		0x01: "Power Entry Module",
+		0x02: "Shelf FRU Information",
+		0x03: "Dedicated Shelf Management Controller",
+		0x04: "Fan Tray",
+		0x05: "Fan Filter Tray",
+		0x06: "Alarm",
+		0x07: "AdvancedMC Module",
+		0x08: "PMC",
+		0x09: "Rear Transition Module",
+	}
+	s, ok := m[t]
+	if ok {
+		return s
+	}
+	if t >= 0xc0 && t <= 0xcf {
+		return "OEM"
+	}
+	return "Reserved"
+}
+
+// PICMGAddressTableEntry maps a Hardware Address to a site of the shelf.
+type PICMGAddressTableEntry struct {
+	HardwareAddress uint8
+	SiteNumber      uint8
+	SiteType        PICMGSiteType
+}
+
+// PICMGAddressTable is the Address Table record (10h).
+//
+// see: PICMG 3.0 Table 3-16, Address Table record
+type PICMGAddressTable struct {
+	ShelfAddressTypeLength TypeLength
+	ShelfAddress           []byte
+	Entries                []*PICMGAddressTableEntry
+}
+
+func (r *PICMGAddressTable) Unpack(msg []byte) error {
+	// Shelf Address: the type/length byte followed by 20 bytes
+	if len(msg) < 22 {
+		return ErrUnpackedDataTooShort
+	}
+	r.ShelfAddressTypeLength = TypeLength(msg[0])
+	length := int(r.ShelfAddressTypeLength.Length())
+	if length > 20 {
+		return fmt.Errorf("the shelf address length (%d) exceeds 20", length)
+	}
+	shelfAddress, err := r.ShelfAddressTypeLength.Chars(msg[1 : 1+length])
+	if err != nil {
+		return fmt.Errorf("get chars from typelength failed, err: %s", err)
+	}
+	r.ShelfAddress = shelfAddress
+
+	count := int(msg[21])
+	if len(msg) < 22+3*count {
+		return ErrUnpackedDataTooShort
+	}
+	r.Entries = make([]*PICMGAddressTableEntry, count)
+	for i := 0; i < count; i++ {
+		offset := 22 + 3*i
+		r.Entries[i] = &PICMGAddressTableEntry{
+			HardwareAddress: msg[offset],
+			SiteNumber:      msg[offset+1],
+			SiteType:        PICMGSiteType(msg[offset+2]),
+		}
+	}
+	return nil
+}
+
+// picmgSummary returns a one-line description of the PICMG record.
+func picmgSummary(r *FRURecordPICMG) string {
+	prefix := fmt.Sprintf("PICMG %s (%#02x), version %d", r.RecordID, uint8(r.RecordID), r.FormatVersion)
+
+	switch record := r.Record.(type) {
+	case *PICMGBoardP2PConnectivity:
+		links := make([]string, 0, len(record.LinkDescriptors))
+		for _, d := range record.LinkDescriptors {
+			links = append(links, d.String())
+		}
+		return fmt.Sprintf("%s, %d OEM GUIDs, links: [%s]", prefix, len(record.OEMGUIDs), strings.Join(links, "; "))
+	case *PICMGModuleCurrentRequirements:
+		return fmt.Sprintf("%s, current draw %.1f A", prefix, float64(record.CurrentDraw100mA)/10)
+	case *PICMGAddressTable:
+		entries := make([]string, 0, len(record.Entries))
+		for _, e := range record.Entries {
+			entries = append(entries, fmt.Sprintf("%#02x: %s %d", e.HardwareAddress, e.SiteType, e.SiteNumber))
+		}
+		return fmt.Sprintf("%s, shelf address %q, entries: [%s]", prefix, record.ShelfAddress, strings.Join(entries, "; "))
+	}
+	return fmt.Sprintf("%s, data %x", prefix, r.Data)
+}
+
+func (f *FRURecordTypeManagementAccess) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{
+		"sub_record_type": uint8(f.SubRecordType),
+		"sub_record_name": f.SubRecordType.String(),
+	}
+	if f.SubRecordType == 0x07 {
+		out["data"] = hex.EncodeToString(f.Data)
+	} else {
+		out["data"] = string(f.Data)
+	}
+	return json.Marshal(out)
+}
+
+func (f *FRURecordTypeOEM) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{
+		"manufacturer_id":   f.ManufacturerID,
+		"manufacturer_name": OEM(f.ManufacturerID).String(),
+		"data":              hex.EncodeToString(f.Data),
+	}
+	if f.Decoded != nil {
+		out["decoded"] = f.Decoded
+	}
+	return json.Marshal(out)
+}
+
+// Summary returns a one-line description of the decoded record.
+func (fruMultiRecord *FRUMultiRecord) Summary() string {
+	switch r := fruMultiRecord.Record.(type) {
+	case *FRURecordTypePowerSupply:
+		return fmt.Sprintf("Power Supply, capacity %d W, peak %d VA, hot swap %s",
+			r.OverallCapacity, r.PeakVA, formatBool(r.HotSwapSuppot, "yes", "no"))
+	case *FRURecordTypeDCOutput:
+		return fmt.Sprintf("DC Output %d, nominal %.2f V (%.2f V ~ %.2f V), max %.3f A",
+			r.OutputNumber, float64(r.NominalVoltage10mV)/100, float64(r.MaxNegativeVoltage10mV)/100, float64(r.MaxPositiveVoltage10mV)/100, float64(r.MaxCurrentDraw1mA)/1000)
+	case *FRURecordTypeExtenedDCOutput:
+		return fmt.Sprintf("Extended DC Output %d, nominal %.2f V (%.2f V ~ %.2f V)",
+			r.OutputNumber, float64(r.NominalVoltage10mV)/100, float64(r.MaxNegativeVoltage10mV)/100, float64(r.MaxPositiveVoltage10mV)/100)
+	case *FRURecordTypeDCLoad:
+		return fmt.Sprintf("DC Load %d, nominal %.2f V (%.2f V ~ %.2f V), max %.3f A",
+			r.OutputNumber, float64(r.NominalVoltage10mV)/100, float64(r.MinTolerableVoltage10mV)/100, float64(r.MaxTolerableVoltage10mV)/100, float64(r.MaxCurrentLoad1mA)/1000)
+	case *FRURecordTypeExtendedDCLoad:
+		return fmt.Sprintf("Extended DC Load %d, nominal %.2f V (%.2f V ~ %.2f V)",
+			r.OutputNumber, float64(r.NominalVoltage10mV)/100, float64(r.MinVoltage10mV)/100, float64(r.MaxVoltage10mV)/100)
+	case *FRURecordTypeManagementAccess:
+		if r.SubRecordType == 0x07 {
+			return fmt.Sprintf("Management Access, %s: %x", r.SubRecordType, r.Data)
+		}
+		return fmt.Sprintf("Management Access, %s: %s", r.SubRecordType, r.Data)
+	case *FRURecordTypeBaseCompatibility:
+		return fmt.Sprintf("Base Compatibility, manufacturer %s, entity %s, base %#02x",
+			OEM(r.ManufacturerID), r.EntityID, r.CompatibilityBase)
+	case *FRURecordTypeExtendedCompatiblityRecord:
+		return fmt.Sprintf("Extended Compatibility, manufacturer %s, entity %s, base %#02x",
+			OEM(r.ManufacturerID), r.EntityID, r.CompatibilityBase)
+	case *FRURecordTypeOEM:
+		if picmg, ok := r.Decoded.(*FRURecordPICMG); ok {
+			return picmgSummary(picmg)
+		}
+		return fmt.Sprintf("OEM (%#02x), manufacturer %s (%d), data %x",
+			uint8(fruMultiRecord.RecordType), OEM(r.ManufacturerID), r.ManufacturerID, r.Data)
+	}
+	return fmt.Sprintf("%s (%#02x), data %x", fruMultiRecord.RecordType, uint8(fruMultiRecord.RecordType), fruMultiRecord.RecordData)
+}
@@ -0,0 +1,417 @@
+package ipmi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FRUSpec is a JSON description of a FRU image, the layout follows the one
+// used by frugen, eg:
+//
+//	{
+//	  "chassis": {"type": 23, "pn": "CHS-1000", "serial": "C0001", "custom": ["rev A"]},
+//	  "board": {"date": "18/10/2026 12:00:00", "mfg": "ACME", "pname": "MB-1",
+//	            "serial": {"encoding": "bcdplus", "data": "0123"}, "pn": "MB-1-001"},
+//	  "product": {"mfg": "ACME", "pname": "Server", "pn": "S-1", "ver": "1.0",
+//	              "serial": "S0001", "atag": ""},
+//	  "multirecord": [{"type": 192, "data": "5a0f0001"}]
+//	}
+//
+// Use FRUSpec.FRU to get the FRU and FRU.Pack to encode it.
+type FRUSpec struct {
+	// InternalUse holds the hex encoded data of the Internal Use Area.
+	InternalUse string                `json:"internal,omitempty"`
+	Chassis     *FRUChassisSpec       `json:"chassis,omitempty"`
+	Board       *FRUBoardSpec         `json:"board,omitempty"`
+	Product     *FRUProductSpec       `json:"product,omitempty"`
+	MultiRecord []*FRUMultiRecordSpec `json:"multirecord,omitempty"`
+}
+
+type FRUChassisSpec struct {
+	Type         ChassisType  `json:"type"`
+	PartNumber   FRUFieldSpec `json:"pn"`
+	SerialNumber FRUFieldSpec `json:"serial"`
+	Custom       []string     `json:"custom,omitempty"`
+}
+
+type FRUBoardSpec struct {
+	LanguageCode uint8 `json:"lang,omitempty"`
+	// MfgDateTime is in "DD/MM/YYYY HH:MM:SS" (UTC) or RFC3339 format,
+	// empty means unspecified.
+	MfgDateTime  string       `json:"date,omitempty"`
+	Manufacturer FRUFieldSpec `json:"mfg"`
+	ProductName  FRUFieldSpec `json:"pname"`
+	SerialNumber FRUFieldSpec `json:"serial"`
+	PartNumber   FRUFieldSpec `json:"pn"`
+	FRUFileID    FRUFieldSpec `json:"file"`
+	Custom       []string     `json:"custom,omitempty"`
+}
+
+type FRUProductSpec struct {
+	LanguageCode uint8        `json:"lang,omitempty"`
+	Manufacturer FRUFieldSpec `json:"mfg"`
+	Name         FRUFieldSpec `json:"pname"`
+	PartModel    FRUFieldSpec `json:"pn"`
+	Version      FRUFieldSpec `json:"ver"`
+	SerialNumber FRUFieldSpec `json:"serial"`
+	AssetTag     FRUFieldSpec `json:"atag"`
+	FRUFileID    FRUFieldSpec `json:"file"`
+	Custom       []string     `json:"custom,omitempty"`
+}
+
+type FRUMultiRecordSpec struct {
+	Type FRURecordType `json:"type"`
+	// Data holds the hex encoded record data.
+	Data string `json:"data"`
+}
+
+// FRUFieldSpec is a type/length field of FRU info areas. In JSON it is either
+// a plain string which selects the most compact encoding, or an object with
+// "encoding" (one of auto, binary, bcdplus, 6bitascii and text) and "data".
+// The data of binary encoding is hex encoded.
+type FRUFieldSpec struct {
+	Encoding string `json:"encoding,omitempty"`
+	Data     string `json:"data"`
+}
+
+func (f *FRUFieldSpec) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		f.Encoding = ""
+		f.Data = s
+		return nil
+	}
+
+	type fieldSpec FRUFieldSpec
+	var v fieldSpec
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("fru field must be a string or an object, err: %s", err)
+	}
+	*f = FRUFieldSpec(v)
+	return nil
+}
+
+// TypeLength returns the chars of the field, and the TypeLength which selects
+// the encoding of the chars, see FRUChassisInfoArea.Pack.
+func (f FRUFieldSpec) TypeLength() (TypeLength, []byte, error) {
+	switch strings.ToLower(f.Encoding) {
+	case "", "auto":
+		return 0, []byte(f.Data), nil
+	case "binary":
+		data, err := hex.DecodeString(f.Data)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid hex data %q, err: %s", f.Data, err)
+		}
+		// a zero TypeLength selects the encoding automatically, so keep the length part
+		return NewTypeLength(TypeCodeBinary, uint8(len(data))), data, nil
+	case "bcdplus":
+		return NewTypeLength(TypeCodeBCDPlus, 0), []byte(f.Data), nil
+	case "6bitascii":
+		return NewTypeLength(TypeCode6BitASCII, 0), []byte(f.Data), nil
+	case "text":
+		return NewTypeLength(TypeCode8BitASCII, 0), []byte(f.Data), nil
+	default:
+		return 0, nil, fmt.Errorf("unknown encoding %q", f.Encoding)
+	}
+}
+
+// ParseFRUSpec parses the JSON description of a FRU image.
+func ParseFRUSpec(data []byte) (*FRUSpec, error) {
+	spec := &FRUSpec{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("unmarshal fru spec failed, err: %s", err)
+	}
+	return spec, nil
+}
+
+// FRU converts the description to FRU.
+func (spec *FRUSpec) FRU() (*FRU, error) {
+	fru := &FRU{}
+
+	// fieldErr holds the first error of converting fields
+	var fieldErr error
+	var field = func(name string, f FRUFieldSpec, typeLength *TypeLength, chars *[]byte) {
+		if fieldErr != nil {
+			return
+		}
+		tl, data, err := f.TypeLength()
+		if err != nil {
+			fieldErr = fmt.Errorf("invalid %s field, err: %s", name, err)
+			return
+		}
+		*typeLength, *chars = tl, data
+	}
+	var custom = func(fields []string) [][]byte {
+		out := make([][]byte, 0, len(fields))
+		for _, v := range fields {
+			out = append(out, []byte(v))
+		}
+		return out
+	}
+
+	if spec.InternalUse != "" {
+		data, err := hex.DecodeString(spec.InternalUse)
+		if err != nil {
+			return nil, fmt.Errorf("invalid internal use data, err: %s", err)
+		}
+		fru.InternalUseArea = &FRUInternalUseArea{
+			FormatVersion: FRUFormatVersion,
+			Data:          data,
+		}
+	}
+
+	if c := spec.Chassis; c != nil {
+		area := &FRUChassisInfoArea{
+			FormatVersion: FRUFormatVersion,
+			ChassisType:   c.Type,
+			Custom:        custom(c.Custom),
+		}
+		field("chassis pn", c.PartNumber, &area.PartNumberTypeLength, &area.PartNumber)
+		field("chassis serial", c.SerialNumber, &area.SerialNumberTypeLength, &area.SerialNumber)
+		fru.ChassisInfoArea = area
+	}
+
+	if b := spec.Board; b != nil {
+		area := &FRUBoardInfoArea{
+			FormatVersion: FRUFormatVersion,
+			LanguageCode:  b.LanguageCode,
+			Custom:        custom(b.Custom),
+		}
+		if b.MfgDateTime != "" {
+			t, err := time.ParseInLocation("02/01/2006 15:04:05", b.MfgDateTime, time.UTC)
+			if err != nil {
+				if t, err = time.Parse(time.RFC3339, b.MfgDateTime); err != nil {
+					return nil, fmt.Errorf("invalid board date %q", b.MfgDateTime)
+				}
+			}
+			area.MfgDateTime = t
+		}
+		field("board mfg", b.Manufacturer, &area.ManufacturerTypeLength, &area.Manufacturer)
+		field("board pname", b.ProductName, &area.ProductNameTypeLength, &area.ProductName)
+		field("board serial", b.SerialNumber, &area.SerialNumberTypeLength, &area.SerialNumber)
+		field("board pn", b.PartNumber, &area.PartNumberTypeLength, &area.PartNumber)
+		field("board file", b.FRUFileID, &area.FRUFileIDTypeLength, &area.FRUFileID)
+		fru.BoardInfoArea = area
+	}
+
+	if p := spec.Product; p != nil {
+		area := &FRUProductInfoArea{
+			FormatVersion: FRUFormatVersion,
+			LanguageCode:  p.LanguageCode,
+			Custom:        custom(p.Custom),
+		}
+		field("product mfg", p.Manufacturer, &area.ManufacturerTypeLength, &area.Manufacturer)
+		field("product pname", p.Name, &area.NameTypeLength, &area.Name)
+		field("product pn", p.PartModel, &area.PartModelTypeLength, &area.PartModel)
+		field("product ver", p.Version, &area.VersionTypeLength, &area.Version)
+		field("product serial", p.SerialNumber, &area.SerialNumberTypeLength, &area.SerialNumber)
+		field("product atag", p.AssetTag, &area.AssetTagTypeLength, &area.AssetTag)
+		field("product file", p.FRUFileID, &area.FRUFileIDTypeLength, &area.FRUFileID)
+		fru.ProductInfoArea = area
+	}
+
+	if fieldErr != nil {
+		return nil, fieldErr
+	}
+
+	for i, r := range spec.MultiRecord {
+		data, err := hex.DecodeString(r.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multirecord %d data, err: %s", i, err)
+		}
+		fru.MultiRecords = append(fru.MultiRecords, NewFRUMultiRecord(r.Type, data))
+	}
+
+	return fru, nil
+}
+
+// SetField sets a field of the FRU info area by the names used in FRUSpec, eg:
+// ("board", "serial", "B0002"), ("chassis", "type", "23") or ("product", "custom.1", "x").
+// A custom field index equal to the number of the custom fields appends a new one.
+//
+// The field keeps its original encoding if the value can be encoded with it,
+// otherwise the most compact encoding is selected.
+func (fru *FRU) SetField(area string, field string, value string) error {
+	var typeLength *TypeLength
+	var chars *[]byte
+	var custom *[][]byte
+
+	switch area {
+	case "chassis":
+		a := fru.ChassisInfoArea
+		if a == nil {
+			return fmt.Errorf("chassis area not present")
+		}
+		custom = &a.Custom
+		switch field {
+		case "type":
+			v, err := strconv.ParseUint(value, 0, 8)
+			if err != nil {
+				return fmt.Errorf("invalid chassis type %q", value)
+			}
+			a.ChassisType = ChassisType(v)
+			return nil
+		case "pn":
+			typeLength, chars = &a.PartNumberTypeLength, &a.PartNumber
+		case "serial":
+			typeLength, chars = &a.SerialNumberTypeLength, &a.SerialNumber
+		}
+
+	case "board":
+		a := fru.BoardInfoArea
+		if a == nil {
+			return fmt.Errorf("board area not present")
+		}
+		custom = &a.Custom
+		switch field {
+		case "date":
+			t, err := time.ParseInLocation("02/01/2006 15:04:05", value, time.UTC)
+			if err != nil {
+				return fmt.Errorf("invalid board date %q, must be DD/MM/YYYY HH:MM:SS", value)
+			}
+			a.MfgDateTime = t
+			return nil
+		case "mfg":
+			typeLength, chars = &a.ManufacturerTypeLength, &a.Manufacturer
+		case "pname":
+			typeLength, chars = &a.ProductNameTypeLength, &a.ProductName
+		case "serial":
+			typeLength, chars = &a.SerialNumberTypeLength, &a.SerialNumber
+		case "pn":
+			typeLength, chars = &a.PartNumberTypeLength, &a.PartNumber
+		case "file":
+			typeLength, chars = &a.FRUFileIDTypeLength, &a.FRUFileID
+		}
+
+	case "product":
+		a := fru.ProductInfoArea
+		if a == nil {
+			return fmt.Errorf("product area not present")
+		}
+		custom = &a.Custom
+		switch field {
+		case "mfg":
+			typeLength, chars = &a.ManufacturerTypeLength, &a.Manufacturer
+		case "pname":
+			typeLength, chars = &a.NameTypeLength, &a.Name
+		case "pn":
+			typeLength, chars = &a.PartModelTypeLength, &a.PartModel
+		case "ver":
+			typeLength, chars = &a.VersionTypeLength, &a.Version
+		case "serial":
+			typeLength, chars = &a.SerialNumberTypeLength, &a.SerialNumber
+		case "atag":
+			typeLength, chars = &a.AssetTagTypeLength, &a.AssetTag
+		case "file":
+			typeLength, chars = &a.FRUFileIDTypeLength, &a.FRUFileID
+		}
+
+	default:
+		return fmt.Errorf("unknown area %q, must be chassis, board or product", area)
+	}
+
+	if strings.HasPrefix(field, "custom.") {
+		index, err := strconv.Atoi(strings.TrimPrefix(field, "custom."))
+		if err != nil || index < 0 || index > len(*custom) {
+			return fmt.Errorf("invalid custom field %q, there are %d custom fields", field, len(*custom))
+		}
+		if index == len(*custom) {
+			*custom = append(*custom, []byte(value))
+		} else {
+			(*custom)[index] = []byte(value)
+		}
+		return nil
+	}
+
+	if chars == nil {
+		return fmt.Errorf("unknown field %q of %s area", field, area)
+	}
+
+	if _, err := PackTypeLengthField(typeLength.TypeCode(), []byte(value)); err != nil || *typeLength == 0 {
+		*typeLength = 0
+	} else {
+		// keep the length part nonzero, so a binary field is not treated as auto
+		*typeLength = NewTypeLength(typeLength.TypeCode(), uint8(len(value)))
+	}
+	*chars = []byte(value)
+	return nil
+}
+
+// PatchArea returns a copy of the FRU image data (which the FRU is parsed from) with the
+// info area ("chassis", "board" or "product") replaced by the one of the FRU, in place.
+// The other areas and the offsets in the Common Header are kept, so the bytes not
+// described by the FRU (eg: padding, unknown records) are not lost.
+//
+// If the area grows past its slot (up to the next area or the end of the image),
+// the whole image is laid out again by Pack, and relaid is true.
+func (fru *FRU) PatchArea(data []byte, area string) (out []byte, relaid bool, err error) {
+	header := &FRUCommonHeader{}
+	if err := header.Unpack(data); err != nil {
+		return nil, false, fmt.Errorf("unpack fru common header failed, err: %s", err)
+	}
+
+	var offset8B uint8
+	var packed []byte
+	switch area {
+	case "chassis":
+		if fru.ChassisInfoArea == nil {
+			return nil, false, fmt.Errorf("chassis area not present")
+		}
+		offset8B = header.ChassisOffset8B
+		packed, err = fru.ChassisInfoArea.Pack()
+	case "board":
+		if fru.BoardInfoArea == nil {
+			return nil, false, fmt.Errorf("board area not present")
+		}
+		offset8B = header.BoardOffset8B
+		packed, err = fru.BoardInfoArea.Pack()
+	case "product":
+		if fru.ProductInfoArea == nil {
+			return nil, false, fmt.Errorf("product area not present")
+		}
+		offset8B = header.ProductOffset8B
+		packed, err = fru.ProductInfoArea.Pack()
+	default:
+		return nil, false, fmt.Errorf("unknown area %q, must be chassis, board or product", area)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("pack fru %s area failed, err: %s", area, err)
+	}
+
+	start := int(offset8B) * 8
+	if offset8B == 0 || len(data) < start+2 {
+		// the area is not in the image
+		out, err := fru.Pack()
+		return out, true, err
+	}
+
+	// the slot of the area ends at the start of the next area
+	end := len(data)
+	for _, o := range []uint8{header.InternalOffset8B, header.ChassisOffset8B, header.BoardOffset8B, header.ProductOffset8B, header.MultiRecordsOffset8B} {
+		if o := int(o) * 8; o > start && o < end {
+			end = o
+		}
+	}
+	if start+len(packed) > end {
+		out, err := fru.Pack()
+		return out, true, err
+	}
+
+	out = make([]byte, len(data))
+	copy(out, data)
+
+	// clear the rest of the old area, if the area shrinks
+	oldEnd := start + int(data[start+1])*8
+	if oldEnd > end {
+		oldEnd = end
+	}
+	for i := start + len(packed); i < oldEnd; i++ {
+		out[i] = 0x00
+	}
+	copy(out[start:], packed)
+	return out, false, nil
+}
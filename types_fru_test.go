@@ -0,0 +1,577 @@
+package ipmi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_PackTypeLengthField(t *testing.T) {
+	tests := []struct {
+		name        string
+		typeLength  TypeLength // zero selects the encoding automatically
+		chars       string
+		expected    []byte
+		expectedErr bool
+	}{
+		{"empty", 0, "", []byte{0xc0}, false},
+		{"auto bcdplus", 0, "12-3", []byte{0x42, 0x21, 0x3b}, false},
+		{"auto 6bit", 0, "IPMI", []byte{0x83, 0x29, 0xdc, 0xa6}, false},
+		{"auto 6bit avoided", 0, "ABC", []byte{0xc3, 'A', 'B', 'C'}, false},
+		{"auto 8bit", 0, "Acme", []byte{0xc4, 'A', 'c', 'm', 'e'}, false},
+		{"auto single char", 0, "A", []byte{0x81, 0x21}, false},
+		{"auto single lower char", 0, "x", []byte{0x01, 'x'}, false},
+		{"bcdplus odd", NewTypeLength(TypeCodeBCDPlus, 0), "123", []byte{0x42, 0x21, 0xa3}, false},
+		{"bcdplus invalid", NewTypeLength(TypeCodeBCDPlus, 0), "12A", nil, true},
+		{"6bit invalid", NewTypeLength(TypeCode6BitASCII, 0), "abc", nil, true},
+		{"8bit single char", NewTypeLength(TypeCode8BitASCII, 0), "a", nil, true},
+		{"binary", NewTypeLength(TypeCodeBinary, 1), "\x01\xff", []byte{0x02, 0x01, 0xff}, false},
+		{"too long", 0, string(bytes.Repeat([]byte("a"), 64)), nil, true},
+	}
+
+	for _, test := range tests {
+		got, err := appendFRUTypeLengthField(nil, test.typeLength, []byte(test.chars))
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("test %s expected error, got: %#v", test.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %s failed, err: %s", test.name, err)
+			continue
+		}
+		if !bytes.Equal(got, test.expected) {
+			t.Errorf("test %s not matched, got: %#v, expected: %#v", test.name, got, test.expected)
+			continue
+		}
+
+		_, _, chars, err := getFRUTypeLengthField(got, 0)
+		if err != nil {
+			t.Errorf("test %s decode failed, err: %s", test.name, err)
+			continue
+		}
+		if test.typeLength == 0 && string(chars) != test.chars {
+			t.Errorf("test %s round trip not matched, got: %q, expected: %q", test.name, chars, test.chars)
+		}
+	}
+}
+
+func Test_FRU_PackRoundTrip(t *testing.T) {
+	powerSupply := &FRURecordTypePowerSupply{
+		OverallCapacity:           800,
+		PeakVA:                    0xffff,
+		InrushCurrent:             30,
+		InrushIntervalMilliSecond: 5,
+		LowEndInputVoltageRange1:  9000,
+		HighEndInputVoltageRange1: 26400,
+		LowEndInputFrequencyRange: 47,
+		HotSwapSuppot:             true,
+		Autoswitch:                true,
+		PeakWattageHoldupSecond:   10,
+		PeakCapacity:              900,
+		CombinedWattageVoltage1:   2,
+		CombinedWattageVoltage2:   3,
+		TotalCombinedWattage:      150,
+	}
+	dcOutput := &FRURecordTypeDCOutput{
+		OutputWhenOff:          true,
+		OutputNumber:           1,
+		NominalVoltage10mV:     1200,
+		MaxNegativeVoltage10mV: -1140,
+		MaxPositiveVoltage10mV: 1260,
+		RippleNoise1mV:         120,
+		MaxCurrentDraw1mA:      60000,
+	}
+
+	fru := &FRU{
+		InternalUseArea: &FRUInternalUseArea{
+			FormatVersion: FRUFormatVersion,
+			Data:          []byte{0xde, 0xad, 0xbe, 0xef},
+		},
+		ChassisInfoArea: &FRUChassisInfoArea{
+			ChassisType:  0x17,
+			PartNumber:   []byte("CHS-1000"),
+			SerialNumber: []byte("0123456789"),
+			Custom:       [][]byte{[]byte("rev A")},
+		},
+		BoardInfoArea: &FRUBoardInfoArea{
+			MfgDateTime:  time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC),
+			Manufacturer: []byte("ACME Inc."),
+			ProductName:  []byte("MB-1"),
+			SerialNumber: []byte("B0001"),
+			PartNumber:   []byte("MB-1-001"),
+			FRUFileID:    []byte{},
+		},
+		ProductInfoArea: &FRUProductInfoArea{
+			Manufacturer:           []byte("ACME Inc."),
+			Name:                   []byte("Server"),
+			PartModel:              []byte("S-1"),
+			VersionTypeLength:      NewTypeLength(TypeCode8BitASCII, 0),
+			Version:                []byte("1.0"),
+			SerialNumberTypeLength: NewTypeLength(TypeCode6BitASCII, 0),
+			SerialNumber:           []byte("S0001"),
+			AssetTag:               []byte{},
+			FRUFileID:              []byte{},
+			Custom:                 [][]byte{[]byte("1234"), []byte("custom field")},
+		},
+		MultiRecords: []*FRUMultiRecord{
+			NewFRUMultiRecord(0x00, powerSupply.Pack()),
+			NewFRUMultiRecord(0x01, dcOutput.Pack()),
+		},
+	}
+
+	data, err := fru.Pack()
+	if err != nil {
+		t.Fatalf("pack fru failed, err: %s", err)
+	}
+
+	got, err := ParseFRUData(data)
+	if err != nil {
+		t.Fatalf("parse fru failed, err: %s", err)
+	}
+
+	if !got.CommonHeader.Valid() {
+		t.Errorf("common header checksum invalid")
+	}
+	for name, offset8B := range map[string]uint8{
+		"chassis": got.CommonHeader.ChassisOffset8B,
+		"board":   got.CommonHeader.BoardOffset8B,
+		"product": got.CommonHeader.ProductOffset8B,
+	} {
+		start := int(offset8B) * 8
+		area := data[start : start+int(data[start+1])*8]
+		if c := fruChecksum(area); c != 0 {
+			t.Errorf("%s area checksum invalid, sum: %#02x", name, c)
+		}
+	}
+
+	// the internal use area has no length field, the padding is parsed as data
+	if !bytes.HasPrefix(got.InternalUseArea.Data, fru.InternalUseArea.Data) {
+		t.Errorf("internal use data not matched, got: %#v", got.InternalUseArea.Data)
+	}
+
+	chassis := got.ChassisInfoArea
+	if chassis.ChassisType != 0x17 || string(chassis.PartNumber) != "CHS-1000" || string(chassis.SerialNumber) != "0123456789" {
+		t.Errorf("chassis area not matched, got: %+v", chassis)
+	}
+	if chassis.SerialNumberTypeLength.TypeCode() != TypeCodeBCDPlus {
+		t.Errorf("chassis serial number expected BCD plus, got: %s", chassis.SerialNumberTypeLength.Type())
+	}
+	if !reflect.DeepEqual(chassis.Custom, fru.ChassisInfoArea.Custom) {
+		t.Errorf("chassis custom fields not matched, got: %q", chassis.Custom)
+	}
+
+	board := got.BoardInfoArea
+	if !board.MfgDateTime.Equal(fru.BoardInfoArea.MfgDateTime) {
+		t.Errorf("board mfg date time not matched, got: %s", board.MfgDateTime)
+	}
+	if string(board.Manufacturer) != "ACME Inc." || string(board.ProductName) != "MB-1" ||
+		string(board.SerialNumber) != "B0001" || string(board.PartNumber) != "MB-1-001" {
+		t.Errorf("board area not matched, got: %+v", board)
+	}
+
+	product := got.ProductInfoArea
+	if string(product.Name) != "Server" || string(product.PartModel) != "S-1" || string(product.Version) != "1.0" {
+		t.Errorf("product area not matched, got: %+v", product)
+	}
+	if product.VersionTypeLength.TypeCode() != TypeCode8BitASCII || product.SerialNumberTypeLength.TypeCode() != TypeCode6BitASCII {
+		t.Errorf("product field encodings not matched, got: %s, %s", product.VersionTypeLength.Type(), product.SerialNumberTypeLength.Type())
+	}
+	// 5 chars of 6-bit ASCII occupy 4 bytes, which decode back to 5 chars.
+	if string(product.SerialNumber) != "S0001" {
+		t.Errorf("product serial number not matched, got: %q", product.SerialNumber)
+	}
+	if !reflect.DeepEqual(product.Custom, fru.ProductInfoArea.Custom) {
+		t.Errorf("product custom fields not matched, got: %q", product.Custom)
+	}
+
+	if len(got.MultiRecords) != 2 || !got.MultiRecords[1].EndOfList || got.MultiRecords[0].EndOfList {
+		t.Fatalf("multi records not matched, got: %d records", len(got.MultiRecords))
+	}
+	for _, r := range got.MultiRecords {
+		if fruChecksum(r.RecordData) != r.RecordChecksum {
+			t.Errorf("record %s checksum invalid", r.RecordType)
+		}
+	}
+
+	gotPowerSupply := &FRURecordTypePowerSupply{}
+	if err := gotPowerSupply.Unpack(got.MultiRecords[0].RecordData); err != nil {
+		t.Fatalf("unpack power supply failed, err: %s", err)
+	}
+	if !reflect.DeepEqual(gotPowerSupply, powerSupply) {
+		t.Errorf("power supply record not matched, got: %+v", gotPowerSupply)
+	}
+
+	gotDCOutput := &FRURecordTypeDCOutput{}
+	if err := gotDCOutput.Unpack(got.MultiRecords[1].RecordData); err != nil {
+		t.Fatalf("unpack dc output failed, err: %s", err)
+	}
+	if !reflect.DeepEqual(gotDCOutput, dcOutput) {
+		t.Errorf("dc output record not matched, got: %+v", gotDCOutput)
+	}
+
+	// packing the parsed FRU again produces the same image
+	again, err := got.Pack()
+	if err != nil {
+		t.Fatalf("pack parsed fru failed, err: %s", err)
+	}
+	if !bytes.Equal(again, data) {
+		t.Errorf("repacked fru not matched\ngot:      %x\nexpected: %x", again, data)
+	}
+}
+
+func Test_FRUSpec(t *testing.T) {
+	spec, err := ParseFRUSpec([]byte(`{
+		"chassis": {"type": 23, "pn": "CHS-1000", "serial": {"encoding": "6bitascii", "data": "C0001"}},
+		"board": {"date": "18/10/2026 12:30:00", "mfg": "ACME", "pname": "MB-1", "serial": "B0001", "pn": "MB-1-001",
+			"file": {"encoding": "binary", "data": "0102"}},
+		"multirecord": [{"type": 192, "data": "5a0f00"}]
+	}`))
+	if err != nil {
+		t.Fatalf("parse fru spec failed, err: %s", err)
+	}
+
+	fru, err := spec.FRU()
+	if err != nil {
+		t.Fatalf("convert fru spec failed, err: %s", err)
+	}
+	data, err := fru.Pack()
+	if err != nil {
+		t.Fatalf("pack fru failed, err: %s", err)
+	}
+	got, err := ParseFRUData(data)
+	if err != nil {
+		t.Fatalf("parse fru failed, err: %s", err)
+	}
+
+	if got.ChassisInfoArea.SerialNumberTypeLength.TypeCode() != TypeCode6BitASCII || string(got.ChassisInfoArea.SerialNumber) != "C0001" {
+		t.Errorf("chassis serial number not matched, got: %q", got.ChassisInfoArea.SerialNumber)
+	}
+	if !got.BoardInfoArea.MfgDateTime.Equal(time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC)) {
+		t.Errorf("board mfg date time not matched, got: %s", got.BoardInfoArea.MfgDateTime)
+	}
+	if got.BoardInfoArea.FRUFileIDTypeLength.TypeCode() != TypeCodeBinary || !bytes.Equal(got.BoardInfoArea.FRUFileID, []byte{0x01, 0x02}) {
+		t.Errorf("board file id not matched, got: %#v", got.BoardInfoArea.FRUFileID)
+	}
+	if got.ProductInfoArea != nil {
+		t.Errorf("product area expected absent")
+	}
+	if len(got.MultiRecords) != 1 || got.MultiRecords[0].RecordType != 0xc0 || !bytes.Equal(got.MultiRecords[0].RecordData, []byte{0x5a, 0x0f, 0x00}) {
+		t.Errorf("multi record not matched, got: %+v", got.MultiRecords)
+	}
+}
+
+func Test_FRU_SetField(t *testing.T) {
+	fru := &FRU{
+		BoardInfoArea: &FRUBoardInfoArea{
+			SerialNumberTypeLength: NewTypeLength(TypeCode8BitASCII, 5),
+			SerialNumber:           []byte("B0001"),
+			PartNumberTypeLength:   NewTypeLength(TypeCodeBCDPlus, 2),
+			PartNumber:             []byte("1234"),
+		},
+	}
+
+	tests := []struct {
+		field       string
+		value       string
+		typeCode    uint8
+		expectedErr bool
+	}{
+		{"serial", "B0002", TypeCode8BitASCII, false},
+		{"pn", "5678", TypeCodeBCDPlus, false},
+		{"pn", "PN-1", TypeCode6BitASCII, false}, // can not be BCD plus, select automatically
+		{"custom.0", "x", 0, false},
+		{"custom.2", "y", 0, true},
+		{"unknown", "z", 0, true},
+	}
+
+	for _, test := range tests {
+		err := fru.SetField("board", test.field, test.value)
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("test %s expected error", test.field)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %s failed, err: %s", test.field, err)
+		}
+	}
+
+	data, err := fru.Pack()
+	if err != nil {
+		t.Fatalf("pack fru failed, err: %s", err)
+	}
+	got, err := ParseFRUData(data)
+	if err != nil {
+		t.Fatalf("parse fru failed, err: %s", err)
+	}
+	board := got.BoardInfoArea
+	if string(board.SerialNumber) != "B0002" || board.SerialNumberTypeLength.TypeCode() != TypeCode8BitASCII {
+		t.Errorf("serial number not matched, got: %q (%s)", board.SerialNumber, board.SerialNumberTypeLength.Type())
+	}
+	if string(board.PartNumber) != "PN-1" || board.PartNumberTypeLength.TypeCode() != TypeCode6BitASCII {
+		t.Errorf("part number not matched, got: %q (%s)", board.PartNumber, board.PartNumberTypeLength.Type())
+	}
+	if len(board.Custom) != 1 || string(board.Custom[0]) != "x" {
+		t.Errorf("custom fields not matched, got: %q", board.Custom)
+	}
+
+	if err := fru.SetField("product", "serial", "P1"); err == nil {
+		t.Errorf("absent product area expected error")
+	}
+}
+
+func Test_FRU_DecodeMultiRecords(t *testing.T) {
+	picmg := (&FRURecordTypeOEM{
+		ManufacturerID: uint32(OEM_PICMG),
+		Data:           []byte{0x14, 0x00, 0x00, 0x41, 0x2f, 0x00, 0x00},
+	}).Pack()
+	mgmt := (&FRURecordTypeManagementAccess{SubRecordType: 0x02, Data: []byte("node-1")}).Pack()
+
+	fru := &FRU{
+		InternalUseArea: &FRUInternalUseArea{Data: []byte{0xca, 0xfe}},
+		MultiRecords: []*FRUMultiRecord{
+			NewFRUMultiRecord(0x03, mgmt),
+			NewFRUMultiRecord(0x06, []byte{0x01, 0x02}),
+			NewFRUMultiRecord(0xc0, picmg),
+			NewFRUMultiRecord(0xc1, []byte{0x57, 0x01, 0x00, 0xaa}),
+		},
+	}
+	data, err := fru.Pack()
+	if err != nil {
+		t.Fatalf("pack fru failed, err: %s", err)
+	}
+	got, err := ParseFRUData(data)
+	if err != nil {
+		t.Fatalf("parse fru failed, err: %s", err)
+	}
+
+	if r, ok := got.MultiRecords[0].Record.(*FRURecordTypeManagementAccess); !ok || string(r.Data) != "node-1" {
+		t.Errorf("management access record not decoded, got: %#v", got.MultiRecords[0].Record)
+	}
+	if got.MultiRecords[1].Record != nil {
+		t.Errorf("ASF record expected not decoded, got: %#v", got.MultiRecords[1].Record)
+	}
+	oem, ok := got.MultiRecords[2].Record.(*FRURecordTypeOEM)
+	if !ok {
+		t.Fatalf("OEM record not decoded, got: %#v", got.MultiRecords[2].Record)
+	}
+	expected := &FRURecordPICMG{
+		RecordID:      0x14,
+		FormatVersion: 0x00,
+		Data:          []byte{0x00, 0x41, 0x2f, 0x00, 0x00},
+		Record: &PICMGBoardP2PConnectivity{
+			OEMGUIDs: [][16]byte{},
+			LinkDescriptors: []*PICMGLinkDescriptor{
+				{ChannelNumber: 1, Interface: 0x01, Ports: 0x0f, LinkType: 0x02},
+			},
+		},
+	}
+	if !reflect.DeepEqual(oem.Decoded, expected) {
+		t.Errorf("PICMG record not matched, got: %#v", oem.Decoded)
+	}
+	if r, ok := got.MultiRecords[3].Record.(*FRURecordTypeOEM); !ok || r.ManufacturerID != 0x157 || r.Decoded != nil {
+		t.Errorf("unregistered OEM record not matched, got: %#v", got.MultiRecords[3].Record)
+	}
+
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("marshal fru failed, err: %s", err)
+	}
+	for _, s := range []string{
+		`"internal_use":{"data":"cafe`,
+		`"sub_record_name":"System Name"`,
+		`"data":"0102","format_version":2,"type":"ASF Fixed SMBus Device"`,
+		`"record_name":"Board Point-to-Point Connectivity"`,
+		`"decoded":{"OEMGUIDs":[],"LinkDescriptors":[{"ChannelNumber":1,"Interface":1,"Ports":15,"LinkType":2`,
+	} {
+		if !strings.Contains(string(b), s) {
+			t.Errorf("json output expected to contain %s, got: %s", s, b)
+		}
+	}
+}
+
+func Test_decodeFRURecordPICMG(t *testing.T) {
+	shelfAddress := append([]byte{0xc5}, []byte("shelf")...)
+	shelfAddress = append(shelfAddress, make([]byte, 15)...)
+
+	tests := []struct {
+		name        string
+		data        []byte
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:     "board point-to-point connectivity",
+			data:     []byte{0x14, 0x00, 0x00, 0x41, 0x2f, 0x00, 0x00, 0x02, 0x11, 0x10, 0x01},
+			expected: "PICMG Board Point-to-Point Connectivity (0x14), version 0, 0 OEM GUIDs, links: [Fabric Interface channel 1 ports 0xf, PICMG 3.1 Ethernet Fabric Interface (0x02), extension 0x0, group 0; Base Interface channel 2 ports 0x1, PICMG 3.0 Base Interface 10/100/1000 BASE-T (0x01), extension 0x1, group 1]",
+		},
+		{
+			name:        "board point-to-point connectivity, truncated descriptor",
+			data:        []byte{0x14, 0x00, 0x00, 0x41, 0x2f, 0x00},
+			expectedErr: true,
+		},
+		{
+			name:        "board point-to-point connectivity, truncated guid",
+			data:        []byte{0x14, 0x00, 0x01, 0x00, 0x01},
+			expectedErr: true,
+		},
+		{
+			name:     "module current requirements",
+			data:     []byte{0x16, 0x00, 0x32},
+			expected: "PICMG Module Current Requirements (0x16), version 0, current draw 5.0 A",
+		},
+		{
+			name:        "module current requirements, truncated",
+			data:        []byte{0x16, 0x00},
+			expectedErr: true,
+		},
+		{
+			name:     "address table",
+			data:     append(append([]byte{0x10, 0x00}, shelfAddress...), 0x02, 0x41, 0x01, 0x00, 0x42, 0x02, 0x00),
+			expected: `PICMG Address Table (0x10), version 0, shelf address "shelf", entries: [0x41: PICMG Board 1; 0x42: PICMG Board 2]`,
+		},
+		{
+			name:        "address table, truncated entries",
+			data:        append(append([]byte{0x10, 0x00}, shelfAddress...), 0x02, 0x41, 0x01, 0x00),
+			expectedErr: true,
+		},
+		{
+			name:     "not decoded",
+			data:     []byte{0x11, 0x00, 0xaa},
+			expected: "PICMG Shelf Power Distribution (0x11), version 0, data aa",
+		},
+	}
+
+	for _, test := range tests {
+		decoded, err := decodeFRURecordPICMG(test.data)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("test %s failed, got err: %v, expected err: %v", test.name, err, test.expectedErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		got := picmgSummary(decoded.(*FRURecordPICMG))
+		if got != test.expected {
+			t.Errorf("test %s not matched, got: %s, expected: %s", test.name, got, test.expected)
+		}
+	}
+}
+
+func Test_FRU_PatchArea(t *testing.T) {
+	fru := &FRU{
+		BoardInfoArea: &FRUBoardInfoArea{
+			Manufacturer: []byte("ACME Inc."),
+			ProductName:  []byte("MB-1"),
+			SerialNumber: []byte("B0001-0000-0000-0000"),
+			PartNumber:   []byte("MB-1-001"),
+			FRUFileID:    []byte{},
+		},
+		ProductInfoArea: &FRUProductInfoArea{
+			Manufacturer: []byte("ACME Inc."),
+			Name:         []byte("Server"),
+			SerialNumber: []byte("S0001"),
+			AssetTag:     []byte{},
+			FRUFileID:    []byte{},
+		},
+	}
+	image, err := fru.Pack()
+	if err != nil {
+		t.Fatalf("pack fru failed, err: %s", err)
+	}
+	// the bytes after the last area are not described by the FRU
+	trailer := []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0x00}
+	image = append(image, trailer...)
+
+	tests := []struct {
+		name  string
+		area  string
+		field string
+		value string
+
+		expectedRelaid bool
+		// whether the bytes of the other areas and the trailer are kept
+		expectedKept bool
+	}{
+		{
+			name:         "board shrinks",
+			area:         "board",
+			field:        "serial",
+			value:        "B1",
+			expectedKept: true,
+		},
+		{
+			name:         "product grows into free space",
+			area:         "product",
+			field:        "custom.0",
+			value:        "more",
+			expectedKept: true,
+		},
+		{
+			name:           "board grows past the product",
+			area:           "board",
+			field:          "serial",
+			value:          "B0001-0000-0000-0000-0000-0000-0000-0000",
+			expectedRelaid: true,
+		},
+	}
+
+	for _, test := range tests {
+		parsed, err := ParseFRUData(image)
+		if err != nil {
+			t.Fatalf("parse fru failed, err: %s", err)
+		}
+		if err := parsed.SetField(test.area, test.field, test.value); err != nil {
+			t.Errorf("test %s: SetField failed, err: %s", test.name, err)
+			continue
+		}
+		out, relaid, err := parsed.PatchArea(image, test.area)
+		if err != nil {
+			t.Errorf("test %s: PatchArea failed, err: %s", test.name, err)
+			continue
+		}
+		if relaid != test.expectedRelaid {
+			t.Errorf("test %s relaid not matched, got: %v, expected: %v", test.name, relaid, test.expectedRelaid)
+		}
+
+		got, err := ParseFRUData(out)
+		if err != nil {
+			t.Errorf("test %s: parse patched fru failed, err: %s", test.name, err)
+			continue
+		}
+		value := string(got.BoardInfoArea.SerialNumber)
+		if test.area == "product" {
+			value = string(bytes.Join(got.ProductInfoArea.Custom, []byte(",")))
+		}
+		if value != test.value {
+			t.Errorf("test %s not matched, got: %s, expected: %s", test.name, value, test.value)
+		}
+
+		if !test.expectedKept {
+			continue
+		}
+		if !bytes.Equal(out[:FRUCommonHeaderSize], image[:FRUCommonHeaderSize]) {
+			t.Errorf("test %s common header not matched, got: % x, expected: % x", test.name, out[:FRUCommonHeaderSize], image[:FRUCommonHeaderSize])
+		}
+		if test.area == "board" {
+			productOffset := int(parsed.CommonHeader.ProductOffset8B) * 8
+			if !bytes.Equal(out[productOffset:], image[productOffset:]) {
+				t.Errorf("test %s product area not kept, got: % x, expected: % x", test.name, out[productOffset:], image[productOffset:])
+			}
+		} else {
+			boardEnd := int(parsed.CommonHeader.ProductOffset8B) * 8
+			if !bytes.Equal(out[:boardEnd], image[:boardEnd]) {
+				t.Errorf("test %s board area not kept, got: % x, expected: % x", test.name, out[:boardEnd], image[:boardEnd])
+			}
+			if !bytes.HasSuffix(out, trailer[8:]) {
+				t.Errorf("test %s trailer not kept, got: % x", test.name, out)
+			}
+		}
+	}
+}
@@ -151,7 +151,7 @@ func (c *Client) BuildIPMIRequest(reqCmd Request) (*IPMIRequest, error) {
 		ResponderAddr: BMC_SA,
 
 		NetFn:        reqCmd.Command().NetFn,
-		ResponderLUN: uint8(IPMB_LUN_BMC),
+		ResponderLUN: c.target.lun,
 
 		RequesterAddr: RemoteConsole_SWID,
 
@@ -172,6 +172,37 @@ func (c *Client) BuildIPMIRequest(reqCmd Request) (*IPMIRequest, error) {
 	return ipmiReq, nil
 }
 
+// buildIPMBRequest creates the IPMB request of the Command Request to the target,
+// which is sent by the BMC on behalf of the client, see exchangeLANBridged.
+func (c *Client) buildIPMBRequest(reqCmd Request, t target) *IPMIRequest {
+	c.lock()
+	defer c.unlock()
+
+	ipmbReq := &IPMIRequest{
+		ResponderAddr: t.addr,
+
+		NetFn:        reqCmd.Command().NetFn,
+		ResponderLUN: t.lun,
+
+		// the BMC is the requester on IPMB
+		RequesterAddr: BMC_SA,
+
+		RequesterSequence: c.session.ipmiSeq,
+		RequesterLUN:      0x00,
+
+		Command:     reqCmd.Command().ID,
+		CommandData: reqCmd.Pack(),
+	}
+
+	c.session.ipmiSeq += 1
+	if c.session.ipmiSeq > IPMIRequesterSequenceMax {
+		c.session.ipmiSeq = 1
+	}
+
+	ipmbReq.ComputeChecksum()
+	return ipmbReq
+}
+
 // AllCC returns all possible completion codes for the specified response.
 // i.e.:
 //
@@ -97,13 +97,46 @@ var LanParams = []LanParam{
 	{Selector: LanParam_CommunityString, DataSize: 18, Name: "SNMP Community String"},
 	{Selector: LanParam_AlertDestinationsNumber, DataSize: 1, Name: "Number of Destinations"},
 	{Selector: LanParam_AlertDestinationType, DataSize: 4, Name: "Destination Type"},
-	{Selector: LanParam_AlertDestinationAddress, DataSize: 18, Name: "Destination Addresses"},
+	{Selector: LanParam_AlertDestinationAddress, DataSize: 13, Name: "Destination Addresses"},
 	{Selector: LanParam_VLANID, DataSize: 2, Name: "802.1q VLAN ID"},
 	{Selector: LanParam_VLANPriority, DataSize: 1, Name: "802.1q VLAN Priority"},
 	{Selector: LanParam_CipherSuiteEntrySupport, DataSize: 1, Name: "RMCP+ Cipher Suite Count"},
 	{Selector: LanParam_CipherSuiteEntries, DataSize: 17, Name: "RMCP+ Cipher Suites"},
 	{Selector: LanParam_CihperSuitePrivilegeLevels, DataSize: 9, Name: "Cipher Suite Priv Max"},
 	{Selector: LanParam_BadPassordThreshold, DataSize: 4, Name: "Bad Password Threshold"},
+	{Selector: LanParam_IP6Support, DataSize: 1, Name: "IPv6/IPv4 Support"},
+	{Selector: LanParam_IP6Enables, DataSize: 1, Name: "IPv6/IPv4 Addressing Enables"},
+	{Selector: LanParam_IP6StaticTrafficClass, DataSize: 1, Name: "IPv6 Static Traffic Class"},
+	{Selector: LanParam_IP6StaticHopLimit, DataSize: 1, Name: "IPv6 Static Hop Limit"},
+	{Selector: LanParam_IP6FlowLabel, DataSize: 3, Name: "IPv6 Flow Label"},
+	{Selector: LanParam_IP6Status, DataSize: 3, Name: "IPv6 Status"},
+	{Selector: LanParam_IP6StaticAddr, DataSize: 20, Name: "IPv6 Static Address"},
+	{Selector: LanParam_IP6DHCP6StaticDUIDLength, DataSize: 1, Name: "IPv6 DHCPv6 Static DUID Storage Length"},
+	{Selector: LanParam_IP6DynamicAddr, DataSize: 20, Name: "IPv6 Dynamic Address"},
+	{Selector: LanParam_IP6DHCP6DynamicDUIDLenth, DataSize: 1, Name: "IPv6 DHCPv6 Dynamic DUID Storage Length"},
+	{Selector: LanParam_IP6DHCP6TimingConfigSupport, DataSize: 1, Name: "IPv6 DHCPv6 Timing Configuration Support"},
+	{Selector: LanParam_IP6RouterAddressConfigControl, DataSize: 1, Name: "IPv6 Router Address Configuration Control"},
+	{Selector: LanParam_IP6StaticRouter1IP, DataSize: 16, Name: "IPv6 Static Router 1 IP Address"},
+	{Selector: LanParam_IP6StaticRouter1MAC, DataSize: 6, Name: "IPv6 Static Router 1 MAC Address"},
+	{Selector: LanParam_IP6StaticRouter1PrefixLength, DataSize: 1, Name: "IPv6 Static Router 1 Prefix Length"},
+	{Selector: LanParam_IP6StaticRouter1PrefixValue, DataSize: 16, Name: "IPv6 Static Router 1 Prefix Value"},
+	{Selector: LanParam_IP6StaticRouter2IP, DataSize: 16, Name: "IPv6 Static Router 2 IP Address"},
+	{Selector: LanParam_IP6StaticRouter2MAC, DataSize: 6, Name: "IPv6 Static Router 2 MAC Address"},
+	{Selector: LanParam_IP6StaticRouter2PrefixLength, DataSize: 1, Name: "IPv6 Static Router 2 Prefix Length"},
+	{Selector: LanParam_IP6StaticRouter2PrefixValue, DataSize: 16, Name: "IPv6 Static Router 2 Prefix Value"},
+	{Selector: LanParam_IP6DynamicRouterSetsNumber, DataSize: 1, Name: "IPv6 Number of Dynamic Router Info Sets"},
+	{Selector: LanParam_IP6DynamicRouterIP, DataSize: 17, Name: "IPv6 Dynamic Router IP Address"},
+	{Selector: LanParam_IP6DynamicRouterMAC, DataSize: 7, Name: "IPv6 Dynamic Router MAC Address"},
+	{Selector: LanParam_IP6DynamicRouterPrefixLength, DataSize: 2, Name: "IPv6 Dynamic Router Prefix Length"},
+	{Selector: LanParam_IP6DynamicRouterPrefixValue, DataSize: 17, Name: "IPv6 Dynamic Router Prefix Value"},
+	{Selector: LanParam_IP6DynamicRouterReceivedHopLimit, DataSize: 1, Name: "IPv6 Dynamic Router Received Hop Limit"},
+	{Selector: LanParam_IP6NDSLAACTimingConfigSupport, DataSize: 1, Name: "IPv6 ND/SLAAC Timing Configuration Support"},
+	{Selector: LanParam_IP6NDSLAACTiming, DataSize: 2, Name: "IPv6 ND/SLAAC Timing Configuration"},
+}
+
+// isIP6 returns whether the param is one of the IPv6 params.
+func (lanParam LanParamSelector) isIP6() bool {
+	return lanParam >= LanParam_IP6Support && lanParam <= LanParam_IP6NDSLAACTiming
 }
 
 func (lanParam LanParamSelector) String() string {
@@ -151,7 +184,26 @@ type LanConfig struct {
 	AlertDestinationVLAN          AlertDestinationVLAN
 	BadPasswordThreshold          BadPasswordThreshold
 
-	IP6Support IP6Support
+	IP6Support                       IP6Support
+	IP6Enables                       IP6Enables
+	IP6StaticTrafficClass            uint8
+	IP6StaticHopLimit                uint8
+	IP6FlowLabel                     uint32
+	IP6Status                        IP6Status
+	IP6StaticAddresses               []IP6Address
+	IP6DHCP6StaticDUIDStorageLength  uint8
+	IP6DynamicAddresses              []IP6Address
+	IP6DHCP6DynamicDUIDStorageLength uint8
+	IP6DHCP6TimingConfigSupport      IP6TimingConfigSupport
+	IP6RouterConfig                  IP6RouterConfig
+	IP6StaticRouter1                 IP6Router
+	IP6StaticRouter2                 IP6Router
+	IP6DynamicRouterSetsNumber       uint8
+	IP6DynamicRouters                []IP6Router
+	IP6DynamicRouterReceivedHopLimit uint8
+	IP6NDSLAACTimingConfigSupport    IP6TimingConfigSupport
+	// raw data (set selector 0, block selector 0) of ND/SLAAC Timing Configuration
+	IP6NDSLAACTimingConfig []byte
 }
 
 func (lanConfig *LanConfig) Format() string {
@@ -185,7 +237,7 @@ Cipher Suite Priv Max   : %s
                         :     o=OPERATOR
                         :     a=ADMIN
                         :     O=OEM
-Bad Password Threshold  : %d`,
+Bad Password Threshold  : %d%s`,
 		lanConfig.SetInProgress,
 		lanConfig.IPSource,
 		lanConfig.IP,
@@ -198,11 +250,71 @@ Bad Password Threshold  : %d`,
 		cipherSuitesStr,
 		levelsStr,
 		lanConfig.BadPasswordThreshold.Threshold,
+		lanConfig.FormatIP6(),
 	)
 }
 
+// FormatIP6 returns the formatted IPv6 parameters, it returns empty string
+// if IPv6 is not supported.
+func (lanConfig *LanConfig) FormatIP6() string {
+	support := lanConfig.IP6Support
+	if !support.CanUseIP6Only && !support.CanUseBothIP4AndIP6 && !support.SupportIP6AlertDestination {
+		return ""
+	}
+
+	var buf strings.Builder
+	buf.WriteString(fmt.Sprintf(`
+IPv6/IPv4 Support       : IPv6 only: %s, IPv4 and IPv6: %s, IPv6 Alert Destination: %s
+IPv6/IPv4 Addressing    : %s
+IPv6 Header Traffic Class : %#02x
+IPv6 Header Hop Limit   : %d
+IPv6 Header Flow Label  : %#05x
+IPv6 Status             : Max Static Addresses: %d, Max Dynamic Addresses: %d, DHCPv6: %s, SLAAC: %s`,
+		formatBool(support.CanUseIP6Only, "yes", "no"),
+		formatBool(support.CanUseBothIP4AndIP6, "yes", "no"),
+		formatBool(support.SupportIP6AlertDestination, "yes", "no"),
+		lanConfig.IP6Enables,
+		lanConfig.IP6StaticTrafficClass,
+		lanConfig.IP6StaticHopLimit,
+		lanConfig.IP6FlowLabel,
+		lanConfig.IP6Status.StaticAddressMax,
+		lanConfig.IP6Status.DynamicAddressMax,
+		formatBool(lanConfig.IP6Status.SupportDHCP6, "supported", "not supported"),
+		formatBool(lanConfig.IP6Status.SupportSLAAC, "supported", "not supported"),
+	))
+
+	for _, addr := range lanConfig.IP6StaticAddresses {
+		buf.WriteString(fmt.Sprintf("\nIPv6 Static Address %d   : %s", addr.SetSelector, addr.Format()))
+	}
+	buf.WriteString(fmt.Sprintf("\nIPv6 DHCPv6 Static DUID : %d blocks", lanConfig.IP6DHCP6StaticDUIDStorageLength))
+	for _, addr := range lanConfig.IP6DynamicAddresses {
+		buf.WriteString(fmt.Sprintf("\nIPv6 Dynamic Address %d  : %s", addr.SetSelector, addr.Format()))
+	}
+	buf.WriteString(fmt.Sprintf("\nIPv6 DHCPv6 Dynamic DUID : %d blocks", lanConfig.IP6DHCP6DynamicDUIDStorageLength))
+	buf.WriteString(fmt.Sprintf("\nIPv6 DHCPv6 Timing Conf : %s", lanConfig.IP6DHCP6TimingConfigSupport))
+	buf.WriteString(fmt.Sprintf("\nIPv6 Router Config      : Static: %s, Dynamic: %s",
+		formatBool(lanConfig.IP6RouterConfig.EnableStatic, "enabled", "disabled"),
+		formatBool(lanConfig.IP6RouterConfig.EnableDynamic, "enabled", "disabled"),
+	))
+	buf.WriteString(fmt.Sprintf("\nIPv6 Static Router 1    : %s", lanConfig.IP6StaticRouter1.Format()))
+	buf.WriteString(fmt.Sprintf("\nIPv6 Static Router 2    : %s", lanConfig.IP6StaticRouter2.Format()))
+	for _, router := range lanConfig.IP6DynamicRouters {
+		buf.WriteString(fmt.Sprintf("\nIPv6 Dynamic Router %d   : %s", router.SetSelector, router.Format()))
+	}
+	buf.WriteString(fmt.Sprintf("\nIPv6 Dynamic Router Received Hop Limit : %d", lanConfig.IP6DynamicRouterReceivedHopLimit))
+	buf.WriteString(fmt.Sprintf("\nIPv6 ND/SLAAC Timing Conf : %s", lanConfig.IP6NDSLAACTimingConfigSupport))
+
+	return buf.String()
+}
+
 type SetInProgress uint8
 
+const (
+	SetInProgressSetComplete   SetInProgress = 0x00
+	SetInProgressSetInProgress SetInProgress = 0x01
+	SetInProgressCommitWrite   SetInProgress = 0x02
+)
+
 func (p SetInProgress) String() string {
 	m := map[SetInProgress]string{
 		0x00: "set complete",
@@ -278,6 +390,17 @@ type ARPControl struct {
 	GratuitousARPEnabled bool
 }
 
+func (arpControl ARPControl) Pack() []byte {
+	var b uint8
+	if arpControl.ARPResponseEnabled {
+		b = setBit1(b)
+	}
+	if arpControl.GratuitousARPEnabled {
+		b = setBit0(b)
+	}
+	return []byte{b}
+}
+
 type CommunityString [18]byte
 
 func (c CommunityString) String() string {
@@ -298,6 +421,7 @@ func NewCommunityString(s string) CommunityString {
 	for i := 0; i < 18; i++ {
 		if i < len(b) {
 			o[i] = b[i]
+			continue
 		}
 		o[i] = 0x00
 	}
@@ -329,6 +453,18 @@ type AlertDestinationType struct {
 	Retries uint8
 }
 
+func (t *AlertDestinationType) Pack() []byte {
+	out := make([]byte, 4)
+	out[0] = t.SetSelector & 0x0f
+	out[1] = t.DestinationType & 0x07
+	if t.AlertSupportAcknowledge {
+		out[1] = setBit7(out[1])
+	}
+	out[2] = t.AlertAcknowledgeTimeout
+	out[3] = t.Retries & 0x07
+	return out
+}
+
 type AlertDestinationAddress struct {
 	SetSelector uint8
 
@@ -343,6 +479,26 @@ type AlertDestinationAddress struct {
 	IP6IP net.IP
 }
 
+func (a *AlertDestinationAddress) Pack() []byte {
+	if a.AddressFormat == 0x01 {
+		out := make([]byte, 18)
+		out[0] = a.SetSelector & 0x0f
+		out[1] = a.AddressFormat << 4
+		copy(out[2:18], a.IP6IP.To16())
+		return out
+	}
+
+	out := make([]byte, 13)
+	out[0] = a.SetSelector & 0x0f
+	out[1] = a.AddressFormat << 4
+	if a.IP4UseBackupGateway {
+		out[2] = setBit0(out[2])
+	}
+	copy(out[3:7], a.IP4IP.To4())
+	copy(out[7:13], a.IP4MAC)
+	return out
+}
+
 type VLAN struct {
 	Enabled  bool
 	ID       uint16
@@ -386,3 +542,181 @@ type IP6Support struct {
 	// Implementation can be configured to use IPv6 addresses only.
 	CanUseIP6Only bool
 }
+
+// IP6Enables is the IPv6/IPv4 Addressing Enables parameter.
+type IP6Enables uint8
+
+const (
+	IP6EnablesIP4Only   IP6Enables = 0x00 // IPv6 addressing disabled
+	IP6EnablesIP6Only   IP6Enables = 0x01
+	IP6EnablesIP4AndIP6 IP6Enables = 0x02
+)
+
+func (e IP6Enables) String() string {
+	m := map[IP6Enables]string{
+		0x00: "IPv4 only",
+		0x01: "IPv6 only",
+		0x02: "IPv4 and IPv6",
+	}
+	s, ok := m[e]
+	if ok {
+		return s
+	}
+	return "reserved"
+}
+
+type IP6Status struct {
+	// Maximum number of static IPv6 addresses
+	StaticAddressMax uint8
+	// Maximum number of Dynamic (SLAAC/DHCPv6) addresses
+	DynamicAddressMax uint8
+
+	SupportDHCP6 bool
+	SupportSLAAC bool
+}
+
+type IP6AddressSource uint8
+
+const (
+	IP6AddressSourceStatic IP6AddressSource = 0x00
+	IP6AddressSourceSLAAC  IP6AddressSource = 0x01
+	IP6AddressSourceDHCP6  IP6AddressSource = 0x02
+)
+
+func (s IP6AddressSource) String() string {
+	m := map[IP6AddressSource]string{
+		0x00: "static",
+		0x01: "SLAAC",
+		0x02: "DHCPv6",
+	}
+	str, ok := m[s]
+	if ok {
+		return str
+	}
+	return "reserved"
+}
+
+type IP6AddressStatus uint8
+
+const (
+	IP6AddressStatusActive     IP6AddressStatus = 0x00
+	IP6AddressStatusDisabled   IP6AddressStatus = 0x01
+	IP6AddressStatusPending    IP6AddressStatus = 0x02
+	IP6AddressStatusFailed     IP6AddressStatus = 0x03
+	IP6AddressStatusDeprecated IP6AddressStatus = 0x04
+	IP6AddressStatusInvalid    IP6AddressStatus = 0x05
+)
+
+func (s IP6AddressStatus) String() string {
+	m := map[IP6AddressStatus]string{
+		0x00: "active",
+		0x01: "disabled",
+		0x02: "pending",
+		0x03: "failed",
+		0x04: "deprecated",
+		0x05: "invalid",
+	}
+	str, ok := m[s]
+	if ok {
+		return str
+	}
+	return "reserved"
+}
+
+// IP6Address is used by IPv6 Static Addresses and IPv6 Dynamic Address parameters.
+type IP6Address struct {
+	SetSelector uint8
+
+	// Only for static addresses
+	Enabled bool
+	Source  IP6AddressSource
+
+	Address      net.IP
+	PrefixLength uint8
+
+	// read only
+	Status IP6AddressStatus
+}
+
+func (a *IP6Address) Format() string {
+	return fmt.Sprintf("%s/%d (%s, %s, %s)",
+		a.Address, a.PrefixLength,
+		a.Source,
+		formatBool(a.Enabled, "enabled", "disabled"),
+		a.Status,
+	)
+}
+
+func (a *IP6Address) Pack() []byte {
+	out := make([]byte, 19)
+	out[0] = a.SetSelector
+	out[1] = uint8(a.Source) & 0x0f
+	if a.Enabled {
+		out[1] = setBit7(out[1])
+	}
+	copy(out[2:18], a.Address.To16())
+	out[18] = a.PrefixLength
+	return out
+}
+
+func (a *IP6Address) Unpack(data []byte) error {
+	if len(data) < 20 {
+		return ErrUnpackedDataTooShort
+	}
+	a.SetSelector = data[0]
+	a.Enabled = isBit7Set(data[1])
+	a.Source = IP6AddressSource(data[1] & 0x0f)
+	a.Address = net.IP(append([]byte{}, data[2:18]...))
+	a.PrefixLength = data[18]
+	a.Status = IP6AddressStatus(data[19])
+	return nil
+}
+
+// IP6TimingConfigSupport is used by DHCPv6 and ND/SLAAC Timing Configuration Support parameters.
+type IP6TimingConfigSupport uint8
+
+func (s IP6TimingConfigSupport) String() string {
+	m := map[IP6TimingConfigSupport]string{
+		0x00: "not supported",
+		0x01: "global",
+		0x02: "per interface",
+	}
+	str, ok := m[s]
+	if ok {
+		return str
+	}
+	return "reserved"
+}
+
+type IP6RouterConfig struct {
+	// Enable dynamic router address configuration via router advertisement messages.
+	EnableDynamic bool
+	// Enable static router address
+	EnableStatic bool
+}
+
+func (r IP6RouterConfig) Pack() []byte {
+	var b uint8
+	if r.EnableDynamic {
+		b = setBit1(b)
+	}
+	if r.EnableStatic {
+		b = setBit0(b)
+	}
+	return []byte{b}
+}
+
+// IP6Router holds the info of IPv6 static routers or dynamic routers.
+type IP6Router struct {
+	// Only for dynamic routers
+	SetSelector uint8
+
+	IP           net.IP
+	MAC          net.HardwareAddr
+	PrefixLength uint8
+	PrefixValue  net.IP
+}
+
+func (r *IP6Router) Format() string {
+	return fmt.Sprintf("IP: %s, MAC: %s, Prefix: %s/%d", r.IP, r.MAC, r.PrefixValue, r.PrefixLength)
+}
@@ -40,6 +40,7 @@ const (
 	OEM_RARITAN                      = 13742
 	OEM_KONTRON                      = 15000
 	OEM_PPS                          = 16394
+	OEM_LENOVO                       = 19046
 	OEM_IBM_20301                    = 20301 /* 20301 for [IBM eServer X] */
 	OEM_AMI                          = 20974
 	OEM_FOXCONN                      = 22238
@@ -50,6 +51,7 @@ const (
 	OEM_INSPUR                       = 37945
 	OEM_TENCENT                      = 41475
 	OEM_BYTEDANCE                    = 46045
+	OEM_HPE                          = 47196
 	OEM_SUPERMICRO_47488             = 47488
 	OEM_YADRO                        = 49769
 )
@@ -89,6 +91,7 @@ func (oem OEM) String() string {
 		13742: "Raritan", // 力登
 		15000: "Kontron", // 控创
 		16394: "PPS",
+		19046: "Lenovo",
 		20301: "IBM",
 		20974: "AMI",
 		22238: "Foxconn",
@@ -99,6 +102,7 @@ func (oem OEM) String() string {
 		37945: "Inspur",    // 浪潮
 		41475: "Tencent",   // 腾讯
 		46045: "ByteDance", // 字节跳动
+		47196: "HPE",
 		47488: "Supermicro",
 		49769: "Yadro",
 	}
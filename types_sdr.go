@@ -3,6 +3,7 @@ package ipmi
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 
 	"github.com/olekukonko/tablewriter"
 )
@@ -74,6 +75,9 @@ type SDR struct {
 	BMCChannelInfo              *SDRBMCChannelInfo
 	OEM                         *SDROEM
 	Reserved                    *SDRReserved
+
+	// raw holds the whole record data (header and body) the SDR is parsed from.
+	raw []byte
 }
 
 func (sdr *SDR) String() string {
@@ -142,6 +146,87 @@ func (sdr *SDR) SensorName() string {
 	return ""
 }
 
+// ExpandShared returns one SDR for each sensor sharing the Compact or Event-Only record.
+//
+// The sensors sharing the record have sequential sensor numbers starting with the
+// sensor number of the record, their ID Strings are appended with the instance modifier
+// (numeric or alpha) generated from the modifier offset, and their entity instances
+// increment if Entity Instance Sharing is set.
+// For other records or records not shared, it returns a slice only containing sdr itself.
+func (sdr *SDR) ExpandShared() []*SDR {
+	switch sdr.RecordHeader.RecordType {
+	case SDRRecordTypeCompactSensor:
+		compact := sdr.Compact
+		if compact.ShareCount <= 1 {
+			return []*SDR{sdr}
+		}
+
+		out := make([]*SDR, 0, compact.ShareCount)
+		for i := uint8(0); i < compact.ShareCount; i++ {
+			c := *compact
+			c.SensorNumber = compact.SensorNumber + SensorNumber(i)
+			if compact.EntityInstanceSharing == 1 {
+				c.SensorEntityInstance = compact.SensorEntityInstance + EntityInstance(i)
+			}
+			c.IDStringBytes = sharedSensorName(compact.IDStringBytes, compact.IDStringInstanceModifierType, compact.IDStringInstanceModifierOffset, i)
+			c.ShareCount = 1
+
+			s := *sdr
+			s.Compact = &c
+			out = append(out, &s)
+		}
+		return out
+
+	case SDRRecordTypeEventOnly:
+		eventOnly := sdr.EventOnly
+		if eventOnly.ShareCount <= 1 {
+			return []*SDR{sdr}
+		}
+
+		out := make([]*SDR, 0, eventOnly.ShareCount)
+		for i := uint8(0); i < eventOnly.ShareCount; i++ {
+			e := *eventOnly
+			e.SensorNumber = eventOnly.SensorNumber + SensorNumber(i)
+			if eventOnly.EntityInstanceSharing {
+				e.SensorEntityInstance = eventOnly.SensorEntityInstance + EntityInstance(i)
+			}
+			e.IDStringBytes = sharedSensorName(eventOnly.IDStringBytes, eventOnly.IDStringInstanceModifierType, eventOnly.IDStringInstanceModifierOffset, i)
+			e.ShareCount = 1
+
+			s := *sdr
+			s.EventOnly = &e
+			out = append(out, &s)
+		}
+		return out
+	}
+
+	return []*SDR{sdr}
+}
+
+// sharedSensorName generates the ID String of the index-th sensor sharing a record.
+// modifierType 00b means numeric modifier, 01b means alpha modifier, in which the
+// alpha characters are considered to be base 26, eg: 0 is "A", 25 is "Z", 26 is "AA".
+func sharedSensorName(idString []byte, modifierType uint8, modifierOffset uint8, index uint8) []byte {
+	n := int(modifierOffset) + int(index)
+
+	var modifier string
+	switch modifierType {
+	case 0x01:
+		modifier = string(rune('A' + n%26))
+		for n = n / 26; n > 0; n = n / 26 {
+			n--
+			modifier = string(rune('A'+n%26)) + modifier
+		}
+	default:
+		modifier = strconv.Itoa(n)
+	}
+
+	out := make([]byte, 0, len(idString)+len(modifier))
+	out = append(out, idString...)
+	out = append(out, modifier...)
+	return out
+}
+
 // Determine if sensor has an analog reading
 func (sdr *SDR) HasAnalogReading() bool {
 
@@ -159,6 +244,40 @@ func (sdr *SDR) HasAnalogReading() bool {
 	return sdr.Full.HasAnalogReading()
 }
 
+// sdrOwnerKey identifies a sensor by the sensor owner and the sensor number.
+type sdrOwnerKey struct {
+	// GeneratorID holds the Sensor Owner ID and Sensor Owner LUN (with channel).
+	generatorID  GeneratorID
+	sensorNumber SensorNumber
+}
+
+// ownerKey returns the sdrOwnerKey of sensor records, ok is false for other records.
+func (sdr *SDR) ownerKey() (key sdrOwnerKey, ok bool) {
+	switch sdr.RecordHeader.RecordType {
+	case SDRRecordTypeFullSensor:
+		return sdrOwnerKey{sdr.Full.GeneratorID, sdr.Full.SensorNumber}, true
+	case SDRRecordTypeCompactSensor:
+		return sdrOwnerKey{sdr.Compact.GeneratorID, sdr.Compact.SensorNumber}, true
+	case SDRRecordTypeEventOnly:
+		return sdrOwnerKey{sdr.EventOnly.GeneratorID, sdr.EventOnly.SensorNumber}, true
+	}
+	return sdrOwnerKey{}, false
+}
+
+// sdrSource represents where the SDRs of the BMC are read from.
+type sdrSource uint8
+
+const (
+	sdrSourceUnknown sdrSource = iota
+	sdrSourceRepo              // SDR Repository, by Get SDR command
+	sdrSourceDevice            // Device SDRs, by Get Device SDR command
+)
+
+// Raw returns the raw record data (header and body) of the SDR.
+func (sdr *SDR) Raw() []byte {
+	return sdr.raw
+}
+
 // ParseSDR parses raw SDR record data to SDR struct.
 // This function is normally used after GetSDRResponse or GetDeviceSDRResponse to
 // interpret the raw SDR record data in the response.
@@ -179,6 +298,7 @@ func ParseSDR(data []byte, nextRecordID uint16) (*SDR, error) {
 	sdr := &SDR{
 		RecordHeader: sdrHeader,
 		NextRecordID: nextRecordID,
+		raw:          data,
 	}
 
 	switch sdrHeader.RecordType {
@@ -403,6 +523,24 @@ func (mask *Mask_Thresholds) IsThresholdReadable(thresholdType SensorThresholdTy
 	return false
 }
 
+func (mask *Mask_Thresholds) IsThresholdSettable(thresholdType SensorThresholdType) bool {
+	switch thresholdType {
+	case SensorThresholdType_LCR:
+		return mask.LCR.Settable
+	case SensorThresholdType_LNR:
+		return mask.LNR.Settable
+	case SensorThresholdType_LNC:
+		return mask.LNC.Settable
+	case SensorThresholdType_UCR:
+		return mask.UCR.Settable
+	case SensorThresholdType_UNC:
+		return mask.UNC.Settable
+	case SensorThresholdType_UNR:
+		return mask.UNR.Settable
+	}
+	return false
+}
+
 type Mask_DiscreteEvent struct {
 	State_0  bool
 	State_1  bool
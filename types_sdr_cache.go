@@ -0,0 +1,268 @@
+package ipmi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SDRCache stores the parsed SDR repository of BMCs, so the client can avoid
+// walking the whole SDR repository each time SDRs are needed.
+//
+// The cache entries are keyed by the device GUID of the BMC, and are validated
+// against the Most Recent Addition/Erase timestamps returned by GetSDRRepoInfo.
+// The client uses a SDRMemoryCache by default, see Client.WithSDRCache.
+type SDRCache interface {
+	// Get returns the cached entry for the key, ok is false if not found.
+	// The returned SDRs must not be shared with other callers, as the callers may modify them.
+	Get(key string) (entry *SDRCacheEntry, ok bool)
+	// Set stores the entry for the key.
+	Set(key string, entry *SDRCacheEntry) error
+}
+
+// SDRCacheEntry holds all SDRs of a SDR repository alongside with
+// the repository info at the time the SDRs were read.
+type SDRCacheEntry struct {
+	RecordCount            uint16
+	MostRecentAdditionTime time.Time
+	MostRecentEraseTime    time.Time
+
+	SDRs []*SDR
+}
+
+// Valid checks whether the cache entry is still up to date with the SDR repository.
+func (entry *SDRCacheEntry) Valid(repoInfo *GetSDRRepoInfoResponse) bool {
+	if entry == nil || repoInfo == nil {
+		return false
+	}
+	return entry.RecordCount == repoInfo.RecordCount &&
+		entry.MostRecentAdditionTime.Equal(repoInfo.MostRecentAddititionTime) &&
+		entry.MostRecentEraseTime.Equal(repoInfo.MostRecentEraseTime)
+}
+
+// SDRMemoryCache is an in-memory SDRCache, it is safe for concurrent use.
+//
+// Only the raw records are kept, the SDRs are parsed again on each Get,
+// so the SDRs returned to different callers never share memory.
+type SDRMemoryCache struct {
+	entries map[string]*sdrFileCacheEntry
+	l       sync.RWMutex
+}
+
+func NewSDRMemoryCache() *SDRMemoryCache {
+	return &SDRMemoryCache{
+		entries: make(map[string]*sdrFileCacheEntry),
+	}
+}
+
+func (cache *SDRMemoryCache) Get(key string) (*SDRCacheEntry, bool) {
+	cache.l.RLock()
+	defer cache.l.RUnlock()
+
+	rawEntry, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry, err := rawEntry.parse()
+	if err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (cache *SDRMemoryCache) Set(key string, entry *SDRCacheEntry) error {
+	cache.l.Lock()
+	defer cache.l.Unlock()
+
+	cache.entries[key] = newSDRFileCacheEntry(entry)
+	return nil
+}
+
+// SDRFileCache is a SDRCache which stores each entry as a JSON file
+// named by the key under the specified directory.
+type SDRFileCache struct {
+	dir string
+	l   sync.Mutex
+}
+
+func NewSDRFileCache(dir string) *SDRFileCache {
+	return &SDRFileCache{
+		dir: dir,
+	}
+}
+
+// sdrFileCacheEntry is the on-disk format of SDRCacheEntry.
+// The SDRs are stored as raw record data, and parsed again when loaded.
+type sdrFileCacheEntry struct {
+	RecordCount            uint16           `json:"record_count"`
+	MostRecentAdditionTime time.Time        `json:"most_recent_addition_time"`
+	MostRecentEraseTime    time.Time        `json:"most_recent_erase_time"`
+	Records                []sdrCacheRecord `json:"records"`
+}
+
+type sdrCacheRecord struct {
+	NextRecordID uint16 `json:"next_record_id"`
+	Data         []byte `json:"data"`
+}
+
+// newSDRFileCacheEntry copies the raw records of the SDRs of the entry.
+func newSDRFileCacheEntry(entry *SDRCacheEntry) *sdrFileCacheEntry {
+	fileEntry := &sdrFileCacheEntry{
+		RecordCount:            entry.RecordCount,
+		MostRecentAdditionTime: entry.MostRecentAdditionTime,
+		MostRecentEraseTime:    entry.MostRecentEraseTime,
+		Records:                make([]sdrCacheRecord, 0, len(entry.SDRs)),
+	}
+	for _, sdr := range entry.SDRs {
+		fileEntry.Records = append(fileEntry.Records, sdrCacheRecord{
+			NextRecordID: sdr.NextRecordID,
+			Data:         append([]byte{}, sdr.Raw()...),
+		})
+	}
+	return fileEntry
+}
+
+// parse parses the raw records to a new SDRCacheEntry.
+func (fileEntry *sdrFileCacheEntry) parse() (*SDRCacheEntry, error) {
+	entry := &SDRCacheEntry{
+		RecordCount:            fileEntry.RecordCount,
+		MostRecentAdditionTime: fileEntry.MostRecentAdditionTime,
+		MostRecentEraseTime:    fileEntry.MostRecentEraseTime,
+		SDRs:                   make([]*SDR, 0, len(fileEntry.Records)),
+	}
+	for _, record := range fileEntry.Records {
+		// ParseSDR keeps the data as the raw of the SDR, so pass a copy
+		sdr, err := ParseSDR(append([]byte{}, record.Data...), record.NextRecordID)
+		if err != nil {
+			return nil, fmt.Errorf("ParseSDR failed, err: %s", err)
+		}
+		entry.SDRs = append(entry.SDRs, sdr)
+	}
+	return entry, nil
+}
+
+func (cache *SDRFileCache) filename(key string) string {
+	return filepath.Join(cache.dir, key+".json")
+}
+
+func (cache *SDRFileCache) Get(key string) (*SDRCacheEntry, bool) {
+	cache.l.Lock()
+	defer cache.l.Unlock()
+
+	b, err := os.ReadFile(cache.filename(key))
+	if err != nil {
+		return nil, false
+	}
+
+	fileEntry := &sdrFileCacheEntry{}
+	if err := json.Unmarshal(b, fileEntry); err != nil {
+		return nil, false
+	}
+
+	entry, err := fileEntry.parse()
+	if err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (cache *SDRFileCache) Set(key string, entry *SDRCacheEntry) error {
+	cache.l.Lock()
+	defer cache.l.Unlock()
+
+	fileEntry := newSDRFileCacheEntry(entry)
+
+	b, err := json.Marshal(fileEntry)
+	if err != nil {
+		return fmt.Errorf("marshal sdr cache entry failed, err: %s", err)
+	}
+
+	if err := os.MkdirAll(cache.dir, 0755); err != nil {
+		return fmt.Errorf("create sdr cache dir failed, err: %s", err)
+	}
+
+	// write to a temp file first, then rename it, so readers never see a partial file.
+	filename := cache.filename(key)
+	tmpFilename := filename + ".tmp"
+	if err := os.WriteFile(tmpFilename, b, 0644); err != nil {
+		return fmt.Errorf("write sdr cache file failed, err: %s", err)
+	}
+	if err := os.Rename(tmpFilename, filename); err != nil {
+		return fmt.Errorf("rename sdr cache file failed, err: %s", err)
+	}
+	return nil
+}
+
+// cachedSDRs returns all SDRs of the SDR repository from the SDR cache of the client.
+// The SDR repository is walked again only if the cache entry is missing or out of date.
+func (c *Client) cachedSDRs() ([]*SDR, error) {
+	repoInfo, err := c.GetSDRRepoInfo()
+	if err != nil {
+		return nil, fmt.Errorf("GetSDRRepoInfo failed, err: %s", err)
+	}
+
+	key, err := c.getSDRCacheKey()
+	if err != nil {
+		return nil, fmt.Errorf("getSDRCacheKey failed, err: %s", err)
+	}
+
+	return c.lookupSDRCache(key, repoInfo, c.walkRepoSDRs)
+}
+
+// lookupSDRCache returns the SDRs of the cache entry for the key if it is valid for the repoInfo,
+// otherwise the SDRs are walked by walk and stored in the cache.
+func (c *Client) lookupSDRCache(key string, repoInfo *GetSDRRepoInfoResponse, walk func(fn func(sdr *SDR) bool) error) ([]*SDR, error) {
+	if entry, ok := c.sdrCache.Get(key); ok && entry.Valid(repoInfo) {
+		c.Debugf("Using cached SDRs for %s\n", key)
+		return entry.SDRs, nil
+	}
+
+	sdrs := make([]*SDR, 0)
+	err := walk(func(sdr *SDR) bool {
+		sdrs = append(sdrs, sdr)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &SDRCacheEntry{
+		RecordCount:            repoInfo.RecordCount,
+		MostRecentAdditionTime: repoInfo.MostRecentAddititionTime,
+		MostRecentEraseTime:    repoInfo.MostRecentEraseTime,
+		SDRs:                   sdrs,
+	}
+	if err := c.sdrCache.Set(key, entry); err != nil {
+		// failing to update the cache should not fail the caller
+		c.Debug("Set SDR cache failed", err)
+	}
+
+	return sdrs, nil
+}
+
+// getSDRCacheKey returns the key used to identify the BMC in SDR cache.
+// The device GUID is used, and the system GUID as a fallback for
+// the BMCs not supporting Get Device GUID command.
+func (c *Client) getSDRCacheKey() (string, error) {
+	if c.sdrCacheKey != "" {
+		return c.sdrCacheKey, nil
+	}
+
+	var guid [16]byte
+	deviceGUIDRes, err := c.GetDeviceGUID()
+	if err != nil {
+		systemGUIDRes, err2 := c.GetSystemGUID()
+		if err2 != nil {
+			return "", fmt.Errorf("GetDeviceGUID failed, err: %s, GetSystemGUID failed, err: %s", err, err2)
+		}
+		guid = systemGUIDRes.GUID
+	} else {
+		guid = deviceGUIDRes.GUID
+	}
+
+	c.sdrCacheKey = fmt.Sprintf("%x", guid[:])
+	return c.sdrCacheKey, nil
+}
@@ -0,0 +1,106 @@
+package ipmi
+
+import (
+	"testing"
+	"time"
+)
+
+// testCompactSDR returns the raw data of a Compact Sensor record with the sensor name.
+func testCompactSDR(recordID uint16, sensorNumber uint8, name string) []byte {
+	data := make([]byte, 32+len(name))
+	packUint16L(recordID, data, 0)
+	packUint8(0x51, data, 2)
+	packUint8(uint8(SDRRecordTypeCompactSensor), data, 3)
+	packUint8(uint8(len(data)-5), data, 4)
+	packUint8(BMC_SA, data, 5)
+	packUint8(sensorNumber, data, 7)
+	packUint8(0xc0|uint8(len(name)), data, 31)
+	copy(data[32:], name)
+	return data
+}
+
+func Test_lookupSDRCache(t *testing.T) {
+	caches := map[string]SDRCache{
+		"memory": NewSDRMemoryCache(),
+		"file":   NewSDRFileCache(t.TempDir()),
+	}
+
+	for name, cache := range caches {
+		c := &Client{sdrCache: cache}
+
+		records := [][]byte{
+			testCompactSDR(1, 0x01, "Temp"),
+			testCompactSDR(2, 0x02, "FAN1"),
+		}
+		var walks int
+		walk := func(fn func(sdr *SDR) bool) error {
+			walks++
+			for i, data := range records {
+				sdr, err := ParseSDR(append([]byte{}, data...), uint16(i+2))
+				if err != nil {
+					return err
+				}
+				if fn(sdr) {
+					break
+				}
+			}
+			return nil
+		}
+
+		repoInfo := &GetSDRRepoInfoResponse{
+			RecordCount:              2,
+			MostRecentAddititionTime: time.Unix(1000, 0),
+			MostRecentEraseTime:      time.Unix(500, 0),
+		}
+
+		sdrs, err := c.lookupSDRCache("guid", repoInfo, walk)
+		if err != nil {
+			t.Fatalf("%s: lookupSDRCache failed, err: %s", name, err)
+		}
+		if walks != 1 || len(sdrs) != 2 {
+			t.Errorf("%s: miss not matched, got walks: %d, sdrs: %d, expected: 1, 2", name, walks, len(sdrs))
+		}
+
+		// the SDRs returned to a caller are modified by enhanceSDR
+		sdrs[0].Compact.SensorValue = 42
+		sdrs[0].Compact.SensorStatus = "ucr"
+
+		sdrs, err = c.lookupSDRCache("guid", repoInfo, walk)
+		if err != nil {
+			t.Fatalf("%s: lookupSDRCache failed, err: %s", name, err)
+		}
+		if walks != 1 || len(sdrs) != 2 {
+			t.Errorf("%s: hit not matched, got walks: %d, sdrs: %d, expected: 1, 2", name, walks, len(sdrs))
+		}
+		if sdrs[0].Compact.SensorValue != 0 || sdrs[0].Compact.SensorStatus != "" {
+			t.Errorf("%s: cached SDR is shared with the previous caller", name)
+		}
+		if sdrs[1].SensorName() != "FAN1" || sdrs[1].NextRecordID != 3 {
+			t.Errorf("%s: cached SDR not matched, got: %s (next %d), expected: FAN1 (next 3)", name, sdrs[1].SensorName(), sdrs[1].NextRecordID)
+		}
+
+		// a record is added to the SDR Repository
+		records = append(records, testCompactSDR(3, 0x03, "PSU1"))
+		repoInfo = &GetSDRRepoInfoResponse{
+			RecordCount:              3,
+			MostRecentAddititionTime: time.Unix(2000, 0),
+			MostRecentEraseTime:      time.Unix(500, 0),
+		}
+		sdrs, err = c.lookupSDRCache("guid", repoInfo, walk)
+		if err != nil {
+			t.Fatalf("%s: lookupSDRCache failed, err: %s", name, err)
+		}
+		if walks != 2 || len(sdrs) != 3 {
+			t.Errorf("%s: invalidation not matched, got walks: %d, sdrs: %d, expected: 2, 3", name, walks, len(sdrs))
+		}
+
+		// the erase timestamp changed only
+		repoInfo.MostRecentEraseTime = time.Unix(2500, 0)
+		if _, err := c.lookupSDRCache("guid", repoInfo, walk); err != nil {
+			t.Fatalf("%s: lookupSDRCache failed, err: %s", name, err)
+		}
+		if walks != 3 {
+			t.Errorf("%s: erase invalidation not matched, got walks: %d, expected: 3", name, walks)
+		}
+	}
+}
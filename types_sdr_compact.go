@@ -55,8 +55,24 @@ type SDRCompact struct {
 	// 11b = reserved
 	SensorDirection uint8
 
+	// ID String Instance Modifier Type
+	// 00b = numeric
+	// 01b = alpha
+	IDStringInstanceModifierType uint8
+
+	// Share count (number of sensors sharing this record). Sensor numbers sharing this
+	// record are sequential starting with the sensor number specified by the Sensor
+	// Number field for this record. 0h and 1h both mean the record is not shared.
+	ShareCount uint8
+
+	// 0 = Entity Instance same for all shared records
+	// 1 = Entity Instance increments for each shared record
 	EntityInstanceSharing uint8
 
+	// The offset added to the sensor index within the shared sensors, to generate the
+	// instance modifier appended to the ID String, see SDREventOnly.IDStringInstanceModifierOffset.
+	IDStringInstanceModifierOffset uint8
+
 	// Positive hysteresis is defined as the unsigned number of counts that are
 	// subtracted from the raw threshold values to create the "re-arm" point for all
 	// positive-going thresholds on the sensor. 0 indicates that there is no hysteresis on
@@ -242,6 +258,17 @@ func parseSDRCompactSensor(data []byte, sdr *SDR) error {
 		ModifierUnit:     SensorUnitType(b22),
 	}
 
+	b23, _, _ := unpackUint8(data, 23)
+	s.SensorDirection = (b23 & 0xc0) >> 6
+	s.IDStringInstanceModifierType = (b23 & 0x30) >> 4
+	s.ShareCount = b23 & 0x0f
+
+	b24, _, _ := unpackUint8(data, 24)
+	if isBit7Set(b24) {
+		s.EntityInstanceSharing = 1
+	}
+	s.IDStringInstanceModifierOffset = b24 & 0x7f
+
 	s.PositiveHysteresisRaw, _, _ = unpackUint8(data, 25)
 	s.NegativeHysteresisRaw, _, _ = unpackUint8(data, 26)
 
@@ -95,6 +95,15 @@ func parseSDREventOnly(data []byte, sdr *SDR) error {
 	eventReadingType, _, _ := unpackUint8(data, 11)
 	s.SensorEventReadingType = EventReadingType(eventReadingType)
 
+	b12, _, _ := unpackUint8(data, 12)
+	s.SensorDirection = (b12 & 0xc0) >> 6
+	s.IDStringInstanceModifierType = (b12 & 0x30) >> 4
+	s.ShareCount = b12 & 0x0f
+
+	b13, _, _ := unpackUint8(data, 13)
+	s.EntityInstanceSharing = isBit7Set(b13)
+	s.IDStringInstanceModifierOffset = b13 & 0x7f
+
 	typeLength, _, _ := unpackUint8(data, 16)
 	s.IDStringTypeLength = TypeLength(typeLength)
 
@@ -783,9 +792,9 @@ func (tl TypeLength) Size() uint8 {
 		/* hex dump or BCD -> 2x length */
 		size = l * 2
 	case 2: /* 10b: 6-bit ASCII packed */
-		// three bytes packs four chars
-		/* 4 chars per group of 1-3 bytes, round up to 4 bytes boundary */
-		size = (l/3 + 1) * 4
+		// three bytes packs four chars, a trailing partial group
+		// of one or two bytes packs one or two chars.
+		size = uint8(uint16(l) * 8 / 6)
 	case 3: /* 11b: 8-bit ASCII + Latin 1 */
 		/* no length adjustment */
 		size = l
@@ -831,30 +840,15 @@ func (tl TypeLength) Chars(raw []byte) (chars []byte, err error) {
 			'@', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
 			'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', '[', '\\', ']', '^', '_',
 		}
-		// every 3 bytes packs 4 chars.
-
-		// i holds index for raw
-		// j holds index for target data
-		for i, j := 0, 0; i < int(tl.Length()); i += 3 {
-			c1 := raw[i]
-			c2 := raw[i+1]
-			c3 := raw[i+2]
-
-			for k := 0; k < 4; k++ {
-				var idx byte
-				switch k {
-				case 0:
-					idx = c1 & 0x3f
-				case 1:
-					idx = (c1&0xc0)>>6 | (c2&0x0f)<<2
-				case 2:
-					idx = (c2&0xf0)>>4 | (c3&0x03)<<4
-				case 3:
-					idx = (c3 & 0xfc) >> 2
-				}
-				chars[j] = ascci6bit[idx]
-				j++
+		// every 3 bytes packs 4 chars, the chars are packed
+		// from the least significant bit of the first byte.
+		for j := 0; j < size; j++ {
+			bit := j * 6
+			v := uint16(raw[bit/8])
+			if bit/8+1 < len(raw) {
+				v |= uint16(raw[bit/8+1]) << 8
 			}
+			chars[j] = ascci6bit[(v>>(bit%8))&0x3f]
 		}
 
 	case 3: // 11b - 8-bit ASCII
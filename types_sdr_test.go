@@ -0,0 +1,207 @@
+package ipmi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_sharedSensorName(t *testing.T) {
+	// see: 43.3 SDR Type 03h, Event-Only Record, ID String Instance Modifier Offset
+	tests := []struct {
+		name           string
+		idString       string
+		modifierType   uint8
+		modifierOffset uint8
+		index          uint8
+		expected       string
+	}{
+		{"numeric1", "Temp ", 0x00, 5, 0, "Temp 5"},
+		{"numeric2", "Temp ", 0x00, 5, 2, "Temp 7"},
+		{"numeric3", "DIMM", 0x00, 1, 15, "DIMM16"},
+		{"alpha1", "Temp ", 0x01, 0, 0, "Temp A"},
+		{"alpha2", "Temp ", 0x01, 0, 25, "Temp Z"},
+		{"alpha3", "Temp ", 0x01, 26, 0, "Temp AA"},
+		{"alpha4", "Temp ", 0x01, 26, 2, "Temp AC"},
+		{"alpha5", "Temp ", 0x01, 51, 1, "Temp BA"},
+	}
+
+	for _, test := range tests {
+		got := string(sharedSensorName([]byte(test.idString), test.modifierType, test.modifierOffset, test.index))
+		if got != test.expected {
+			t.Errorf("test %s not matched, got: %s, expected: %s", test.name, got, test.expected)
+		}
+	}
+}
+
+func Test_SDR_ExpandShared(t *testing.T) {
+	newCompact := func(shareCount uint8, instanceSharing bool) *SDR {
+		compact := &SDRCompact{
+			GeneratorID:                    GeneratorID(BMC_SA),
+			SensorNumber:                   0x10,
+			SensorEntityInstance:           0x01,
+			SensorEventReadingType:         EventReadingTypeSensorSpecific,
+			IDStringBytes:                  []byte("DIMM"),
+			IDStringInstanceModifierOffset: 1,
+			ShareCount:                     shareCount,
+		}
+		if instanceSharing {
+			compact.EntityInstanceSharing = 1
+		}
+		return &SDR{RecordHeader: &SDRHeader{RecordType: SDRRecordTypeCompactSensor}, Compact: compact}
+	}
+	newEventOnly := func(shareCount uint8, instanceSharing bool) *SDR {
+		return &SDR{
+			RecordHeader: &SDRHeader{RecordType: SDRRecordTypeEventOnly},
+			EventOnly: &SDREventOnly{
+				GeneratorID:                    GeneratorID(BMC_SA),
+				SensorNumber:                   0x20,
+				SensorEntityInstance:           0x05,
+				IDStringBytes:                  []byte("PSU"),
+				IDStringInstanceModifierType:   0x01,
+				IDStringInstanceModifierOffset: 0,
+				ShareCount:                     shareCount,
+				EntityInstanceSharing:          instanceSharing,
+			},
+		}
+	}
+
+	type expanded struct {
+		number     SensorNumber
+		instance   EntityInstance
+		name       string
+		shareCount uint8
+	}
+	get := func(sdr *SDR) expanded {
+		if sdr.Compact != nil {
+			return expanded{sdr.Compact.SensorNumber, sdr.Compact.SensorEntityInstance, sdr.SensorName(), sdr.Compact.ShareCount}
+		}
+		return expanded{sdr.EventOnly.SensorNumber, sdr.EventOnly.SensorEntityInstance, sdr.SensorName(), sdr.EventOnly.ShareCount}
+	}
+
+	tests := []struct {
+		name     string
+		sdr      *SDR
+		expected []expanded
+	}{
+		{
+			name:     "compact not shared",
+			sdr:      newCompact(0, false),
+			expected: []expanded{{0x10, 0x01, "DIMM", 0}},
+		},
+		{
+			name: "compact with entity instance sharing",
+			sdr:  newCompact(3, true),
+			expected: []expanded{
+				{0x10, 0x01, "DIMM1", 1},
+				{0x11, 0x02, "DIMM2", 1},
+				{0x12, 0x03, "DIMM3", 1},
+			},
+		},
+		{
+			name: "compact without entity instance sharing",
+			sdr:  newCompact(2, false),
+			expected: []expanded{
+				{0x10, 0x01, "DIMM1", 1},
+				{0x11, 0x01, "DIMM2", 1},
+			},
+		},
+		{
+			name:     "event-only not shared",
+			sdr:      newEventOnly(1, true),
+			expected: []expanded{{0x20, 0x05, "PSU", 1}},
+		},
+		{
+			name: "event-only with entity instance sharing",
+			sdr:  newEventOnly(2, true),
+			expected: []expanded{
+				{0x20, 0x05, "PSUA", 1},
+				{0x21, 0x06, "PSUB", 1},
+			},
+		},
+		{
+			name: "event-only without entity instance sharing",
+			sdr:  newEventOnly(2, false),
+			expected: []expanded{
+				{0x20, 0x05, "PSUA", 1},
+				{0x21, 0x05, "PSUB", 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		origin := get(test.sdr)
+		sdrs := test.sdr.ExpandShared()
+
+		got := make([]expanded, 0, len(sdrs))
+		for _, sdr := range sdrs {
+			got = append(got, get(sdr))
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("test %s not matched, got: %+v, expected: %+v", test.name, got, test.expected)
+		}
+		if get(test.sdr) != origin {
+			t.Errorf("test %s the shared record is changed, got: %+v, expected: %+v", test.name, get(test.sdr), origin)
+		}
+	}
+}
+
+func Test_GetSDRsMap_Shared(t *testing.T) {
+	compact := &SDR{
+		RecordHeader: &SDRHeader{RecordType: SDRRecordTypeCompactSensor},
+		Compact: &SDRCompact{
+			GeneratorID:            GeneratorID(BMC_SA),
+			SensorNumber:           0x10,
+			SensorEventReadingType: EventReadingTypeSensorSpecific,
+			IDStringBytes:          []byte("DIMM"),
+			ShareCount:             3,
+		},
+	}
+	eventOnly := &SDR{
+		RecordHeader: &SDRHeader{RecordType: SDRRecordTypeEventOnly},
+		EventOnly: &SDREventOnly{
+			GeneratorID:                  GeneratorID(BMC_SA),
+			SensorNumber:                 0x20,
+			IDStringBytes:                []byte("PSU"),
+			IDStringInstanceModifierType: 0x01,
+			ShareCount:                   2,
+		},
+	}
+
+	c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+		switch req.Cmd {
+		case CommandGetSensorReading.ID:
+			return 0x00, []byte{0x00, 0xc0, 0x00, 0x00}
+		case CommandGetSensorEventStatus.ID:
+			return 0x00, []byte{0xc0, 0x00, 0x00, 0x00, 0x00}
+		}
+		return 0xc1, nil
+	})
+	c.sdrFile = "test"
+	c.sdrFileSDRs = []*SDR{compact, eventOnly}
+
+	sdrMap, err := c.GetSDRsMap()
+	if err != nil {
+		t.Fatalf("GetSDRsMap failed, err: %s", err)
+	}
+	expectedSDRs := map[SensorNumber]string{0x10: "DIMM0", 0x11: "DIMM1", 0x12: "DIMM2", 0x20: "PSUA", 0x21: "PSUB"}
+	got := make(map[SensorNumber]string)
+	for number, sdr := range sdrMap[GeneratorID(BMC_SA)] {
+		got[number] = sdr.SensorName()
+	}
+	if !reflect.DeepEqual(got, expectedSDRs) {
+		t.Errorf("test sdr map not matched, got: %v, expected: %v", got, expectedSDRs)
+	}
+
+	sensors, err := c.GetSensors()
+	if err != nil {
+		t.Fatalf("GetSensors failed, err: %s", err)
+	}
+	expectedSensors := []string{"DIMM0", "DIMM1", "DIMM2"}
+	gotSensors := make([]string, 0)
+	for _, sensor := range sensors {
+		gotSensors = append(gotSensors, sensor.Name)
+	}
+	if !reflect.DeepEqual(gotSensors, expectedSensors) {
+		t.Errorf("test sensors not matched, got: %v, expected: %v", gotSensors, expectedSensors)
+	}
+}
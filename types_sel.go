@@ -3,6 +3,8 @@ package ipmi
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
@@ -150,6 +152,74 @@ func (sel *SELStandard) EventSeverity() EventSeverity {
 	return sel.EventReadingType.EventSeverity(sel.SensorType, sel.SensorNumber, sel.EventData, sel.EventDir)
 }
 
+// ThresholdEventValues returns the trigger reading (event data 2) and the trigger threshold value (event data 3)
+// of the threshold event, converted to engineering units by the Full SDR of the sensor.
+// The reading or threshold is nil if it is not present in the event data.
+// ok is false if the event is not a threshold event or the values can not be converted.
+func (sel *SELStandard) ThresholdEventValues(sdr *SDR) (reading *float64, threshold *float64, ok bool) {
+	if !sel.EventReadingType.IsThreshold() {
+		return nil, nil, false
+	}
+	if sdr == nil || sdr.Full == nil || !sdr.Full.HasAnalogReading() {
+		return nil, nil, false
+	}
+
+	// 29.7 Event Data 1
+	// [7:6] 01b = trigger reading in byte 2
+	// [5:4] 01b = trigger threshold value in byte 3
+	ed1 := sel.EventData.EventData1
+	if (ed1>>6)&0x03 == 0x01 {
+		v := sdr.Full.ConvertReading(sel.EventData.EventData2)
+		reading = &v
+	}
+	if (ed1>>4)&0x03 == 0x01 {
+		v := sdr.Full.ConvertReading(sel.EventData.EventData3)
+		threshold = &v
+	}
+	if reading == nil && threshold == nil {
+		return nil, nil, false
+	}
+	return reading, threshold, true
+}
+
+// ThresholdEventValuesString returns the trigger reading and threshold of the threshold event
+// in engineering units, like "Reading 95 degrees C > Threshold 90 degrees C".
+// It returns empty string if the values are not available, see ThresholdEventValues.
+func (sel *SELStandard) ThresholdEventValuesString(sdr *SDR) string {
+	reading, threshold, ok := sel.ThresholdEventValues(sdr)
+	if !ok {
+		return ""
+	}
+	unit := sdr.Full.SensorUnit
+
+	var readingStr, thresholdStr string
+	if reading != nil {
+		readingStr = fmt.Sprintf("Reading %s %s", formatEventValue(*reading), unit)
+	}
+	if threshold != nil {
+		thresholdStr = fmt.Sprintf("Threshold %s %s", formatEventValue(*threshold), unit)
+	}
+
+	switch {
+	case reading == nil:
+		return thresholdStr
+	case threshold == nil:
+		return readingStr
+	}
+
+	// the odd offsets are "going high" events, the even ones are "going low" events.
+	op := "<"
+	if sel.EventData.EventReadingOffset()%2 == 1 {
+		op = ">"
+	}
+	return fmt.Sprintf("%s %s %s", readingStr, op, thresholdStr)
+}
+
+// formatEventValue formats the converted value with at most 3 decimal places.
+func formatEventValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
 func parseSELDefault(msg []byte, sel *SEL) error {
 	if len(msg) < 16 {
 		return ErrUnpackedDataTooShort
@@ -216,7 +286,7 @@ func parseSELOEMNonTimestamped(msg []byte, sel *SEL) error {
 
 	s.OEM = [13]byte{}
 	b, _, _ := unpackBytes(msg, 3, 13)
-	for i := 0; i < 6; i++ {
+	for i := 0; i < 13; i++ {
 		s.OEM[i] = b[i]
 	}
 
@@ -228,6 +298,12 @@ func parseSELOEMNonTimestamped(msg []byte, sel *SEL) error {
 // it will also print sensor number, entity id and instance, and asserted discrete states.
 // sdrMap can be get by client GetSDRsMap method.
 func FormatSELs(records []*SEL, sdrMap SDRMapBySensorNumber) string {
+	return FormatSELsFor(records, sdrMap, 0)
+}
+
+// FormatSELsFor is like FormatSELs, the bmcManufacturerID (see GetDeviceID) is used to
+// decode the non-timestamped OEM records, see SEL.OEMDescription.
+func FormatSELsFor(records []*SEL, sdrMap SDRMapBySensorNumber, bmcManufacturerID OEM) string {
 	var elistMode bool
 	if sdrMap != nil {
 		elistMode = true
@@ -254,7 +330,7 @@ func FormatSELs(records []*SEL, sdrMap SDRMapBySensorNumber) string {
 		"EventData",
 	}
 	if elistMode {
-		headers = append(headers, "SensorName")
+		headers = append(headers, "SensorName", "EventValues")
 	}
 
 	table.SetHeader(headers)
@@ -286,19 +362,66 @@ func FormatSELs(records []*SEL, sdrMap SDRMapBySensorNumber) string {
 
 			if elistMode {
 				var sensorName string
+				var eventValues string
 				sdr, ok := sdrMap[s.GeneratorID][s.SensorNumber]
 				if !ok {
 					sensorName = fmt.Sprintf("N/A %#04x, %#02x", s.GeneratorID, s.SensorNumber)
 				} else {
 					sensorName = sdr.SensorName()
+					eventValues = s.ThresholdEventValuesString(sdr)
 				}
-				content = append(content, sensorName)
+				content = append(content, sensorName, eventValues)
 			}
 
 			table.Append(content)
 
 		case SELRecordTypeRangeTimestampedOEM:
+			s := sel.OEMTimestamped
+
+			content := []string{
+				fmt.Sprintf("%#04x", sel.RecordID),
+				sel.RecordType.String(),
+				"",
+				fmt.Sprintf("%v", s.Timestamp),
+				"",
+				"",
+				"",
+				"",
+				"",
+				"",
+				sel.OEMDescription(bmcManufacturerID),
+				"",
+				"",
+				fmt.Sprintf("%x", s.OEMDefined),
+			}
+			if elistMode {
+				content = append(content, "", "")
+			}
+			table.Append(content)
+
 		case SELRecordTypeRangeNonTimestampedOEM:
+			s := sel.OEMNonTimestamped
+
+			content := []string{
+				fmt.Sprintf("%#04x", sel.RecordID),
+				sel.RecordType.String(),
+				"",
+				"",
+				"",
+				"",
+				"",
+				"",
+				"",
+				"",
+				sel.OEMDescription(bmcManufacturerID),
+				"",
+				"",
+				fmt.Sprintf("%x", s.OEM),
+			}
+			if elistMode {
+				content = append(content, "", "")
+			}
+			table.Append(content)
 		}
 	}
 
@@ -0,0 +1,338 @@
+package ipmi
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SELExportFormat is the format used by ExportSELs.
+type SELExportFormat string
+
+const (
+	// JSON lines, one JSON object (SELExportRecord) per line.
+	SELExportFormatJSON SELExportFormat = "json"
+	// CSV with a header line, the columns are the fields of SELExportRecord.
+	SELExportFormatCSV SELExportFormat = "csv"
+	// RFC5424 syslog messages, one message per line.
+	SELExportFormatSyslog SELExportFormat = "syslog"
+	// Pipe-delimited text, compatible with the output of "ipmitool sel elist".
+	SELExportFormatIPMITool SELExportFormat = "ipmitool"
+)
+
+// SELExportRecord holds the decoded fields of a SEL record, used by the exporters.
+// TriggerReading and TriggerThreshold are set for threshold events if the SDR of the sensor is available.
+type SELExportRecord struct {
+	RecordID         uint16     `json:"record_id"`
+	RecordType       string     `json:"record_type"`
+	RecordTypeCode   uint8      `json:"record_type_code"`
+	Timestamp        *time.Time `json:"timestamp,omitempty"`
+	GeneratorID      uint16     `json:"generator_id"`
+	SensorNumber     uint8      `json:"sensor_number"`
+	SensorType       string     `json:"sensor_type,omitempty"`
+	SensorName       string     `json:"sensor_name,omitempty"`
+	EventReadingType string     `json:"event_reading_type,omitempty"`
+	EventDir         string     `json:"event_dir,omitempty"`
+	Event            string     `json:"event,omitempty"`
+	Severity         string     `json:"severity,omitempty"`
+	EventData        string     `json:"event_data,omitempty"`
+	TriggerReading   *float64   `json:"trigger_reading,omitempty"`
+	TriggerThreshold *float64   `json:"trigger_threshold,omitempty"`
+	Unit             string     `json:"unit,omitempty"`
+	EventValues      string     `json:"event_values,omitempty"`
+	ManufacturerID   uint32     `json:"manufacturer_id,omitempty"`
+	Manufacturer     string     `json:"manufacturer,omitempty"`
+	OEMData          string     `json:"oem_data,omitempty"`
+}
+
+// NewSELExportRecord decodes the SEL record.
+// The sdrMap is optional, it is used to find the sensor name.
+// The bmcManufacturerID is the manufacturer of the BMC (see GetDeviceID), used to decode
+// the non-timestamped OEM records, pass 0 if unknown.
+func NewSELExportRecord(sel *SEL, sdrMap SDRMapBySensorNumber, bmcManufacturerID OEM) *SELExportRecord {
+	r := &SELExportRecord{
+		RecordID:       sel.RecordID,
+		RecordType:     sel.RecordType.String(),
+		RecordTypeCode: uint8(sel.RecordType),
+	}
+
+	switch sel.RecordType.Range() {
+	case SELRecordTypeRangeStandard:
+		s := sel.Standard
+		timestamp := s.Timestamp
+		r.Timestamp = &timestamp
+		r.GeneratorID = uint16(s.GeneratorID)
+		r.SensorNumber = uint8(s.SensorNumber)
+		r.SensorType = s.SensorType.String()
+		r.EventReadingType = s.EventReadingType.String()
+		r.EventDir = s.EventDir.String()
+		r.Event = s.EventString()
+		r.Severity = string(s.EventSeverity())
+		r.EventData = s.EventData.String()
+		if sdr, ok := sdrMap[s.GeneratorID][s.SensorNumber]; ok {
+			r.SensorName = sdr.SensorName()
+			if reading, threshold, ok := s.ThresholdEventValues(sdr); ok {
+				r.TriggerReading = reading
+				r.TriggerThreshold = threshold
+				r.Unit = sdr.Full.SensorUnit.String()
+				r.EventValues = s.ThresholdEventValuesString(sdr)
+			}
+		}
+
+	case SELRecordTypeRangeTimestampedOEM:
+		s := sel.OEMTimestamped
+		timestamp := s.Timestamp
+		r.Timestamp = &timestamp
+		r.ManufacturerID = s.ManufacturerID
+		r.Manufacturer = OEM(s.ManufacturerID).String()
+		r.Event = sel.OEMDescription(bmcManufacturerID)
+		r.OEMData = hex.EncodeToString(s.OEMDefined[:])
+
+	case SELRecordTypeRangeNonTimestampedOEM:
+		r.Event = sel.OEMDescription(bmcManufacturerID)
+		r.OEMData = hex.EncodeToString(sel.OEMNonTimestamped.OEM[:])
+	}
+
+	return r
+}
+
+// ExportSELs writes the SEL records to w in the format.
+// The sdrMap is optional, it is used to find the sensor names, see GetSDRsMap.
+// The bmcManufacturerID is used to decode the non-timestamped OEM records, see NewSELExportRecord.
+func ExportSELs(w io.Writer, records []*SEL, sdrMap SDRMapBySensorNumber, bmcManufacturerID OEM, format SELExportFormat) error {
+	switch format {
+	case SELExportFormatJSON:
+		return ExportSELsJSON(w, records, sdrMap, bmcManufacturerID)
+	case SELExportFormatCSV:
+		return ExportSELsCSV(w, records, sdrMap, bmcManufacturerID)
+	case SELExportFormatSyslog:
+		return ExportSELsSyslog(w, records, sdrMap, bmcManufacturerID, SELSyslogOptions{})
+	case SELExportFormatIPMITool:
+		return ExportSELsIPMITool(w, records, sdrMap, bmcManufacturerID)
+	}
+	return fmt.Errorf("unknown SEL export format (%s)", format)
+}
+
+// ExportSELsJSON writes the SEL records as JSON lines.
+func ExportSELsJSON(w io.Writer, records []*SEL, sdrMap SDRMapBySensorNumber, bmcManufacturerID OEM) error {
+	encoder := json.NewEncoder(w)
+	for _, sel := range records {
+		if err := encoder.Encode(NewSELExportRecord(sel, sdrMap, bmcManufacturerID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportSELsCSV writes the SEL records as CSV with a header line.
+func ExportSELsCSV(w io.Writer, records []*SEL, sdrMap SDRMapBySensorNumber, bmcManufacturerID OEM) error {
+	writer := csv.NewWriter(w)
+
+	headers := []string{
+		"record_id",
+		"record_type",
+		"record_type_code",
+		"timestamp",
+		"generator_id",
+		"sensor_number",
+		"sensor_type",
+		"sensor_name",
+		"event_reading_type",
+		"event_dir",
+		"event",
+		"severity",
+		"event_data",
+		"trigger_reading",
+		"trigger_threshold",
+		"unit",
+		"event_values",
+		"manufacturer_id",
+		"manufacturer",
+		"oem_data",
+	}
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+
+	for _, sel := range records {
+		r := NewSELExportRecord(sel, sdrMap, bmcManufacturerID)
+
+		var timestamp string
+		if r.Timestamp != nil {
+			timestamp = r.Timestamp.Format(time.RFC3339)
+		}
+
+		row := []string{
+			fmt.Sprintf("%#04x", r.RecordID),
+			r.RecordType,
+			fmt.Sprintf("%#02x", r.RecordTypeCode),
+			timestamp,
+			fmt.Sprintf("%#04x", r.GeneratorID),
+			fmt.Sprintf("%#02x", r.SensorNumber),
+			r.SensorType,
+			r.SensorName,
+			r.EventReadingType,
+			r.EventDir,
+			r.Event,
+			r.Severity,
+			r.EventData,
+			formatOptionalFloat(r.TriggerReading),
+			formatOptionalFloat(r.TriggerThreshold),
+			r.Unit,
+			r.EventValues,
+			fmt.Sprintf("%d", r.ManufacturerID),
+			r.Manufacturer,
+			r.OEMData,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatOptionalFloat formats the value like JSON does, or returns empty string if it is nil.
+func formatOptionalFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// SELSyslogOptions is the options of ExportSELsSyslog.
+type SELSyslogOptions struct {
+	// Facility is the syslog facility code, defaults to 16 (local0) if zero.
+	Facility uint8
+	// Hostname defaults to "-" (nil value) if empty.
+	Hostname string
+	// AppName defaults to "ipmi-sel" if empty.
+	AppName string
+}
+
+// syslogSeverity maps EventSeverity to the syslog severity (RFC5424 6.2.1).
+func syslogSeverity(severity EventSeverity) uint8 {
+	switch severity {
+	case EventSeverityCritical:
+		return 2 // Critical
+	case EventSeverityNonFatal:
+		return 3 // Error
+	case EventSeverityWarning, EventSeverityDegraded:
+		return 4 // Warning
+	case EventSeverityOK, EventSeverityInfo:
+		return 6 // Informational
+	}
+	return 5 // Notice
+}
+
+// ExportSELsSyslog writes the SEL records as RFC5424 syslog messages, one message per line.
+// The syslog severity is mapped from the EventSeverity of the record.
+func ExportSELsSyslog(w io.Writer, records []*SEL, sdrMap SDRMapBySensorNumber, bmcManufacturerID OEM, options SELSyslogOptions) error {
+	facility := options.Facility
+	if facility == 0 {
+		facility = 16
+	}
+	hostname := options.Hostname
+	if hostname == "" {
+		hostname = "-"
+	}
+	appName := options.AppName
+	if appName == "" {
+		appName = "ipmi-sel"
+	}
+
+	for _, sel := range records {
+		r := NewSELExportRecord(sel, sdrMap, bmcManufacturerID)
+
+		timestamp := "-"
+		if r.Timestamp != nil {
+			timestamp = r.Timestamp.Format(time.RFC3339)
+		}
+
+		pri := int(facility)*8 + int(syslogSeverity(EventSeverity(r.Severity)))
+
+		// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+		line := fmt.Sprintf("<%d>1 %s %s %s - SEL - %s\n", pri, timestamp, hostname, appName, r.message())
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// message returns the one line description of the record.
+func (r *SELExportRecord) message() string {
+	if r.OEMData != "" {
+		return fmt.Sprintf("record %#04x: %s", r.RecordID, r.Event)
+	}
+
+	msg := fmt.Sprintf("record %#04x: %s %s: %s %s, severity %s",
+		r.RecordID, r.SensorType, r.sensorName(), r.Event, r.EventDir, r.Severity)
+	if r.EventValues != "" {
+		msg += ", " + r.EventValues
+	}
+	return msg
+}
+
+// sensorName returns the sensor name, or the sensor number if the name is unknown (like ipmitool).
+func (r *SELExportRecord) sensorName() string {
+	if r.SensorName != "" {
+		return r.SensorName
+	}
+	return fmt.Sprintf("#%#02x", r.SensorNumber)
+}
+
+// ExportSELsIPMITool writes the SEL records in the pipe-delimited format of "ipmitool sel elist".
+func ExportSELsIPMITool(w io.Writer, records []*SEL, sdrMap SDRMapBySensorNumber, bmcManufacturerID OEM) error {
+	for _, sel := range records {
+		if _, err := io.WriteString(w, FormatSELIPMITool(sel, sdrMap, bmcManufacturerID)+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatSELIPMITool formats the SEL record like "ipmitool sel elist", e.g.
+//
+//	1a | 11/04/2020 | 03:50:46 | Power Supply PSU1 Status | Presence detected | Asserted
+func FormatSELIPMITool(sel *SEL, sdrMap SDRMapBySensorNumber, bmcManufacturerID OEM) string {
+	r := NewSELExportRecord(sel, sdrMap, bmcManufacturerID)
+
+	fields := []string{fmt.Sprintf("%4x", r.RecordID)}
+	if r.Timestamp != nil {
+		fields = append(fields, r.Timestamp.Format("01/02/2006"), r.Timestamp.Format("15:04:05"))
+	}
+
+	if r.OEMData != "" {
+		if r.ManufacturerID != 0 {
+			fields = append(fields, fmt.Sprintf("OEM record %02x", r.RecordTypeCode), fmt.Sprintf("%06x", r.ManufacturerID), r.OEMData)
+		} else {
+			fields = append(fields, fmt.Sprintf("OEM record %02x", r.RecordTypeCode), r.OEMData)
+		}
+		return strings.Join(fields, " | ")
+	}
+
+	eventDir := "Asserted"
+	if sel.Standard.EventDir == EventDirDeassertion {
+		eventDir = "Deasserted"
+	}
+	fields = append(fields, fmt.Sprintf("%s %s", r.SensorType, r.sensorName()), r.Event, eventDir)
+	if r.TriggerReading != nil && r.TriggerThreshold != nil {
+		// ipmitool prints the unit only once, like "Reading 95 > Threshold 90 degrees C"
+		op := "<"
+		if sel.Standard.EventData.EventReadingOffset()%2 == 1 {
+			op = ">"
+		}
+		fields = append(fields, fmt.Sprintf("Reading %s %s Threshold %s %s",
+			formatEventValue(*r.TriggerReading), op, formatEventValue(*r.TriggerThreshold), r.Unit))
+	} else if r.EventValues != "" {
+		fields = append(fields, r.EventValues)
+	}
+	return strings.Join(fields, " | ")
+}
@@ -0,0 +1,89 @@
+package ipmi
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testSELExportRecords returns a threshold event with the SDR of the sensor,
+// a discrete event without SDR, and an OEM record. The timestamps are in UTC.
+func testSELExportRecords(t *testing.T) ([]*SEL, SDRMapBySensorNumber) {
+	records := [][]byte{
+		// upper critical going high, reading 95, threshold 90
+		{0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0x60, 0x20, 0x00, 0x04, 0x01, 0x30, 0x01, 0x59, 0x5f, 0x5a},
+		// power supply presence detected, deasserted
+		{0x02, 0x00, 0x02, 0x3c, 0x00, 0x00, 0x60, 0x20, 0x00, 0x04, 0x08, 0x40, 0xef, 0x00, 0xff, 0xff},
+		// timestamped OEM
+		{0x03, 0x00, 0xc1, 0x78, 0x00, 0x00, 0x60, 0x57, 0x01, 0x00, 0xde, 0xad, 0xbe, 0xef, 0x00, 0xff},
+	}
+
+	sels := make([]*SEL, 0, len(records))
+	for _, record := range records {
+		sel, err := ParseSEL(record)
+		if err != nil {
+			t.Fatalf("ParseSEL failed, err: %s", err)
+		}
+		switch {
+		case sel.Standard != nil:
+			sel.Standard.Timestamp = sel.Standard.Timestamp.UTC()
+		case sel.OEMTimestamped != nil:
+			sel.OEMTimestamped.Timestamp = sel.OEMTimestamped.Timestamp.UTC()
+		}
+		sels = append(sels, sel)
+	}
+
+	sdr := &SDR{
+		RecordHeader: &SDRHeader{RecordType: SDRRecordTypeFullSensor},
+		Full: &SDRFull{
+			SensorNumber:           0x30,
+			SensorEventReadingType: EventReadingTypeThreshold,
+			SensorUnit: SensorUnit{
+				AnalogDataFormat: SensorAnalogUnitFormat_Unsigned,
+				BaseUnit:         SensorUnitType_DegressC,
+			},
+			LinearizationFunc: LinearizationFunc_Linear,
+			ReadingFactors:    ReadingFactors{M: 1},
+			IDStringBytes:     []byte("CPU Temp"),
+		},
+	}
+	sdrMap := SDRMapBySensorNumber{
+		0x0020: {0x30: sdr},
+	}
+	return sels, sdrMap
+}
+
+func Test_ExportSELs(t *testing.T) {
+	sels, sdrMap := testSELExportRecords(t)
+
+	tests := []struct {
+		format   SELExportFormat
+		expected string
+	}{
+		{
+			format: SELExportFormatJSON,
+			expected: `{"record_id":1,"record_type":"standard","record_type_code":2,"timestamp":"2021-01-14T08:25:36Z","generator_id":32,"sensor_number":48,"sensor_type":"Temperature","sensor_name":"CPU Temp","event_reading_type":"Threshold","event_dir":"Assertion","event":"Upper Critical - going high","severity":"Warning","event_data":"595f5a","trigger_reading":95,"trigger_threshold":90,"unit":"degrees C","event_values":"Reading 95 degrees C \u003e Threshold 90 degrees C"}
+{"record_id":2,"record_type":"standard","record_type_code":2,"timestamp":"2021-01-14T08:26:36Z","generator_id":32,"sensor_number":64,"sensor_type":"Power Supply","event_reading_type":"Sensor Specific","event_dir":"Deassertion","event":"Presence detected","severity":"Info","event_data":"00ffff"}
+{"record_id":3,"record_type":"timestamped OEM","record_type_code":193,"timestamp":"2021-01-14T08:27:36Z","generator_id":0,"sensor_number":0,"event":"OEM record c1, manufacturer Intel (343), data deadbeef00ff","manufacturer_id":343,"manufacturer":"Intel","oem_data":"deadbeef00ff"}
+`,
+		},
+		{
+			format: SELExportFormatCSV,
+			expected: `record_id,record_type,record_type_code,timestamp,generator_id,sensor_number,sensor_type,sensor_name,event_reading_type,event_dir,event,severity,event_data,trigger_reading,trigger_threshold,unit,event_values,manufacturer_id,manufacturer,oem_data
+0x0001,standard,0x02,2021-01-14T08:25:36Z,0x0020,0x30,Temperature,CPU Temp,Threshold,Assertion,Upper Critical - going high,Warning,595f5a,95,90,degrees C,Reading 95 degrees C > Threshold 90 degrees C,0,,
+0x0002,standard,0x02,2021-01-14T08:26:36Z,0x0020,0x40,Power Supply,,Sensor Specific,Deassertion,Presence detected,Info,00ffff,,,,,0,,
+0x0003,timestamped OEM,0xc1,2021-01-14T08:27:36Z,0x0000,0x00,,,,,"OEM record c1, manufacturer Intel (343), data deadbeef00ff",,,,,,,343,Intel,deadbeef00ff
+`,
+		},
+	}
+
+	for _, test := range tests {
+		buf := new(bytes.Buffer)
+		if err := ExportSELs(buf, sels, sdrMap, OEM_UNKNOWN, test.format); err != nil {
+			t.Errorf("test %s: ExportSELs failed, err: %s", test.format, err)
+			continue
+		}
+		if got := buf.String(); got != test.expected {
+			t.Errorf("test %s not matched, got:\n%s\nexpected:\n%s", test.format, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package ipmi
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// SELOEMDecoder decodes the OEM SEL record into a human-readable description.
+// It returns false if the record is not recognized.
+type SELOEMDecoder func(sel *SEL) (string, bool)
+
+// SELOEMRecordTypeAny is used to register a SELOEMDecoder for all OEM record types of the manufacturer.
+const SELOEMRecordTypeAny SELRecordType = 0x00
+
+type selOEMDecoderKey struct {
+	manufacturerID OEM
+	recordType     SELRecordType
+}
+
+var (
+	selOEMDecoders     = map[selOEMDecoderKey]SELOEMDecoder{}
+	selOEMDecodersLock sync.RWMutex
+)
+
+// RegisterSELOEMDecoder registers the decoder for the OEM SEL records of the manufacturer and record type.
+// The record type can be SELOEMRecordTypeAny.
+// Pass OEM_UNKNOWN as manufacturerID for the non-timestamped OEM records
+// whose format is not specific to a manufacturer.
+//
+// The manufacturer ID is contained in the timestamped OEM records (C0h-DFh),
+// and for the non-timestamped OEM records (E0h-FFh) it is the manufacturer of the BMC
+// passed to SEL.OEMDescription.
+//
+// A registered decoder replaces the previous one of the same key, a nil decoder removes it.
+func RegisterSELOEMDecoder(manufacturerID OEM, recordType SELRecordType, decoder SELOEMDecoder) {
+	selOEMDecodersLock.Lock()
+	defer selOEMDecodersLock.Unlock()
+
+	key := selOEMDecoderKey{manufacturerID, recordType}
+	if decoder == nil {
+		delete(selOEMDecoders, key)
+		return
+	}
+	selOEMDecoders[key] = decoder
+}
+
+func lookupSELOEMDecoders(manufacturerID OEM, recordType SELRecordType) []SELOEMDecoder {
+	selOEMDecodersLock.RLock()
+	defer selOEMDecodersLock.RUnlock()
+
+	// the decoders of the record type are tried before the ones for any record type,
+	// eg: the Linux kernel panic records are logged on any BMC.
+	keys := []selOEMDecoderKey{
+		{manufacturerID, recordType},
+	}
+	if manufacturerID != OEM_UNKNOWN {
+		keys = append(keys, selOEMDecoderKey{OEM_UNKNOWN, recordType})
+	}
+	keys = append(keys, selOEMDecoderKey{manufacturerID, SELOEMRecordTypeAny})
+
+	out := make([]SELOEMDecoder, 0)
+	for _, key := range keys {
+		if decoder, ok := selOEMDecoders[key]; ok {
+			out = append(out, decoder)
+		}
+	}
+	return out
+}
+
+// OEMManufacturerID returns the manufacturer ID of the OEM SEL record.
+// The non-timestamped OEM records do not contain manufacturer ID, the bmcManufacturerID is returned for them.
+func (sel *SEL) OEMManufacturerID(bmcManufacturerID OEM) OEM {
+	if sel.RecordType.Range() == SELRecordTypeRangeTimestampedOEM && sel.OEMTimestamped != nil {
+		return OEM(sel.OEMTimestamped.ManufacturerID)
+	}
+	return bmcManufacturerID
+}
+
+// OEMDescription returns the description of the OEM SEL record,
+// decoded by the registered SELOEMDecoder, or the manufacturer and raw data if no decoder recognizes it.
+// The bmcManufacturerID is the manufacturer of the BMC (see GetDeviceID), only used for
+// non-timestamped OEM records, pass 0 if unknown.
+func (sel *SEL) OEMDescription(bmcManufacturerID OEM) string {
+	manufacturerID := sel.OEMManufacturerID(bmcManufacturerID)
+
+	for _, decoder := range lookupSELOEMDecoders(manufacturerID, sel.RecordType) {
+		if desc, ok := decoder(sel); ok {
+			return desc
+		}
+	}
+
+	switch sel.RecordType.Range() {
+	case SELRecordTypeRangeTimestampedOEM:
+		if sel.OEMTimestamped == nil {
+			break
+		}
+		return fmt.Sprintf("OEM record %02x, manufacturer %s (%d), data %x",
+			uint8(sel.RecordType), manufacturerID, uint32(manufacturerID), sel.OEMTimestamped.OEMDefined)
+	case SELRecordTypeRangeNonTimestampedOEM:
+		if sel.OEMNonTimestamped == nil {
+			break
+		}
+		return fmt.Sprintf("OEM record %02x, data %x", uint8(sel.RecordType), sel.OEMNonTimestamped.OEM)
+	}
+	return ""
+}
+
+func init() {
+	RegisterSELOEMDecoder(OEM_UNKNOWN, 0xf0, decodeSELLinuxKernelPanic)
+}
+
+// decodeSELLinuxKernelPanic decodes the non-timestamped OEM records (type F0h) logged
+// by the Linux IPMI driver when the kernel panics (CONFIG_IPMI_PANIC_STRING).
+// The panic string is split into records:
+//
+//	byte 4: the slave address of the BMC
+//	byte 5: the sequence number of the record
+//	byte 6-16: up to 11 characters of the panic string
+func decodeSELLinuxKernelPanic(sel *SEL) (string, bool) {
+	if sel.OEMNonTimestamped == nil {
+		return "", false
+	}
+	data := sel.OEMNonTimestamped.OEM
+
+	s := data[2:]
+	if i := bytes.IndexByte(s, 0); i >= 0 {
+		s = s[:i]
+	}
+	for _, c := range s {
+		if c < 0x20 || c > 0x7e {
+			return "", false
+		}
+	}
+	return fmt.Sprintf("Kernel panic (%d): %s", data[1], s), true
+}
@@ -0,0 +1,71 @@
+package ipmi
+
+import (
+	"testing"
+)
+
+func Test_SEL_OEMDescription(t *testing.T) {
+	RegisterSELOEMDecoder(OEM_DEBUG, 0xc5, func(sel *SEL) (string, bool) {
+		return "debug record", sel.OEMTimestamped.OEMDefined[0] == 0x01
+	})
+	t.Cleanup(func() {
+		RegisterSELOEMDecoder(OEM_DEBUG, 0xc5, nil)
+	})
+
+	tests := []struct {
+		name              string
+		data              []byte
+		bmcManufacturerID OEM
+		expected          string
+	}{
+		{
+			name:     "linux kernel panic",
+			data:     []byte{0x01, 0x00, 0xf0, 0x20, 0x00, 'N', 'o', 't', ' ', 's', 'y', 'n', 'c', 'i', 'n', 'g'},
+			expected: "Kernel panic (0): Not syncing",
+		},
+		{
+			name:              "linux kernel panic on vendor bmc",
+			data:              []byte{0x01, 0x00, 0xf0, 0x20, 0x01, 's', 'y', 'n', 'c', 'i', 'n', 'g', 0x00, 0x00, 0x00, 0x00},
+			bmcManufacturerID: OEM_DELL,
+			expected:          "Kernel panic (1): syncing",
+		},
+		{
+			name:     "registered decoder",
+			data:     []byte{0x02, 0x00, 0xc5, 0x00, 0x00, 0x00, 0x00, 0xfe, 0xff, 0xff, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
+			expected: "debug record",
+		},
+		{
+			name:     "not recognized by registered decoder",
+			data:     []byte{0x03, 0x00, 0xc5, 0x00, 0x00, 0x00, 0x00, 0xfe, 0xff, 0xff, 0x00, 0x02, 0x03, 0x04, 0x05, 0x06},
+			expected: "OEM record c5, manufacturer Unknown (16777214), data 000203040506",
+		},
+		{
+			name:     "timestamped vendor",
+			data:     []byte{0x04, 0x00, 0xc1, 0x00, 0x00, 0x00, 0x00, 0xa2, 0x02, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
+			expected: "OEM record c1, manufacturer Dell (674), data 010203040506",
+		},
+		{
+			name:              "non-timestamped vendor bmc",
+			data:              []byte{0x05, 0x00, 0xe1, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d},
+			bmcManufacturerID: OEM_LENOVO,
+			expected:          "OEM record e1, data 0102030405060708090a0b0c0d",
+		},
+		{
+			name:     "non-timestamped unknown bmc",
+			data:     []byte{0x06, 0x00, 0xe1, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d},
+			expected: "OEM record e1, data 0102030405060708090a0b0c0d",
+		},
+	}
+
+	for _, test := range tests {
+		sel, err := ParseSEL(test.data)
+		if err != nil {
+			t.Errorf("test %s ParseSEL failed, err: %s", test.name, err)
+			continue
+		}
+		got := sel.OEMDescription(test.bmcManufacturerID)
+		if got != test.expected {
+			t.Errorf("test %s not matched, got: %s, expected: %s", test.name, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package ipmi
+
+import (
+	"testing"
+)
+
+func Test_SELStandard_ThresholdEventValuesString(t *testing.T) {
+	sdr := &SDR{
+		Full: &SDRFull{
+			SensorEventReadingType: EventReadingTypeThreshold,
+			SensorUnit: SensorUnit{
+				AnalogDataFormat: SensorAnalogUnitFormat_Unsigned,
+				BaseUnit:         SensorUnitType_DegressC,
+			},
+			LinearizationFunc: LinearizationFunc_Linear,
+			ReadingFactors:    ReadingFactors{M: 1},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		eventData EventData
+		expected  string
+	}{
+		{"upper critical going high", EventData{0x59, 95, 90}, "Reading 95 degrees C > Threshold 90 degrees C"},
+		{"lower critical going low", EventData{0x52, 3, 5}, "Reading 3 degrees C < Threshold 5 degrees C"},
+		{"reading only", EventData{0x49, 95, 0}, "Reading 95 degrees C"},
+		{"unspecified", EventData{0x09, 0, 0}, ""},
+	}
+
+	for _, test := range tests {
+		sel := &SELStandard{
+			EventReadingType: EventReadingTypeThreshold,
+			EventData:        test.eventData,
+		}
+		got := sel.ThresholdEventValuesString(sdr)
+		if got != test.expected {
+			t.Errorf("test %s not matched, got: %s, expected: %s", test.name, got, test.expected)
+		}
+	}
+}
@@ -529,6 +529,36 @@ func (l LinearizationFunc) Apply(x float64) float64 {
 	return x
 }
 
+// Inverse applies the inverse function of the linearization func to the
+// input value, that is Inverse(Apply(x)) == x.
+func (l LinearizationFunc) Inverse(y float64) float64 {
+	switch l {
+	case LinearizationFunc_LN:
+		return math.Exp(y)
+	case LinearizationFunc_LOG10:
+		return math.Pow(10, y)
+	case LinearizationFunc_LOG2:
+		return math.Exp2(y)
+	case LinearizationFunc_E:
+		return math.Log(y)
+	case LinearizationFunc_EXP10:
+		return math.Log10(y)
+	case LinearizationFunc_EXP2:
+		return math.Log2(y)
+	case LinearizationFunc_1X:
+		return math.Pow(y, -1)
+	case LinearizationFunc_SQR:
+		return math.Sqrt(y)
+	case LinearizationFunc_CUBE:
+		return math.Cbrt(y)
+	case LinearizationFunc_SQRT:
+		return math.Pow(y, 2.0)
+	case LinearizationFunc_CUBERT:
+		return math.Pow(y, 3.0)
+	}
+	return y
+}
+
 type SensorUnit struct {
 	AnalogDataFormat SensorAnalogUnitFormat
 	RateUnit         SensorRateUnit
@@ -815,22 +845,19 @@ func ConvertReading(raw uint8, analogDataFormat SensorAnalogUnitFormat, factors
 
 // ConvertSensorHysteresis converts raw sensor hysterresis value to real value in the desired units for the sensor.
 //
+// The hysteresis is an amount of the reading change, not a reading, so the B offset,
+// the analog data format and the linearization do not apply, the raw value is unsigned:
+//
+//	y = M * x * 10^R_Exp
+//
+// The analogDataFormat and linearizationFunc are not used, they are kept for compatibility.
+//
 // see: 36.3 Sensor Reading Conversion Formula
 func ConvertSensorHysteresis(raw uint8, analogDataFormat SensorAnalogUnitFormat, factors ReadingFactors, linearizationFunc LinearizationFunc) float64 {
-	// y = L[(Mx + (B * 10^B_Exp) ) * 10^R_Exp ] units
-
-	analog := AnalogValue(raw, analogDataFormat)
-
-	x := float64(analog)
-
 	M := float64(factors.M)
-	B := float64(factors.B)
-	Bexp := math.Pow(10, float64(factors.B_Exp))
 	Rexp := math.Pow(10, float64(factors.R_Exp))
 
-	y := (M*x + B*Bexp) * Rexp
-
-	return linearizationFunc.Apply(y)
+	return M * float64(raw) * Rexp
 }
 
 // ConvertSensorTolerance converts raw sensor tolerance value to real value in the desired units for the sensor.
@@ -851,6 +878,80 @@ func ConvertSensorTolerance(raw uint8, analogDataFormat SensorAnalogUnitFormat,
 	return linearizationFunc.Apply(y)
 }
 
+// ConvertReadingToRaw converts the real value in the desired units for the sensor to raw
+// sensor reading or raw sensor threshold value, it is the inverse of ConvertReading.
+//
+// The result is rounded to the nearest raw value, and error is returned if the value
+// can not be represented by the analog data format.
+func ConvertReadingToRaw(value float64, analogDataFormat SensorAnalogUnitFormat, factors ReadingFactors, linearizationFunc LinearizationFunc) (uint8, error) {
+	// x = ( L'(y) / 10^R_Exp - B * 10^B_Exp ) / M
+
+	if factors.M == 0 {
+		return 0, fmt.Errorf("the M factor of the sensor is zero")
+	}
+
+	M := float64(factors.M)
+	B := float64(factors.B)
+	Bexp := math.Pow(10, float64(factors.B_Exp))
+	Rexp := math.Pow(10, float64(factors.R_Exp))
+
+	y := linearizationFunc.Inverse(value)
+	x := math.Round((y/Rexp - B*Bexp) / M)
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return 0, fmt.Errorf("value %v can not be converted by linearization %s", value, linearizationFunc)
+	}
+
+	return analogRaw(x, analogDataFormat, value)
+}
+
+// ConvertSensorHysteresisToRaw converts the real hysteresis value in the desired units for the sensor to raw value,
+// it is the inverse of ConvertSensorHysteresis:
+//
+//	raw = round(value / (M * 10^R_Exp))
+//
+// Error is returned if the raw value is negative (eg: a negative value, or any non-zero value
+// if M is negative), or not less than 0xff, which is reserved.
+func ConvertSensorHysteresisToRaw(value float64, analogDataFormat SensorAnalogUnitFormat, factors ReadingFactors, linearizationFunc LinearizationFunc) (uint8, error) {
+	if factors.M == 0 {
+		return 0, fmt.Errorf("the M factor of the sensor is zero")
+	}
+
+	M := float64(factors.M)
+	Rexp := math.Pow(10, float64(factors.R_Exp))
+
+	x := math.Round(value / (M * Rexp))
+	if math.IsNaN(x) {
+		return 0, fmt.Errorf("value %v can not be converted", value)
+	}
+	if x < 0 || x >= 0xff {
+		return 0, fmt.Errorf("hysteresis %v is out of the range of the sensor, raw %v not in [0, 254]", value, x)
+	}
+	return uint8(x), nil
+}
+
+// analogRaw encodes the analog value x into raw by the analog data format,
+// it is the inverse of AnalogValue.
+func analogRaw(x float64, format SensorAnalogUnitFormat, value float64) (uint8, error) {
+	var min, max float64 = 0, 255
+	switch format {
+	case SensorAnalogUnitFormat_1sComplement:
+		min, max = -127, 127
+	case SensorAnalogUnitFormat_2sComplement:
+		min, max = -128, 127
+	}
+	if x < min || x > max {
+		return 0, fmt.Errorf("value %v is out of the range of the sensor, raw %v not in [%v, %v]", value, x, min, max)
+	}
+
+	switch format {
+	case SensorAnalogUnitFormat_1sComplement:
+		return uint8(onesComplementEncode(int32(x), 8)), nil
+	case SensorAnalogUnitFormat_2sComplement:
+		return uint8(twosComplementEncode(int32(x), 8)), nil
+	}
+	return uint8(x), nil
+}
+
 // Sensor holds all attribute of a sensor.
 type Sensor struct {
 	SDRRecordType SDRRecordType
@@ -858,6 +959,17 @@ type Sensor struct {
 	Number uint8
 	Name   string
 
+	// OwnerID is the Sensor Owner ID, the 8-bit slave address of the controller which
+	// owns the sensor ([0] is 0b), or the system software ID ([0] is 1b).
+	OwnerID uint8
+	// OwnerLUN is the LUN of the controller which owns the sensor.
+	OwnerLUN uint8
+	// OwnerChannel is the channel of the controller which owns the sensor, 0 for primary IPMB.
+	OwnerChannel uint8
+
+	EntityID       EntityID
+	EntityInstance EntityInstance
+
 	SensorType           SensorType
 	EventReadingType     EventReadingType
 	SensorUnit           SensorUnit
@@ -915,6 +1027,10 @@ type Sensor struct {
 	}
 
 	OccuredEvents []SensorEvent
+
+	// Err is the error of retrieving the sensor attributes which are not stored in SDR
+	// from the sensor owner (eg: an absent satellite controller), they are not meaningful if it is set.
+	Err error
 }
 
 func (s *Sensor) String() string {
@@ -923,12 +1039,21 @@ func (s *Sensor) String() string {
 			fmt.Sprintf(" Sensor Type (%s) : %s (%#02x)\n", string(s.EventReadingType.SensorClass()), s.SensorType.String(), uint8(s.SensorType)) +
 			fmt.Sprintf(" Sensor Number        : %#02x\n", s.Number) +
 			fmt.Sprintf(" Sensor Name          : %s\n", s.Name) +
+			fmt.Sprintf(" Sensor Owner         : %#02x (LUN: %d, Channel: %d)\n", s.OwnerID, s.OwnerLUN, s.OwnerChannel) +
 			fmt.Sprintf(" Sensor Reading (raw) : %d\n", s.Raw) +
 			fmt.Sprintf(" Sensor Value         : %.3f %s\n", s.Value, s.SensorUnit) +
-			fmt.Sprintf(" Sensor Status        : %s\n", s.Status()),
+			fmt.Sprintf(" Sensor Status        : %s\n", s.Status()) +
+			s.errStr(),
 	)
 }
 
+func (s *Sensor) errStr() string {
+	if s.Err == nil {
+		return ""
+	}
+	return fmt.Sprintf(" Sensor Error         : %s\n", s.Err)
+}
+
 // FormatSensors return a string of table printed for sensors
 func FormatSensors(extended bool, sensors ...*Sensor) string {
 
@@ -1010,6 +1135,9 @@ func FormatSensors(extended bool, sensors ...*Sensor) string {
 }
 
 func (sensor *Sensor) Status() string {
+	if sensor.Err != nil {
+		return "Error"
+	}
 	if sensor.IsThreshold() {
 		return string(sensor.Threshold.ThresholdStatus)
 	}
@@ -1017,13 +1145,31 @@ func (sensor *Sensor) Status() string {
 	return fmt.Sprintf("0x%02x%02x", sensor.Discrete.optionalData1, sensor.Discrete.optionalData2)
 }
 
+// IsOwnedBySoftware returns whether the sensor is owned by system software, instead of a controller.
+func (sensor *Sensor) IsOwnedBySoftware() bool {
+	return isBit0Set(sensor.OwnerID)
+}
+
+// ownerTarget returns the target to access the sensor. The sensors owned by
+// system software are accessed through the BMC.
+func (sensor *Sensor) ownerTarget() target {
+	if sensor.IsOwnedBySoftware() {
+		return target{}
+	}
+	return target{
+		addr:    sensor.OwnerID,
+		channel: sensor.OwnerChannel,
+		lun:     sensor.OwnerLUN,
+	}
+}
+
 // IsThreshold returns whether the sensor is threshold sensor class or not.
 func (sensor *Sensor) IsThreshold() bool {
 	return sensor.EventReadingType.IsThreshold()
 }
 
 func (sensor *Sensor) IsReadingValid() bool {
-	return !sensor.readingUnavailable
+	return sensor.Err == nil && !sensor.readingUnavailable
 }
 
 func (sensor *Sensor) IsThresholdAndReadingValid() bool {
@@ -1055,23 +1201,43 @@ func (sensor *Sensor) ThresholdStr(thresholdType SensorThresholdType) string {
 		return "N/A"
 	}
 
-	var value float64
+	return fmt.Sprintf("%.3f", sensor.thresholdValue(thresholdType))
+}
+
+func (sensor *Sensor) thresholdValue(thresholdType SensorThresholdType) float64 {
 	switch thresholdType {
 	case SensorThresholdType_LCR:
-		value = sensor.Threshold.LCR
+		return sensor.Threshold.LCR
 	case SensorThresholdType_LNR:
-		value = sensor.Threshold.LNR
+		return sensor.Threshold.LNR
 	case SensorThresholdType_LNC:
-		value = sensor.Threshold.LNC
+		return sensor.Threshold.LNC
 	case SensorThresholdType_UCR:
-		value = sensor.Threshold.UCR
+		return sensor.Threshold.UCR
 	case SensorThresholdType_UNC:
-		value = sensor.Threshold.UNC
+		return sensor.Threshold.UNC
 	case SensorThresholdType_UNR:
-		value = sensor.Threshold.UNR
+		return sensor.Threshold.UNR
 	}
+	return 0
+}
 
-	return fmt.Sprintf("%.3f", value)
+func (sensor *Sensor) thresholdRaw(thresholdType SensorThresholdType) uint8 {
+	switch thresholdType {
+	case SensorThresholdType_LCR:
+		return sensor.Threshold.LCR_Raw
+	case SensorThresholdType_LNR:
+		return sensor.Threshold.LNR_Raw
+	case SensorThresholdType_LNC:
+		return sensor.Threshold.LNC_Raw
+	case SensorThresholdType_UCR:
+		return sensor.Threshold.UCR_Raw
+	case SensorThresholdType_UNC:
+		return sensor.Threshold.UNC_Raw
+	case SensorThresholdType_UNR:
+		return sensor.Threshold.UNR_Raw
+	}
+	return 0
 }
 
 // ConvertReading converts raw discrete-sensor reading or raw threshold-sensor value to real value in the desired units for the sensor.
@@ -1098,6 +1264,30 @@ func (sensor *Sensor) ConvertSensorTolerance(raw uint8) float64 {
 	return float64(raw)
 }
 
+// ConvertReadingToRaw converts the real value to raw threshold-sensor value, it is the inverse of ConvertReading.
+func (sensor *Sensor) ConvertReadingToRaw(value float64) (uint8, error) {
+	if sensor.HasAnalogReading {
+		return ConvertReadingToRaw(value, sensor.SensorUnit.AnalogDataFormat, sensor.Threshold.ReadingFactors, sensor.Threshold.LinearizationFunc)
+	}
+	return rawUint8(value)
+}
+
+// ConvertSensorHysteresisToRaw converts the real hysteresis value to raw value,
+// it is the inverse of Sensor.ConvertSensorHysteresis.
+func (sensor *Sensor) ConvertSensorHysteresisToRaw(value float64) (uint8, error) {
+	if sensor.HasAnalogReading {
+		return ConvertSensorHysteresisToRaw(value, sensor.SensorUnit.AnalogDataFormat, sensor.Threshold.ReadingFactors, sensor.Threshold.LinearizationFunc)
+	}
+	return rawUint8(value)
+}
+
+func rawUint8(value float64) (uint8, error) {
+	if value < 0 || value > 255 || value != math.Trunc(value) {
+		return 0, fmt.Errorf("value %v is not a valid raw value", value)
+	}
+	return uint8(value), nil
+}
+
 func (sensor *Sensor) HysteresisStr(raw uint8) string {
 	switch sensor.SDRRecordType {
 	case SDRRecordTypeFullSensor:
@@ -0,0 +1,237 @@
+package ipmi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// SensorProfile describes the desired thresholds, hysteresis and event enables of sensors,
+// it is compared with the live values by DiffSensorProfile and applied by ApplySensorProfile.
+//
+// A sensor may be matched by several entries, the later entries override the earlier ones.
+//
+//	{
+//	  "sensors": [
+//	    {"name": "FAN*", "thresholds": {"lcr": 300, "lnc": 500}},
+//	    {"name": "CPU? Temp", "thresholds": {"unc": 85, "ucr": 90}, "positive_hysteresis": 2, "negative_hysteresis": 2},
+//	    {"name": "PS? Status", "event_messages": false},
+//	    {"name": "FAN?", "assertion_events": ["lcr-", "lnr-"], "deassertion_events": []}
+//	  ]
+//	}
+type SensorProfile struct {
+	Sensors []SensorProfileEntry `json:"sensors"`
+}
+
+type SensorProfileEntry struct {
+	// Name is the sensor name pattern, see path.Match for the syntax.
+	Name string `json:"name"`
+
+	// Thresholds is keyed by lnr, lcr, lnc, unc, ucr or unr, the values are in the units of the sensor.
+	Thresholds map[string]float64 `json:"thresholds,omitempty"`
+
+	PositiveHysteresis *float64 `json:"positive_hysteresis,omitempty"`
+	NegativeHysteresis *float64 `json:"negative_hysteresis,omitempty"`
+
+	EventMessages  *bool `json:"event_messages,omitempty"`
+	SensorScanning *bool `json:"sensor_scanning,omitempty"`
+
+	// AssertionEvents and DeassertionEvents are the events to be enabled, the others are disabled.
+	// The events are in the format of SensorEvent.String, eg: "ucr+", "lcr-" or "state3".
+	// A nil list leaves the events unchanged, an empty list disables all of them.
+	AssertionEvents   []string `json:"assertion_events,omitempty"`
+	DeassertionEvents []string `json:"deassertion_events,omitempty"`
+}
+
+// ParseSensorProfile parses the JSON sensor profile.
+func ParseSensorProfile(data []byte) (*SensorProfile, error) {
+	profile := &SensorProfile{}
+	if err := json.Unmarshal(data, profile); err != nil {
+		return nil, fmt.Errorf("unmarshal sensor profile failed, err: %s", err)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Validate checks the patterns and threshold names of the profile.
+func (profile *SensorProfile) Validate() error {
+	for i, entry := range profile.Sensors {
+		if _, err := path.Match(entry.Name, ""); err != nil || entry.Name == "" {
+			return fmt.Errorf("sensor profile entry %d has invalid name pattern %q", i, entry.Name)
+		}
+		for name := range entry.Thresholds {
+			if _, ok := sensorThresholdTypeByAbbr(name); !ok {
+				return fmt.Errorf("sensor profile entry %d (%s) has unknown threshold %q", i, entry.Name, name)
+			}
+		}
+		if _, err := parseSensorEvents(entry.AssertionEvents, true); err != nil {
+			return fmt.Errorf("sensor profile entry %d (%s) has invalid assertion events, err: %s", i, entry.Name, err)
+		}
+		if _, err := parseSensorEvents(entry.DeassertionEvents, false); err != nil {
+			return fmt.Errorf("sensor profile entry %d (%s) has invalid deassertion events, err: %s", i, entry.Name, err)
+		}
+	}
+	return nil
+}
+
+// desired merges the entries matching the sensor name, it returns false if no entry matches.
+func (profile *SensorProfile) desired(sensorName string) (SensorProfileEntry, bool) {
+	out := SensorProfileEntry{
+		Name:       sensorName,
+		Thresholds: map[string]float64{},
+	}
+
+	var matched bool
+	for _, entry := range profile.Sensors {
+		if ok, _ := path.Match(entry.Name, sensorName); !ok {
+			continue
+		}
+		matched = true
+
+		for k, v := range entry.Thresholds {
+			out.Thresholds[k] = v
+		}
+		if entry.PositiveHysteresis != nil {
+			out.PositiveHysteresis = entry.PositiveHysteresis
+		}
+		if entry.NegativeHysteresis != nil {
+			out.NegativeHysteresis = entry.NegativeHysteresis
+		}
+		if entry.EventMessages != nil {
+			out.EventMessages = entry.EventMessages
+		}
+		if entry.SensorScanning != nil {
+			out.SensorScanning = entry.SensorScanning
+		}
+		if entry.AssertionEvents != nil {
+			out.AssertionEvents = entry.AssertionEvents
+		}
+		if entry.DeassertionEvents != nil {
+			out.DeassertionEvents = entry.DeassertionEvents
+		}
+	}
+	return out, matched
+}
+
+func parseSensorEvents(events []string, assert bool) ([]SensorEvent, error) {
+	out := make([]SensorEvent, 0, len(events))
+	for _, s := range events {
+		event, err := ParseSensorEvent(s, assert)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, event)
+	}
+	return out, nil
+}
+
+// sensorThresholdTypesOrdered lists the threshold types from the lowest to the highest.
+var sensorThresholdTypesOrdered = []SensorThresholdType{
+	SensorThresholdType_LNR,
+	SensorThresholdType_LCR,
+	SensorThresholdType_LNC,
+	SensorThresholdType_UNC,
+	SensorThresholdType_UCR,
+	SensorThresholdType_UNR,
+}
+
+func sensorThresholdTypeByAbbr(abbr string) (SensorThresholdType, bool) {
+	for _, t := range sensorThresholdTypesOrdered {
+		if t.Abbr() == abbr {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// checkSensorThresholdsOrder checks the thresholds are in the proper order,
+// that is LNR < LCR < LNC < UNC < UCR < UNR. Absent thresholds are skipped.
+func checkSensorThresholdsOrder(thresholds map[SensorThresholdType]float64) error {
+	var lastType SensorThresholdType
+	for _, t := range sensorThresholdTypesOrdered {
+		v, ok := thresholds[t]
+		if !ok {
+			continue
+		}
+		if lastType != "" && v <= thresholds[lastType] {
+			return fmt.Errorf("threshold %s (%v) is not higher than %s (%v)", t.Abbr(), v, lastType.Abbr(), thresholds[lastType])
+		}
+		lastType = t
+	}
+	return nil
+}
+
+// SensorProfileChange is a setting of the sensor to be changed.
+type SensorProfileChange struct {
+	// Setting is the threshold abbr (lnr, lcr, lnc, unc, ucr, unr), positive_hysteresis,
+	// negative_hysteresis, event_messages, sensor_scanning, assertion_events or deassertion_events.
+	Setting string
+	Current string
+	Desired string
+}
+
+// SensorProfileDiff holds the changes of a sensor to match the profile.
+type SensorProfileDiff struct {
+	Sensor  *Sensor
+	Changes []SensorProfileChange
+
+	// Err is set if the sensor can not be compared with the profile, eg: its owner
+	// is not reachable, the Changes are unknown then.
+	Err error
+
+	thresholds map[SensorThresholdType]float64
+
+	hysteresis            bool
+	positiveHysteresisRaw uint8
+	negativeHysteresisRaw uint8
+
+	eventEnable           bool
+	eventMessagesEnabled  bool
+	sensorScanningEnabled bool
+
+	// the event masks before and after the changes
+	eventMasks                  bool
+	currentAssertionEventMask   uint16
+	currentDeassertionEventMask uint16
+	assertionEventMask          uint16
+	deassertionEventMask        uint16
+}
+
+// FormatSensorProfileDiffs returns a table of the changes, the sensors which can not
+// be compared are listed with the "error" setting.
+func FormatSensorProfileDiffs(diffs []*SensorProfileDiff) string {
+	var buf = new(bytes.Buffer)
+	table := tablewriter.NewWriter(buf)
+	table.SetAutoWrapText(false)
+	table.SetHeader([]string{"SensorNumber", "SensorName", "Setting", "Current", "Desired"})
+
+	for _, diff := range diffs {
+		if diff.Err != nil {
+			table.Append([]string{
+				fmt.Sprintf("%#02x", diff.Sensor.Number),
+				diff.Sensor.Name,
+				"error",
+				diff.Err.Error(),
+				"",
+			})
+			continue
+		}
+		for _, change := range diff.Changes {
+			table.Append([]string{
+				fmt.Sprintf("%#02x", diff.Sensor.Number),
+				diff.Sensor.Name,
+				change.Setting,
+				change.Current,
+				change.Desired,
+			})
+		}
+	}
+
+	table.Render()
+	return buf.String()
+}
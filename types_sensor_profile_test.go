@@ -0,0 +1,290 @@
+package ipmi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_SensorProfile(t *testing.T) {
+	profile, err := ParseSensorProfile([]byte(`{
+		"sensors": [
+			{"name": "FAN*", "thresholds": {"lcr": 300, "lnc": 500}, "event_messages": true},
+			{"name": "FAN2", "thresholds": {"lnc": 600}, "positive_hysteresis": 2}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("parse sensor profile failed, err: %s", err)
+	}
+
+	desired, ok := profile.desired("FAN2")
+	if !ok {
+		t.Fatalf("FAN2 expected matched")
+	}
+	if desired.Thresholds["lcr"] != 300 || desired.Thresholds["lnc"] != 600 {
+		t.Errorf("thresholds not merged, got: %v", desired.Thresholds)
+	}
+	if desired.PositiveHysteresis == nil || *desired.PositiveHysteresis != 2 || desired.EventMessages == nil || !*desired.EventMessages {
+		t.Errorf("hysteresis and event enables not merged, got: %+v", desired)
+	}
+	if _, ok := profile.desired("CPU Temp"); ok {
+		t.Errorf("CPU Temp expected not matched")
+	}
+
+	for _, data := range []string{
+		`{"sensors": [{"name": "FAN*", "thresholds": {"lower": 300}}]}`,
+		`{"sensors": [{"name": "[", "thresholds": {"lcr": 300}}]}`,
+	} {
+		if _, err := ParseSensorProfile([]byte(data)); err == nil {
+			t.Errorf("profile %s expected invalid", data)
+		}
+	}
+
+	if err := checkSensorThresholdsOrder(map[SensorThresholdType]float64{
+		SensorThresholdType_LCR: 300, SensorThresholdType_LNC: 500, SensorThresholdType_UNC: 20000,
+	}); err != nil {
+		t.Errorf("thresholds expected in order, err: %s", err)
+	}
+	if err := checkSensorThresholdsOrder(map[SensorThresholdType]float64{
+		SensorThresholdType_LCR: 600, SensorThresholdType_LNC: 500,
+	}); err == nil {
+		t.Errorf("thresholds expected not in order")
+	}
+}
+
+func Test_SensorProfile_EventMasks(t *testing.T) {
+	tests := []struct {
+		name        string
+		threshold   bool
+		desired     SensorProfileEntry
+		current     []byte
+		expectedErr bool
+		// the data of the Set Sensor Event Enable requests
+		expected [][]byte
+	}{
+		{
+			name:      "threshold events",
+			threshold: true,
+			desired: SensorProfileEntry{
+				AssertionEvents:   []string{"lcr-", "ucr+"},
+				DeassertionEvents: []string{},
+			},
+			// messages and scanning enabled, assert lnc- lcr-, deassert lcr-
+			current: []byte{0xc0, 0x05, 0x00, 0x04, 0x00},
+			expected: [][]byte{
+				// enable ucr+ assertion
+				{0x30, 0xd0, 0x00, 0x02, 0x00, 0x00},
+				// disable lnc- assertion and lcr- deassertion
+				{0x30, 0xe0, 0x01, 0x00, 0x04, 0x00},
+			},
+		},
+		{
+			name:    "discrete states with event messages",
+			desired: SensorProfileEntry{EventMessages: boolPtr(false), AssertionEvents: []string{"state0", "state1"}},
+			current: []byte{0xc0, 0x01, 0x00, 0x00, 0x00},
+			expected: [][]byte{
+				{0x30, 0x50, 0x02, 0x00, 0x00, 0x00},
+			},
+		},
+		{
+			name:     "not changed",
+			desired:  SensorProfileEntry{AssertionEvents: []string{"state0"}},
+			current:  []byte{0xc0, 0x01, 0x00, 0x00, 0x00},
+			expected: nil,
+		},
+		{
+			name:        "wrong sensor class",
+			threshold:   true,
+			desired:     SensorProfileEntry{AssertionEvents: []string{"state0"}},
+			current:     []byte{0xc0, 0x00, 0x00, 0x00, 0x00},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		var got [][]byte
+		c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+			switch req.Cmd {
+			case CommandGetSensorEventEnable.ID:
+				return 0x00, test.current
+			case CommandSetSensorEventEnable.ID:
+				got = append(got, append([]byte{}, req.Data...))
+				return 0x00, nil
+			}
+			return 0xc1, nil
+		})
+
+		sensor := &Sensor{Number: 0x30, Name: "test", EventReadingType: EventReadingTypeSensorSpecific}
+		if test.threshold {
+			sensor.EventReadingType = EventReadingTypeThreshold
+		}
+		diff := &SensorProfileDiff{Sensor: sensor}
+		err := c.diffSensorEventEnable(diff, test.desired)
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("test %s expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %s: diffSensorEventEnable failed, err: %s", test.name, err)
+			continue
+		}
+		if err := c.applySensorProfileDiff(diff); err != nil {
+			t.Errorf("test %s: applySensorProfileDiff failed, err: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("test %s not matched, got: % x, expected: % x", test.name, got, test.expected)
+		}
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func Test_DiffSensorProfile(t *testing.T) {
+	// FAN1: raw = value, thresholds lcr lnc unc ucr readable and settable, lnr unr not settable
+	fan1 := &SDR{
+		RecordHeader: &SDRHeader{RecordType: SDRRecordTypeFullSensor},
+		Full: &SDRFull{
+			GeneratorID:            GeneratorID(BMC_SA),
+			SensorNumber:           0x30,
+			SensorEventReadingType: EventReadingTypeThreshold,
+			SensorCapabilitites: SensorCapabilitites{
+				HysteresisAccess: SensorHysteresisAccess_ReadableSettable,
+				ThresholdAccess:  SensorThresholdAccess_ReadableSettable,
+			},
+			Mask: Mask{Threshold: Mask_Thresholds{
+				LCR: Mask_Threshold{Settable: true},
+				LNC: Mask_Threshold{Settable: true},
+				UNC: Mask_Threshold{Settable: true},
+				UCR: Mask_Threshold{Settable: true},
+			}},
+			ReadingFactors: ReadingFactors{M: 1},
+			IDStringBytes:  []byte("FAN1"),
+		},
+	}
+	// FAN2: owned by an absent satellite controller
+	fan2 := &SDR{
+		RecordHeader: &SDRHeader{RecordType: SDRRecordTypeCompactSensor},
+		Compact: &SDRCompact{
+			GeneratorID:            0x2c,
+			SensorNumber:           0x31,
+			SensorEventReadingType: EventReadingTypeThreshold,
+			IDStringBytes:          []byte("FAN2"),
+		},
+	}
+
+	tests := []struct {
+		name    string
+		profile string
+		// the settings of the changes, prefixed by the sensor name
+		expected    []string
+		expectedErr bool
+		// the Set Sensor Thresholds and Set Sensor Hysteresis requests sent by ApplySensorProfile, prefixed by the command
+		expectedRequests [][]byte
+	}{
+		{
+			name:     "unchanged raw values",
+			profile:  `{"sensors": [{"name": "FAN1", "thresholds": {"lcr": 10, "unc": 80}, "positive_hysteresis": 2, "negative_hysteresis": 2}]}`,
+			expected: []string{},
+		},
+		{
+			name:     "thresholds and hysteresis",
+			profile:  `{"sensors": [{"name": "FAN1", "thresholds": {"lcr": 15, "unc": 80, "ucr": 95}, "positive_hysteresis": 3}]}`,
+			expected: []string{"FAN1 lcr", "FAN1 ucr", "FAN1 positive_hysteresis"},
+			expectedRequests: [][]byte{
+				// set lcr and ucr, the others are sent with the live raw values
+				{CommandSetSensorThresholds.ID, 0x30, 0x12, 20, 15, 0, 80, 95, 0},
+				// the negative hysteresis is sent with the live raw value
+				{CommandSetSensorHysteresis.ID, 0x30, 0xff, 3, 2},
+			},
+		},
+		{
+			name:        "threshold not settable",
+			profile:     `{"sensors": [{"name": "FAN1", "thresholds": {"lnr": 5}}]}`,
+			expectedErr: true,
+		},
+		{
+			// the live unc (80) is merged with the desired lnc
+			name:        "thresholds not in order with live values",
+			profile:     `{"sensors": [{"name": "FAN1", "thresholds": {"lnc": 85}}]}`,
+			expectedErr: true,
+		},
+		{
+			name:        "hysteresis out of range",
+			profile:     `{"sensors": [{"name": "FAN1", "negative_hysteresis": 300}]}`,
+			expectedErr: true,
+		},
+		{
+			name:     "unreachable sensor",
+			profile:  `{"sensors": [{"name": "FAN*", "thresholds": {"lcr": 12}}]}`,
+			expected: []string{"FAN1 lcr", "FAN2 error"},
+			expectedRequests: [][]byte{
+				{CommandSetSensorThresholds.ID, 0x30, 0x02, 20, 12, 0, 80, 90, 0},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		var requests [][]byte
+		c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+			switch req.Cmd {
+			case CommandGetSensorReading.ID:
+				return 0x00, []byte{50, 0xc0, 0x00, 0x00}
+			case CommandGetSensorThresholds.ID:
+				// lnc lcr unc ucr readable
+				return 0x00, []byte{0x1b, 20, 10, 0, 80, 90, 0}
+			case CommandGetSensorHysteresis.ID:
+				return 0x00, []byte{2, 2}
+			case CommandSetSensorThresholds.ID, CommandSetSensorHysteresis.ID:
+				requests = append(requests, append([]byte{req.Cmd}, req.Data...))
+				return 0x00, nil
+			case CommandSendMessage.ID:
+				return 0x83, nil
+			}
+			return 0xc1, nil
+		})
+		c.sdrFile = "test"
+		c.sdrFileSDRs = []*SDR{fan1, fan2}
+
+		profile, err := ParseSensorProfile([]byte(test.profile))
+		if err != nil {
+			t.Fatalf("test %s: parse sensor profile failed, err: %s", test.name, err)
+		}
+
+		diffs, err := c.DiffSensorProfile(profile)
+		if (err != nil) != test.expectedErr {
+			t.Errorf("test %s failed, got err: %v, expected err: %v", test.name, err, test.expectedErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		got := []string{}
+		for _, diff := range diffs {
+			if diff.Err != nil {
+				got = append(got, diff.Sensor.Name+" error")
+			}
+			for _, change := range diff.Changes {
+				got = append(got, diff.Sensor.Name+" "+change.Setting)
+			}
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("test %s not matched, got: %v, expected: %v", test.name, got, test.expected)
+		}
+		if len(requests) != 0 {
+			t.Errorf("test %s: DiffSensorProfile expected to send no set requests, got: % x", test.name, requests)
+		}
+
+		if err := c.ApplySensorProfile(diffs); err != nil {
+			t.Errorf("test %s: ApplySensorProfile failed, err: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(requests, test.expectedRequests) {
+			t.Errorf("test %s requests not matched, got: % x, expected: % x", test.name, requests, test.expectedRequests)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package ipmi
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_ConvertReadingToRaw(t *testing.T) {
+	tests := []struct {
+		name              string
+		format            SensorAnalogUnitFormat
+		factors           ReadingFactors
+		linearizationFunc LinearizationFunc
+	}{
+		{"fan", SensorAnalogUnitFormat_Unsigned, ReadingFactors{M: 100}, LinearizationFunc_Linear},
+		{"voltage", SensorAnalogUnitFormat_Unsigned, ReadingFactors{M: 2, B: 5, B_Exp: 1, R_Exp: -2}, LinearizationFunc_Linear},
+		{"temperature 1s", SensorAnalogUnitFormat_1sComplement, ReadingFactors{M: 1}, LinearizationFunc_Linear},
+		{"temperature 2s", SensorAnalogUnitFormat_2sComplement, ReadingFactors{M: 1, B: -3}, LinearizationFunc_Linear},
+		{"sqr", SensorAnalogUnitFormat_Unsigned, ReadingFactors{M: 3, R_Exp: -1}, LinearizationFunc_SQR},
+		{"1/x", SensorAnalogUnitFormat_Unsigned, ReadingFactors{M: 1, B: 1}, LinearizationFunc_1X},
+	}
+
+	for _, test := range tests {
+		for raw := 0; raw <= 0xff; raw++ {
+			if test.format == SensorAnalogUnitFormat_1sComplement && raw == 0xff {
+				// negative zero
+				continue
+			}
+			value := ConvertReading(uint8(raw), test.format, test.factors, test.linearizationFunc)
+			got, err := ConvertReadingToRaw(value, test.format, test.factors, test.linearizationFunc)
+			if err != nil {
+				t.Errorf("test %s raw %#02x failed, err: %s", test.name, raw, err)
+				continue
+			}
+			if got != uint8(raw) {
+				t.Errorf("test %s not matched, value: %v, got: %#02x, expected: %#02x", test.name, value, got, raw)
+			}
+		}
+	}
+
+	if _, err := ConvertReadingToRaw(26000, SensorAnalogUnitFormat_Unsigned, ReadingFactors{M: 100}, LinearizationFunc_Linear); err == nil {
+		t.Errorf("out of range value expected error")
+	}
+	if _, err := ConvertReadingToRaw(-129, SensorAnalogUnitFormat_2sComplement, ReadingFactors{M: 1}, LinearizationFunc_Linear); err == nil {
+		t.Errorf("out of range value expected error")
+	}
+}
+
+func Test_ConvertSensorHysteresisToRaw(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       float64
+		format      SensorAnalogUnitFormat
+		factors     ReadingFactors
+		expected    uint8
+		expectedErr bool
+	}{
+		{"fan", 300, SensorAnalogUnitFormat_Unsigned, ReadingFactors{M: 100}, 3, false},
+		{"rounded", 0.26, SensorAnalogUnitFormat_Unsigned, ReadingFactors{M: 5, R_Exp: -2}, 5, false},
+		{"b ignored", 2, SensorAnalogUnitFormat_Unsigned, ReadingFactors{M: 2, B: 50, B_Exp: 1, R_Exp: -1}, 10, false},
+		{"signed format ignored", 200, SensorAnalogUnitFormat_2sComplement, ReadingFactors{M: 1, B: -3}, 200, false},
+		{"max", 254, SensorAnalogUnitFormat_Unsigned, ReadingFactors{M: 1}, 0xfe, false},
+		{"too big", 255, SensorAnalogUnitFormat_Unsigned, ReadingFactors{M: 1}, 0, true},
+		{"negative", -5, SensorAnalogUnitFormat_Unsigned, ReadingFactors{M: 1, B: 20}, 0, true},
+		{"negative M", 4, SensorAnalogUnitFormat_1sComplement, ReadingFactors{M: -2, B: 10}, 0, true},
+		{"zero M", 1, SensorAnalogUnitFormat_Unsigned, ReadingFactors{}, 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := ConvertSensorHysteresisToRaw(test.value, test.format, test.factors, LinearizationFunc_Linear)
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("test %s expected error, got: %#02x", test.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %s failed, err: %s", test.name, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("test %s not matched, got: %#02x, expected: %#02x", test.name, got, test.expected)
+		}
+
+		// the hysteresis read back is the one set, B and the format do not apply
+		back := ConvertSensorHysteresis(got, test.format, test.factors, LinearizationFunc_LN)
+		expected := float64(test.factors.M) * float64(got) * math.Pow(10, float64(test.factors.R_Exp))
+		if back != expected {
+			t.Errorf("test %s read back not matched, got: %v, expected: %v", test.name, back, expected)
+		}
+	}
+}
+
+func Test_packSensorEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []string
+		assert   bool
+		expected []byte
+	}{
+		{"lnc-", []string{"lnc-"}, true, []byte{0x01, 0x00, 0x00, 0x00}},
+		{"unc+", []string{"unc+"}, true, []byte{0x80, 0x00, 0x00, 0x00}},
+		{"ucr unr", []string{"ucr+", "unr-"}, true, []byte{0x00, 0x06, 0x00, 0x00}},
+		{"deassert", []string{"lcr+", "unr+"}, false, []byte{0x00, 0x00, 0x08, 0x08}},
+		{"states", []string{"state0", "state7", "state14"}, true, []byte{0x81, 0x40, 0x00, 0x00}},
+	}
+
+	for _, test := range tests {
+		events := make([]SensorEvent, 0)
+		for _, s := range test.events {
+			event, err := ParseSensorEvent(s, test.assert)
+			if err != nil {
+				t.Fatalf("test %s parse event failed, err: %s", test.name, err)
+			}
+			events = append(events, event)
+		}
+		got := packSensorEvents(events)
+		if !bytes.Equal(got, test.expected) {
+			t.Errorf("test %s not matched, got: %#v, expected: %#v", test.name, got, test.expected)
+		}
+	}
+
+	for _, s := range []string{"state15", "state3x", "abc+", "unc"} {
+		if _, err := ParseSensorEvent(s, true); err == nil {
+			t.Errorf("event %s expected invalid", s)
+		}
+	}
+}
+
+func Test_Sensor_ownerTarget(t *testing.T) {
+	tests := []struct {
+		name            string
+		sensor          *Sensor
+		expected        target
+		expectedBridged bool
+	}{
+		{"bmc", &Sensor{OwnerID: BMC_SA, OwnerLUN: 0}, target{addr: BMC_SA}, false},
+		{"bmc lun", &Sensor{OwnerID: BMC_SA, OwnerLUN: 2}, target{addr: BMC_SA, lun: 2}, false},
+		{"software", &Sensor{OwnerID: 0x41, OwnerLUN: 1}, target{}, false},
+		{"satellite", &Sensor{OwnerID: 0x2c, OwnerChannel: 7, OwnerLUN: 1}, target{addr: 0x2c, channel: 7, lun: 1}, true},
+	}
+
+	for _, test := range tests {
+		got := test.sensor.ownerTarget()
+		if got != test.expected {
+			t.Errorf("test %s not matched, got: %+v, expected: %+v", test.name, got, test.expected)
+		}
+		if got.bridged() != test.expectedBridged {
+			t.Errorf("test %s bridged not matched, got: %v, expected: %v", test.name, got.bridged(), test.expectedBridged)
+		}
+	}
+}
+
+func Test_UpdateSensorEventEnable(t *testing.T) {
+	disabled := false
+
+	tests := []struct {
+		name   string
+		update SensorEventEnableUpdate
+		// the data of the Set Sensor Event Enable requests
+		expected [][]byte
+	}{
+		{
+			name:   "events keep the messages and scanning",
+			update: SensorEventEnableUpdate{Enable: []SensorEvent{SensorEvent_UCR_High_Assert}},
+			expected: [][]byte{
+				{0x30, 0xd0, 0x00, 0x02, 0x00, 0x00},
+			},
+		},
+		{
+			name: "enable and disable",
+			update: SensorEventEnableUpdate{
+				SensorScanningEnabled: &disabled,
+				Enable:                []SensorEvent{SensorEvent_State_1_Assert},
+				Disable:               []SensorEvent{SensorEvent_State_0_Deassert},
+			},
+			expected: [][]byte{
+				{0x30, 0x90, 0x02, 0x00, 0x00, 0x00},
+				{0x30, 0xa0, 0x00, 0x00, 0x01, 0x00},
+			},
+		},
+		{
+			name:   "messages only",
+			update: SensorEventEnableUpdate{EventMessagesEnabled: &disabled},
+			expected: [][]byte{
+				{0x30, 0x40},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		var got [][]byte
+		c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+			switch req.Cmd {
+			case CommandGetSensorEventEnable.ID:
+				// event messages and scanning enabled
+				return 0x00, []byte{0xc0, 0x00, 0x00, 0x00, 0x00}
+			case CommandSetSensorEventEnable.ID:
+				got = append(got, append([]byte{}, req.Data...))
+				return 0x00, nil
+			}
+			return 0xc1, nil
+		})
+
+		if err := c.UpdateSensorEventEnable(0x30, test.update); err != nil {
+			t.Errorf("test %s: UpdateSensorEventEnable failed, err: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("test %s not matched, got: % x, expected: % x", test.name, got, test.expected)
+		}
+	}
+}
+
+func Test_GetSensorByOwner(t *testing.T) {
+	newCompactSDR := func(name string, generatorID GeneratorID) *SDR {
+		return &SDR{
+			RecordHeader: &SDRHeader{RecordType: SDRRecordTypeCompactSensor},
+			Compact: &SDRCompact{
+				GeneratorID:            generatorID,
+				SensorNumber:           0x10,
+				SensorEventReadingType: EventReadingTypeSensorSpecific,
+				IDStringBytes:          []byte(name),
+			},
+		}
+	}
+
+	c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+		switch req.Cmd {
+		case CommandGetSensorReading.ID:
+			// reading 0x05, event messages and scanning enabled, state0 asserted
+			return 0x00, []byte{0x05, 0xc0, 0x01, 0x00}
+		case CommandGetSensorEventStatus.ID:
+			return 0x00, []byte{0xc0, 0x01, 0x00, 0x00, 0x00}
+		case CommandSendMessage.ID:
+			// the satellite controller is absent
+			return 0x83, nil
+		}
+		return 0xc1, nil
+	})
+	c.sdrFile = "test"
+	c.sdrFileSDRs = []*SDR{
+		newCompactSDR("bmc", GeneratorID(BMC_SA)),
+		newCompactSDR("satellite", 0x2c),
+	}
+
+	if _, err := c.GetSensorByID(0x10); err == nil {
+		t.Errorf("GetSensorByID expected error for the sensor number of several owners")
+	}
+
+	tests := []struct {
+		name          string
+		ownerID       uint8
+		expectedName  string
+		expectedValid bool
+		expectedErr   bool
+	}{
+		{"bmc", BMC_SA, "bmc", true, false},
+		{"satellite", 0x2c, "satellite", false, true},
+	}
+
+	for _, test := range tests {
+		sensor, err := c.GetSensorByOwner(test.ownerID, 0, 0x10)
+		if err != nil {
+			t.Errorf("test %s: GetSensorByOwner failed, err: %s", test.name, err)
+			continue
+		}
+		if sensor.Name != test.expectedName {
+			t.Errorf("test %s not matched, got: %s, expected: %s", test.name, sensor.Name, test.expectedName)
+		}
+		if sensor.IsReadingValid() != test.expectedValid {
+			t.Errorf("test %s reading valid not matched, got: %v, expected: %v", test.name, sensor.IsReadingValid(), test.expectedValid)
+		}
+		if (sensor.Err != nil) != test.expectedErr {
+			t.Errorf("test %s error not matched, got: %v, expected error: %v", test.name, sensor.Err, test.expectedErr)
+		}
+	}
+}
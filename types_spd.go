@@ -1 +1,327 @@
 package ipmi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// SPD (Serial Presence Detect) holds the decoded contents of the SPD EEPROM of
+// a DIMM, which is exposed as a FRU device with DIMM Memory ID (0x01) device
+// type modifier.
+//
+// see: JEDEC 21-C 4.1.2.11 (DDR3), 4.1.2.12 (DDR4) and JESD400-5 (DDR5)
+type SPD struct {
+	MemoryType SPDMemoryType
+	ModuleType string
+
+	CapacityMB uint64
+	// SpeedMTs is the max data rate in MT/s
+	SpeedMTs uint32
+	Ranks    uint8
+	// BusWidth is the primary bus width in bits, not including ECC.
+	BusWidth uint8
+	ECC      bool
+
+	// ModuleManufacturerID is the JEDEC JEP-106 ID, the high byte is the
+	// continuation code count (with the parity bit) and the low byte is the
+	// manufacturer code, eg: 0x80ce for Samsung.
+	ModuleManufacturerID  uint16
+	ManufacturingLocation uint8
+	// ManufacturingYear and ManufacturingWeek are zero if unspecified.
+	ManufacturingYear uint16
+	ManufacturingWeek uint8
+	SerialNumber      uint32
+	PartNumber        string
+}
+
+type SPDMemoryType uint8
+
+const (
+	SPDMemoryTypeDDR3   SPDMemoryType = 0x0b
+	SPDMemoryTypeDDR4   SPDMemoryType = 0x0c
+	SPDMemoryTypeDDR4E  SPDMemoryType = 0x0e
+	SPDMemoryTypeLPDDR3 SPDMemoryType = 0x0f
+	SPDMemoryTypeLPDDR4 SPDMemoryType = 0x10
+	SPDMemoryTypeDDR5   SPDMemoryType = 0x12
+	SPDMemoryTypeLPDDR5 SPDMemoryType = 0x13
+)
+
+func (t SPDMemoryType) String() string {
+	m := map[SPDMemoryType]string{
+		0x01: "FPM DRAM",
+		0x02: "EDO",
+		0x04: "SDRAM",
+		0x07: "DDR SDRAM",
+		0x08: "DDR2 SDRAM",
+		0x0b: "DDR3 SDRAM",
+		0x0c: "DDR4 SDRAM",
+		0x0e: "DDR4E SDRAM",
+		0x0f: "LPDDR3 SDRAM",
+		0x10: "LPDDR4 SDRAM",
+		0x12: "DDR5 SDRAM",
+		0x13: "LPDDR5 SDRAM",
+	}
+	s, ok := m[t]
+	if ok {
+		return s
+	}
+	return "Unknown"
+}
+
+// ParseSPD decodes the SPD contents of DDR3, DDR4 and DDR5 (and their LP variants) modules.
+// The manufacturing information is left empty if data is not long enough to hold it.
+func ParseSPD(data []byte) (*SPD, error) {
+	if len(data) < 3 {
+		return nil, ErrUnpackedDataTooShort
+	}
+
+	spd := &SPD{
+		MemoryType: SPDMemoryType(data[2]),
+	}
+
+	var err error
+	switch spd.MemoryType {
+	case SPDMemoryTypeDDR3, SPDMemoryTypeLPDDR3:
+		err = spd.parseDDR3(data)
+	case SPDMemoryTypeDDR4, SPDMemoryTypeDDR4E, SPDMemoryTypeLPDDR4:
+		err = spd.parseDDR4(data)
+	case SPDMemoryTypeDDR5, SPDMemoryTypeLPDDR5:
+		err = spd.parseDDR5(data)
+	default:
+		return nil, fmt.Errorf("unsupported SPD memory type %s (%#02x)", spd.MemoryType, uint8(spd.MemoryType))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return spd, nil
+}
+
+func (spd *SPD) parseDDR3(data []byte) error {
+	if len(data) < 35 {
+		return ErrUnpackedDataTooShort
+	}
+
+	spd.ModuleType = spdModuleType(data[3]&0x0f, map[uint8]string{
+		0x01: "RDIMM",
+		0x02: "UDIMM",
+		0x03: "SO-DIMM",
+		0x04: "Micro-DIMM",
+		0x05: "Mini-RDIMM",
+		0x06: "Mini-UDIMM",
+		0x07: "Mini-CDIMM",
+		0x08: "72b-SO-UDIMM",
+		0x09: "72b-SO-RDIMM",
+		0x0a: "72b-SO-CDIMM",
+		0x0b: "LRDIMM",
+		0x0c: "16b-SO-DIMM",
+		0x0d: "32b-SO-DIMM",
+	})
+
+	densityMb := uint64(256) << (data[4] & 0x0f)
+	deviceWidth := uint64(4) << (data[7] & 0x07)
+	spd.Ranks = (data[7]>>3)&0x07 + 1
+	spd.BusWidth = 8 << (data[8] & 0x07)
+	spd.ECC = (data[8]>>3)&0x03 == 0x01
+	spd.CapacityMB = densityMb / 8 * uint64(spd.BusWidth) / deviceWidth * uint64(spd.Ranks)
+
+	// medium timebase in ps is dividend / divisor ns, fine timebase in ps is dividend / divisor ps
+	var mtbPS, ftbPS float64
+	if data[11] != 0 {
+		mtbPS = float64(data[10]) * 1000 / float64(data[11])
+	}
+	if data[9]&0x0f != 0 {
+		ftbPS = float64(data[9]>>4) / float64(data[9]&0x0f)
+	}
+	tCKPS := float64(data[12])*mtbPS + float64(int8(data[34]))*ftbPS
+	spd.SpeedMTs = spdSpeedMTs(tCKPS, spd.MemoryType)
+
+	if len(data) >= 146 {
+		spd.parseManufacturing(data, 117, 119, 120, 122, 128, 18)
+	}
+	return nil
+}
+
+func (spd *SPD) parseDDR4(data []byte) error {
+	if len(data) < 126 {
+		return ErrUnpackedDataTooShort
+	}
+
+	spd.ModuleType = spdModuleType(data[3]&0x0f, map[uint8]string{
+		0x01: "RDIMM",
+		0x02: "UDIMM",
+		0x03: "SO-DIMM",
+		0x04: "LRDIMM",
+		0x05: "Mini-RDIMM",
+		0x06: "Mini-UDIMM",
+		0x08: "72b-SO-RDIMM",
+		0x09: "72b-SO-UDIMM",
+		0x0c: "16b-SO-DIMM",
+		0x0d: "32b-SO-DIMM",
+	})
+
+	densityMb := map[uint8]uint64{
+		0x00: 256, 0x01: 512, 0x02: 1024, 0x03: 2048, 0x04: 4096,
+		0x05: 8192, 0x06: 16384, 0x07: 32768, 0x08: 12288, 0x09: 24576,
+	}[data[4]&0x0f]
+
+	deviceWidth := uint64(4) << (data[12] & 0x07)
+	packageRanks := (data[12]>>3)&0x07 + 1
+	spd.Ranks = packageRanks
+	spd.BusWidth = 8 << (data[13] & 0x07)
+	spd.ECC = (data[13]>>3)&0x03 == 0x01
+
+	// 3DS packages (signal loading 10b) hold multiple logical ranks per package rank
+	logicalRanks := uint64(packageRanks)
+	if data[6]&0x03 == 0x02 {
+		logicalRanks *= uint64((data[6]>>4)&0x07) + 1
+	}
+	spd.CapacityMB = densityMb / 8 * uint64(spd.BusWidth) / deviceWidth * logicalRanks
+
+	// only MTB 125 ps and FTB 1 ps are defined
+	tCKPS := float64(data[18])*125 + float64(int8(data[125]))
+	spd.SpeedMTs = spdSpeedMTs(tCKPS, spd.MemoryType)
+
+	if len(data) >= 349 {
+		spd.parseManufacturing(data, 320, 322, 323, 325, 329, 20)
+	}
+	return nil
+}
+
+func (spd *SPD) parseDDR5(data []byte) error {
+	if len(data) < 236 {
+		return ErrUnpackedDataTooShort
+	}
+
+	spd.ModuleType = spdModuleType(data[3]&0x0f, map[uint8]string{
+		0x01: "RDIMM",
+		0x02: "UDIMM",
+		0x03: "SO-DIMM",
+		0x04: "LRDIMM",
+	})
+
+	densityMb := map[uint8]uint64{
+		0x01: 4096, 0x02: 8192, 0x03: 12288, 0x04: 16384,
+		0x05: 24576, 0x06: 32768, 0x07: 49152, 0x08: 65536,
+	}[data[4]&0x1f]
+	diePerPackage := map[uint8]uint64{
+		0x00: 1, 0x02: 2, 0x03: 4, 0x04: 8, 0x05: 16,
+	}[data[4]>>5]
+
+	deviceWidth := uint64(4) << (data[6] >> 5)
+	spd.Ranks = (data[234]>>3)&0x07 + 1
+
+	channels := uint64((data[235]>>5)&0x07) + 1
+	channelBusWidth := uint8(8) << (data[235] & 0x07)
+	spd.BusWidth = uint8(channels) * channelBusWidth
+	spd.ECC = (data[235]>>3)&0x03 != 0x00
+
+	spd.CapacityMB = channels * uint64(channelBusWidth) / deviceWidth * diePerPackage * densityMb / 8 * uint64(spd.Ranks)
+
+	tCKPS, _, _ := unpackUint16L(data, 20)
+	spd.SpeedMTs = spdSpeedMTs(float64(tCKPS), spd.MemoryType)
+
+	if len(data) >= 551 {
+		spd.parseManufacturing(data, 512, 514, 515, 517, 521, 30)
+	}
+	return nil
+}
+
+// parseManufacturing parses the module manufacturing information, which is laid out
+// the same across generations, only differs in the offsets and the part number length.
+func (spd *SPD) parseManufacturing(data []byte, idOffset int, locationOffset int, dateOffset int, serialOffset int, partNumberOffset int, partNumberLen int) {
+	id, _, _ := unpackUint16(data, idOffset)
+	spd.ModuleManufacturerID = id
+	spd.ManufacturingLocation = data[locationOffset]
+
+	year, week := data[dateOffset], data[dateOffset+1]
+	if year != 0x00 && year != 0xff {
+		spd.ManufacturingYear = 2000 + uint16(bcdUint8(year))
+	}
+	if week != 0x00 && week != 0xff {
+		spd.ManufacturingWeek = bcdUint8(week)
+	}
+
+	spd.SerialNumber, _, _ = unpackUint32(data, serialOffset)
+
+	partNumber := data[partNumberOffset : partNumberOffset+partNumberLen]
+	partNumber = bytes.TrimRight(partNumber, " \x00\xff")
+	spd.PartNumber = string(partNumber)
+}
+
+// ModuleManufacturer returns the name of the module manufacturer if known.
+func (spd *SPD) ModuleManufacturer() string {
+	// JEP-106, the continuation code count byte and the code byte are both with odd parity
+	m := map[uint16]string{
+		0x802c: "Micron",
+		0x80ad: "SK Hynix",
+		0x80c1: "Infineon",
+		0x80ce: "Samsung",
+		0x80fe: "Elpida",
+		0x0198: "Kingston",
+	}
+	s, ok := m[spd.ModuleManufacturerID]
+	if ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown (%#04x)", spd.ModuleManufacturerID)
+}
+
+// ManufacturingDate returns the manufacturing date in "YYYY-Www" format.
+func (spd *SPD) ManufacturingDate() string {
+	if spd.ManufacturingYear == 0 {
+		return "Unspecified"
+	}
+	return fmt.Sprintf("%d-W%02d", spd.ManufacturingYear, spd.ManufacturingWeek)
+}
+
+func (spd *SPD) String() string {
+	var lines = []string{
+		fmt.Sprintf("  Memory Type          : %s", spd.MemoryType),
+		fmt.Sprintf("  Module Type          : %s", spd.ModuleType),
+		fmt.Sprintf("  Memory Size          : %d MB", spd.CapacityMB),
+		fmt.Sprintf("  Memory Speed         : %d MT/s", spd.SpeedMTs),
+		fmt.Sprintf("  Number of Ranks      : %d", spd.Ranks),
+		fmt.Sprintf("  Bus Width            : %d bits%s", spd.BusWidth, formatBool(spd.ECC, " with ECC", "")),
+		fmt.Sprintf("  Manufacturer         : %s", spd.ModuleManufacturer()),
+		fmt.Sprintf("  Manufacture Date     : %s", spd.ManufacturingDate()),
+		fmt.Sprintf("  Serial Number        : %08X", spd.SerialNumber),
+		fmt.Sprintf("  Part Number          : %s", spd.PartNumber),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func spdModuleType(t uint8, m map[uint8]string) string {
+	s, ok := m[t]
+	if ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown (%#02x)", t)
+}
+
+// spdSpeedMTs converts the min cycle time to the max data rate, and rounds
+// it to the standard speed bin of the memory type if close enough.
+func spdSpeedMTs(tCKPS float64, memoryType SPDMemoryType) uint32 {
+	if tCKPS <= 0 {
+		return 0
+	}
+
+	// double data rate
+	rate := uint32(2*1000000/tCKPS + 0.5)
+
+	var bins []uint32
+	switch memoryType {
+	case SPDMemoryTypeDDR3, SPDMemoryTypeLPDDR3:
+		bins = []uint32{800, 1066, 1333, 1600, 1866, 2133}
+	case SPDMemoryTypeDDR4, SPDMemoryTypeDDR4E, SPDMemoryTypeLPDDR4:
+		bins = []uint32{1600, 1866, 2133, 2400, 2666, 2933, 3200}
+	case SPDMemoryTypeDDR5, SPDMemoryTypeLPDDR5:
+		bins = []uint32{3200, 3600, 4000, 4400, 4800, 5200, 5600, 6000, 6400, 6800, 7200, 7600, 8000, 8400, 8800}
+	}
+	for _, bin := range bins {
+		if rate+10 >= bin && rate <= bin+10 {
+			return bin
+		}
+	}
+	return rate
+}
@@ -0,0 +1,81 @@
+package ipmi
+
+import (
+	"testing"
+)
+
+func Test_ParseSPD(t *testing.T) {
+	ddr3 := make([]byte, 256)
+	copy(ddr3[0:], []byte{0x92, 0x10, 0x0b, 0x02, 0x04, 0x19, 0x00, 0x09, 0x03, 0x11, 0x01, 0x08, 0x0a})
+	copy(ddr3[117:], []byte{0x01, 0x98, 0x01, 0x14, 0x32, 0x00, 0x00, 0x00, 0x2a})
+	copy(ddr3[128:], "KVR16N11/8       ")
+
+	ddr4 := make([]byte, 512)
+	copy(ddr4[0:], []byte{0x23, 0x11, 0x0c, 0x01, 0x45, 0x21, 0x00})
+	ddr4[12], ddr4[13], ddr4[18] = 0x09, 0x0b, 0x06
+	copy(ddr4[320:], []byte{0x80, 0xce, 0x02, 0x20, 0x15, 0x12, 0x34, 0x56, 0x78})
+	copy(ddr4[329:], "M393A2K40BB2-CTD    ")
+
+	ddr5 := make([]byte, 1024)
+	copy(ddr5[0:], []byte{0x30, 0x10, 0x12, 0x01, 0x04, 0x00, 0x20})
+	ddr5[20], ddr5[21] = 0xa0, 0x01 // 416 ps
+	ddr5[234], ddr5[235] = 0x08, 0x32
+	copy(ddr5[512:], []byte{0x80, 0x2c, 0x01, 0x23, 0x07, 0xde, 0xad, 0xbe, 0xef})
+	copy(ddr5[521:], "MTC20F2085S1RC48BA1")
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected SPD
+	}{
+		{
+			name: "DDR3 UDIMM",
+			data: ddr3,
+			expected: SPD{
+				MemoryType: SPDMemoryTypeDDR3, ModuleType: "UDIMM", CapacityMB: 8192, SpeedMTs: 1600, Ranks: 2, BusWidth: 64,
+				ModuleManufacturerID: 0x0198, ManufacturingLocation: 0x01, ManufacturingYear: 2014, ManufacturingWeek: 32,
+				SerialNumber: 0x2a, PartNumber: "KVR16N11/8",
+			},
+		},
+		{
+			name: "DDR3 without manufacturing information",
+			data: ddr3[:128],
+			expected: SPD{
+				MemoryType: SPDMemoryTypeDDR3, ModuleType: "UDIMM", CapacityMB: 8192, SpeedMTs: 1600, Ranks: 2, BusWidth: 64,
+			},
+		},
+		{
+			name: "DDR4 RDIMM",
+			data: ddr4,
+			expected: SPD{
+				MemoryType: SPDMemoryTypeDDR4, ModuleType: "RDIMM", CapacityMB: 16384, SpeedMTs: 2666, Ranks: 2, BusWidth: 64, ECC: true,
+				ModuleManufacturerID: 0x80ce, ManufacturingLocation: 0x02, ManufacturingYear: 2020, ManufacturingWeek: 15,
+				SerialNumber: 0x12345678, PartNumber: "M393A2K40BB2-CTD",
+			},
+		},
+		{
+			name: "DDR5 RDIMM",
+			data: ddr5,
+			expected: SPD{
+				MemoryType: SPDMemoryTypeDDR5, ModuleType: "RDIMM", CapacityMB: 32768, SpeedMTs: 4800, Ranks: 2, BusWidth: 64, ECC: true,
+				ModuleManufacturerID: 0x802c, ManufacturingLocation: 0x01, ManufacturingYear: 2023, ManufacturingWeek: 7,
+				SerialNumber: 0xdeadbeef, PartNumber: "MTC20F2085S1RC48BA1",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		spd, err := ParseSPD(test.data)
+		if err != nil {
+			t.Errorf("test %s failed, err: %s", test.name, err)
+			continue
+		}
+		if *spd != test.expected {
+			t.Errorf("test %s not matched\ngot:      %+v\nexpected: %+v", test.name, *spd, test.expected)
+		}
+	}
+
+	if _, err := ParseSPD([]byte{0x80, 0x08, 0x08}); err == nil {
+		t.Errorf("DDR2 SPD expected unsupported error")
+	}
+}
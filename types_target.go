@@ -0,0 +1,45 @@
+package ipmi
+
+import "fmt"
+
+// target addresses the controller and the LUN which requests are sent to.
+//
+// The zero value addresses LUN 0 of the BMC. A controller other than
+// the BMC (like satellite management controllers on IPMB) is reached
+// by bridging the requests through the channel of the BMC.
+type target struct {
+	// 8-bit form of the slave address (the 7-bit address in bits [7:1])
+	addr    uint8
+	channel uint8
+	lun     uint8
+}
+
+func (t target) String() string {
+	return fmt.Sprintf("addr: %#02x, channel: %d, lun: %d", t.addr, t.channel, t.lun)
+}
+
+// bridged returns whether the requests to the target need to be bridged by the BMC.
+func (t target) bridged() bool {
+	return t.addr != 0 && t.addr != BMC_SA
+}
+
+// withTarget makes the requests issued by fn addressed to the LUN of the controller
+// at the slave address on the channel, and restores the previous target after fn returns.
+//
+// A zero addr means the BMC.
+func (c *Client) withTarget(addr uint8, channel uint8, lun uint8, fn func() error) error {
+	old := c.target
+	c.target = target{
+		addr:    addr,
+		channel: channel,
+		lun:     lun & 0x03,
+	}
+	defer func() {
+		c.target = old
+	}()
+
+	if c.target != old {
+		c.Debugf("Switch target to (%s)\n", c.target)
+	}
+	return fn()
+}
@@ -1,30 +1,82 @@
 package ipmi
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/net/proxy"
 )
 
+// IPPreference controls which IP address family is used when the host of UDPClient
+// is a hostname resolving to both IPv4 and IPv6 addresses.
+type IPPreference uint8
+
+const (
+	// IPPreferenceAny uses the first address in the order returned by the resolver.
+	// If the target has not replied on it after happyEyeballsDelay, the request is also
+	// sent to the first address of the other family, and the address replying first is used
+	// (like the Happy Eyeballs of RFC 8305).
+	IPPreferenceAny IPPreference = iota
+	IPPreferenceIPv4
+	IPPreferenceIPv6
+)
+
+func (p IPPreference) String() string {
+	switch p {
+	case IPPreferenceIPv4:
+		return "ipv4"
+	case IPPreferenceIPv6:
+		return "ipv6"
+	}
+	return "any"
+}
+
+// network returns the network name used to dial or resolve.
+func (p IPPreference) network() string {
+	switch p {
+	case IPPreferenceIPv4:
+		return "udp4"
+	case IPPreferenceIPv6:
+		return "udp6"
+	}
+	return "udp"
+}
+
 // UDPClient exposes some common methods for communicating with UDP target addr.
 type UDPClient struct {
-	// Target Host
+	// Target Host, can be a hostname, an IPv4 address or an IPv6 address.
+	// IPv6 link-local address can be zone-scoped, like "fe80::1%eth0",
+	// and the address can be enclosed in square brackets, like "[fe80::1%eth0]".
 	Host string
 	// Target Port
 	Port int
 
-	proxy      proxy.Dialer
-	timeout    time.Duration
-	bufferSize int
+	proxy        proxy.Dialer
+	timeout      time.Duration
+	bufferSize   int
+	ipPreference IPPreference
 
 	conn net.Conn
+
+	// the resolved addresses of the target to try in order,
+	// and the index of the one the conn is dialed to.
+	remoteAddrs []*net.UDPAddr
+	remoteIndex int
 }
 
+// happyEyeballsDelay is the delay before the request is also sent to the next address
+// of the target, see RFC 8305 Section 5, Connection Attempt Delay.
+var happyEyeballsDelay = 250 * time.Millisecond
+
+// lookupIPAddr resolves the hostname of the target, it is replaced in tests.
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
 func NewUDPClient(host string, port int) *UDPClient {
 	udpClient := &UDPClient{
 		Host: host,
@@ -39,26 +91,112 @@ func (c *UDPClient) initConn() error {
 	}
 
 	if c.proxy != nil {
-		conn, err := c.proxy.Dial("udp", fmt.Sprintf("%s:%d", c.Host, c.Port))
+		conn, err := c.proxy.Dial("udp", c.proxyAddress())
 		if err != nil {
 			return fmt.Errorf("proxy dail failed, err: %s", err)
 		}
 		c.conn = conn
 	} else {
-		remoteAddr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("%s:%d", c.Host, c.Port))
-		if err != nil {
-			return fmt.Errorf("resolve addr failed, err: %s", err)
+		if err := c.initRemoteAddrs(); err != nil {
+			return err
 		}
-		conn, err := net.DialUDP("udp", nil, remoteAddr)
-		if err != nil {
-			return fmt.Errorf("dial failed, err: %s", err)
+
+		var dialErr error
+		for ; c.remoteIndex < len(c.remoteAddrs); c.remoteIndex++ {
+			conn, err := net.DialUDP(c.ipPreference.network(), nil, c.remoteAddrs[c.remoteIndex])
+			if err != nil {
+				dialErr = err
+				continue
+			}
+			c.conn = conn
+			return nil
 		}
-		c.conn = conn
+		// resolve again on next try
+		c.remoteAddrs = nil
+		return fmt.Errorf("dial failed, err: %s", dialErr)
 	}
 
 	return nil
 }
 
+// initRemoteAddrs resolves the addresses of the target if not resolved yet.
+func (c *UDPClient) initRemoteAddrs() error {
+	if c.remoteAddrs != nil {
+		return nil
+	}
+	remoteAddrs, err := c.resolveAddrs()
+	if err != nil {
+		return fmt.Errorf("resolve addr failed, err: %s", err)
+	}
+	c.remoteAddrs = remoteAddrs
+	c.remoteIndex = 0
+	return nil
+}
+
+// host returns the target host without the enclosing square brackets.
+func (c *UDPClient) host() string {
+	return strings.TrimSuffix(strings.TrimPrefix(c.Host, "["), "]")
+}
+
+// address returns the "host:port" address of the target, IPv6 address is enclosed in square brackets.
+func (c *UDPClient) address() string {
+	return net.JoinHostPort(c.host(), strconv.Itoa(c.Port))
+}
+
+// proxyAddress returns the address passed to the proxy. The hostname is resolved locally
+// only if an IP family is preferred, otherwise the proxy resolves it.
+func (c *UDPClient) proxyAddress() string {
+	if c.ipPreference == IPPreferenceAny {
+		return c.address()
+	}
+	return net.JoinHostPort(c.RemoteIP(), strconv.Itoa(c.Port))
+}
+
+func (c *UDPClient) resolveAddr() (*net.UDPAddr, error) {
+	return net.ResolveUDPAddr(c.ipPreference.network(), net.JoinHostPort(c.RemoteIP(), strconv.Itoa(c.Port)))
+}
+
+// resolveAddrs returns the addresses of the target to try in order.
+// If no IP family is preferred and the hostname resolves to both IPv4 and IPv6 addresses,
+// the first address and the first address of the other family are returned.
+func (c *UDPClient) resolveAddrs() ([]*net.UDPAddr, error) {
+	host := c.host()
+	if c.ipPreference != IPPreferenceAny || isIPHost(host) {
+		remoteAddr, err := c.resolveAddr()
+		if err != nil {
+			return nil, err
+		}
+		return []*net.UDPAddr{remoteAddr}, nil
+	}
+
+	addrs, err := lookupIPAddr(context.Background(), host)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*net.UDPAddr, 0, 2)
+	for _, addr := range addrs {
+		if len(out) == 1 && (addr.IP.To4() != nil) == (out[0].IP.To4() != nil) {
+			continue
+		}
+		out = append(out, &net.UDPAddr{IP: addr.IP, Port: c.Port, Zone: addr.Zone})
+		if len(out) == 2 {
+			break
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no address found for host (%s)", host)
+	}
+	return out, nil
+}
+
+// isIPHost returns whether the host is an IP address, the zone of IPv6 address is allowed.
+func isIPHost(host string) bool {
+	if i := strings.Index(host, "%"); i >= 0 {
+		host = host[:i]
+	}
+	return net.ParseIP(host) != nil
+}
+
 func (c *UDPClient) SetProxy(proxy proxy.Dialer) *UDPClient {
 	c.proxy = proxy
 	return c
@@ -74,19 +212,49 @@ func (c *UDPClient) SetBufferSize(bufferSize int) *UDPClient {
 	return c
 }
 
+func (c *UDPClient) SetIPPreference(ipPreference IPPreference) *UDPClient {
+	c.ipPreference = ipPreference
+	return c
+}
+
 // RemoteIP returns the parsed ip address of the target.
+// If the host is a hostname, the address the connection is dialed to is returned,
+// or the first resolved address matching the IP preference before connected.
+// The zone of IPv6 link-local address is kept, like "fe80::1%eth0".
 func (c *UDPClient) RemoteIP() string {
-	if net.ParseIP(c.Host) == nil {
-		addrs, err := net.LookupHost(c.Host)
-		if err == nil && len(addrs) > 0 {
-			return addrs[0]
+	host := c.host()
+	if isIPHost(host) {
+		return host
+	}
+
+	if c.conn != nil && c.remoteIndex < len(c.remoteAddrs) {
+		remoteAddr := c.remoteAddrs[c.remoteIndex]
+		return (&net.IPAddr{IP: remoteAddr.IP, Zone: remoteAddr.Zone}).String()
+	}
+
+	addrs, err := lookupIPAddr(context.Background(), host)
+	if err != nil {
+		return host
+	}
+	for _, addr := range addrs {
+		isIPv4 := addr.IP.To4() != nil
+		switch {
+		case c.ipPreference == IPPreferenceIPv4 && !isIPv4,
+			c.ipPreference == IPPreferenceIPv6 && isIPv4:
+			continue
 		}
+		return addr.String()
 	}
-	return c.Host
+	return host
 }
 
+// LocalIPPort returns the local ip and port used to communicate with the target.
 func (c *UDPClient) LocalIPPort() (string, int) {
-	conn, err := net.Dial("udp", fmt.Sprintf("%s:%d", c.Host, c.Port))
+	remoteAddr, err := c.resolveAddr()
+	if err != nil {
+		return "", 0
+	}
+	conn, err := net.DialUDP(c.ipPreference.network(), nil, remoteAddr)
 	if err != nil {
 		return "", 0
 	}
@@ -105,34 +273,171 @@ func (c *UDPClient) Close() error {
 
 // Exchange performs a synchronous UDP query.
 // It sends the request, and waits for a reply.
-// Exchange does not retry a failed query.
+// Exchange does not retry a failed query. Before connected, if the hostname of the target
+// resolves to addresses of both families, the query is raced on them, see happyEyeballs.
 // The sent content is read from reader.
 func (c *UDPClient) Exchange(ctx context.Context, reader io.Reader) ([]byte, error) {
+	if c.conn == nil && c.proxy == nil {
+		if err := c.initRemoteAddrs(); err != nil {
+			return nil, fmt.Errorf("init udp connection failed, err: %s", err)
+		}
+		if len(c.remoteAddrs) > 1 {
+			sent, err := io.ReadAll(reader)
+			if err != nil {
+				return nil, fmt.Errorf("read sent content failed, err: %s", err)
+			}
+			return c.happyEyeballs(ctx, sent)
+		}
+	}
+
+	return c.exchange(ctx, reader)
+}
+
+// happyEyeballs sends the query to the first address of the target, and to the next address
+// if no reply is received after happyEyeballsDelay or the previous address failed.
+// The connection of the address replying first is kept, the others are closed.
+func (c *UDPClient) happyEyeballs(ctx context.Context, sent []byte) ([]byte, error) {
+	type result struct {
+		index int
+		recv  []byte
+		err   error
+	}
+
+	conns := make([]net.Conn, len(c.remoteAddrs))
+	closeConns := func(keep int) {
+		for i, conn := range conns {
+			if conn != nil && i != keep {
+				conn.Close()
+			}
+		}
+	}
+
+	results := make(chan result, len(conns))
+	next, pending := 0, 0
+	var lastErr error
+	startNext := func() {
+		for next < len(conns) {
+			i := next
+			next++
+			conn, err := net.DialUDP(c.ipPreference.network(), nil, c.remoteAddrs[i])
+			if err != nil {
+				lastErr = fmt.Errorf("dial failed, err: %s", err)
+				continue
+			}
+			conns[i] = conn
+			pending++
+			go func() {
+				recv, err := c.roundTrip(conn, bytes.NewReader(sent))
+				results <- result{index: i, recv: recv, err: err}
+			}()
+			return
+		}
+	}
+
+	startNext()
+	timer := time.NewTimer(happyEyeballsDelay)
+	defer timer.Stop()
+
+	for pending > 0 {
+		select {
+		case <-ctx.Done():
+			closeConns(-1)
+			return nil, fmt.Errorf("canceled from caller")
+		case <-timer.C:
+			startNext()
+			if next < len(conns) {
+				timer.Reset(happyEyeballsDelay)
+			}
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				closeConns(r.index)
+				c.conn = conns[r.index]
+				c.remoteIndex = r.index
+				return r.recv, nil
+			}
+			lastErr = r.err
+			startNext()
+		}
+	}
+
+	closeConns(-1)
+	// resolve again on next try
+	c.remoteAddrs = nil
+	return nil, lastErr
+}
+
+func (c *UDPClient) exchange(ctx context.Context, reader io.Reader) ([]byte, error) {
 	if err := c.initConn(); err != nil {
 		return nil, fmt.Errorf("init udp connection failed, err: %s", err)
 	}
 
-	recvBuffer := make([]byte, c.bufferSize)
-
 	doneChan := make(chan error, 1)
-	recvChan := make(chan int, 1)
+	recvChan := make(chan []byte, 1)
 	go func() {
-		// It is possible that this action blocks, although this
-		// should only occur in very resource-intensive situations:
-		// - when you've filled up the socket buffer and the OS
-		//   can't dequeue the queue fast enough.
-		_, err := io.Copy(c.conn, reader)
+		recv, err := c.roundTrip(c.conn, reader)
 		if err != nil {
-			doneChan <- fmt.Errorf("write to conn failed, err: %s", err)
+			doneChan <- err
 			return
 		}
 
-		// Set a deadline for the ReadOperation so that we don't
-		// wait forever for a server that might not respond on
-		// a resonable amount of time.
-		deadline := time.Now().Add(c.timeout)
-		err = c.conn.SetReadDeadline(deadline)
+		doneChan <- nil
+		recvChan <- recv
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("canceled from caller")
+	case err := <-doneChan:
 		if err != nil {
+			return nil, err
+		}
+		return <-recvChan, nil
+	}
+}
+
+// roundTrip writes the request to conn and reads a reply.
+func (c *UDPClient) roundTrip(conn net.Conn, reader io.Reader) ([]byte, error) {
+	// It is possible that this action blocks, although this
+	// should only occur in very resource-intensive situations:
+	// - when you've filled up the socket buffer and the OS
+	//   can't dequeue the queue fast enough.
+	_, err := io.Copy(conn, reader)
+	if err != nil {
+		return nil, fmt.Errorf("write to conn failed, err: %s", err)
+	}
+
+	// Set a deadline for the ReadOperation so that we don't
+	// wait forever for a server that might not respond on
+	// a resonable amount of time.
+	deadline := time.Now().Add(c.timeout)
+	err = conn.SetReadDeadline(deadline)
+	if err != nil {
+		return nil, fmt.Errorf("set conn read deadline failed, err: %s", err)
+	}
+
+	recvBuffer := make([]byte, c.bufferSize)
+	nRead, err := conn.Read(recvBuffer)
+	if err != nil {
+		return nil, fmt.Errorf("read from conn failed, err: %s", err)
+	}
+	return recvBuffer[:nRead], nil
+}
+
+// Receive waits for the next message from the target, it is used when
+// the target replies more than one message for a request, like bridged requests.
+func (c *UDPClient) Receive(ctx context.Context) ([]byte, error) {
+	if c.conn == nil {
+		return nil, fmt.Errorf("udp connection not initialized")
+	}
+
+	recvBuffer := make([]byte, c.bufferSize)
+
+	doneChan := make(chan error, 1)
+	recvChan := make(chan int, 1)
+	go func() {
+		deadline := time.Now().Add(c.timeout)
+		if err := c.conn.SetReadDeadline(deadline); err != nil {
 			doneChan <- fmt.Errorf("set conn read deadline failed, err: %s", err)
 			return
 		}
@@ -0,0 +1,191 @@
+package ipmi
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_UDPClient_Address(t *testing.T) {
+	tests := []struct {
+		host             string
+		port             int
+		expectedAddress  string
+		expectedRemoteIP string
+	}{
+		{"10.0.0.1", 623, "10.0.0.1:623", "10.0.0.1"},
+		{"2001:db8::1", 623, "[2001:db8::1]:623", "2001:db8::1"},
+		{"[2001:db8::1]", 6230, "[2001:db8::1]:6230", "2001:db8::1"},
+		{"fe80::1%eth0", 623, "[fe80::1%eth0]:623", "fe80::1%eth0"},
+		{"[fe80::1%eth0]", 623, "[fe80::1%eth0]:623", "fe80::1%eth0"},
+	}
+
+	for _, test := range tests {
+		c := NewUDPClient(test.host, test.port)
+		if got := c.address(); got != test.expectedAddress {
+			t.Errorf("address of (%s) not matched, got: %s, expected: %s", test.host, got, test.expectedAddress)
+		}
+		if got := c.RemoteIP(); got != test.expectedRemoteIP {
+			t.Errorf("remote ip of (%s) not matched, got: %s, expected: %s", test.host, got, test.expectedRemoteIP)
+		}
+	}
+}
+
+func Test_UDPClient_Fallback(t *testing.T) {
+	// only the IPv4 address of the target replies
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("listen failed, err: %s", err)
+	}
+	defer conn.Close()
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteToUDP(buf[:n], addr)
+		}
+	}()
+
+	lookup := lookupIPAddr
+	defer func() { lookupIPAddr = lookup }()
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{
+			{IP: net.ParseIP("::1")},
+			{IP: net.ParseIP("::2")},
+			{IP: net.IPv4(127, 0, 0, 1)},
+		}, nil
+	}
+
+	tests := []struct {
+		name             string
+		ipPreference     IPPreference
+		expectedRemoteIP string
+		expectedErr      bool
+	}{
+		{"any", IPPreferenceAny, "127.0.0.1", false},
+		{"ipv6", IPPreferenceIPv6, "::1", true},
+	}
+
+	for _, test := range tests {
+		c := NewUDPClient("bmc.example", conn.LocalAddr().(*net.UDPAddr).Port)
+		c.SetTimeout(200 * time.Millisecond).SetBufferSize(1024).SetIPPreference(test.ipPreference)
+
+		recv, err := c.Exchange(context.Background(), bytes.NewReader([]byte("ping")))
+		if test.expectedErr {
+			if err == nil {
+				t.Errorf("test %s: exchange should fail", test.name)
+			}
+		} else if err != nil {
+			t.Errorf("test %s: exchange failed, err: %s", test.name, err)
+		} else if string(recv) != "ping" {
+			t.Errorf("test %s: reply not matched, got: %s, expected: %s", test.name, recv, "ping")
+		}
+		if got := c.RemoteIP(); got != test.expectedRemoteIP {
+			t.Errorf("test %s: remote ip not matched, got: %s, expected: %s", test.name, got, test.expectedRemoteIP)
+		}
+		c.Close()
+	}
+}
+
+func Test_UDPClient_HappyEyeballs(t *testing.T) {
+	v4, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("listen failed, err: %s", err)
+	}
+	defer v4.Close()
+	port := v4.LocalAddr().(*net.UDPAddr).Port
+	v6, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback, Port: port})
+	if err != nil {
+		t.Skipf("ipv6 loopback not available, err: %s", err)
+	}
+	defer v6.Close()
+
+	// serve records the address each request is received on, and echoes it if reply is set
+	serve := func(conn *net.UDPConn, reply bool, received chan<- string) {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			received <- conn.LocalAddr().(*net.UDPAddr).IP.String()
+			if reply {
+				conn.WriteToUDP(buf[:n], addr)
+			}
+		}
+	}
+
+	lookup := lookupIPAddr
+	defer func() { lookupIPAddr = lookup }()
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{
+			{IP: net.IPv6loopback},
+			{IP: net.IPv4(127, 0, 0, 1)},
+		}, nil
+	}
+
+	tests := []struct {
+		name             string
+		v6Reply          bool
+		expectedRemoteIP string
+		expectedReceived []string
+	}{
+		// the IPv6 address black-holes, the IPv4 address is tried after happyEyeballsDelay
+		{"ipv6 silent", false, "127.0.0.1", []string{"::1", "127.0.0.1"}},
+		// the IPv6 address replies within happyEyeballsDelay, the IPv4 address is never tried
+		{"ipv6 replies", true, "::1", []string{"::1"}},
+	}
+
+	for _, test := range tests {
+		received := make(chan string, 10)
+		v4.SetReadDeadline(time.Time{})
+		v6.SetReadDeadline(time.Time{})
+		go serve(v4, true, received)
+		go serve(v6, test.v6Reply, received)
+
+		c := NewUDPClient("bmc.example", port)
+		c.SetTimeout(5 * time.Second).SetBufferSize(1024)
+
+		start := time.Now()
+		recv, err := c.Exchange(context.Background(), bytes.NewReader([]byte("ping")))
+		elapsed := time.Since(start)
+		if err != nil {
+			t.Errorf("test %s: exchange failed, err: %s", test.name, err)
+		} else if string(recv) != "ping" {
+			t.Errorf("test %s: reply not matched, got: %s, expected: %s", test.name, recv, "ping")
+		}
+		if elapsed >= time.Second {
+			t.Errorf("test %s: exchange expected to not wait for the timeout, took: %s", test.name, elapsed)
+		}
+		if got := c.RemoteIP(); got != test.expectedRemoteIP {
+			t.Errorf("test %s: remote ip not matched, got: %s, expected: %s", test.name, got, test.expectedRemoteIP)
+		}
+
+		// the later queries are sent on the kept connection only
+		if _, err := c.Exchange(context.Background(), bytes.NewReader([]byte("ping"))); err != nil {
+			t.Errorf("test %s: second exchange failed, err: %s", test.name, err)
+		}
+		c.Close()
+
+		// stop the servers
+		v4.SetReadDeadline(time.Now())
+		v6.SetReadDeadline(time.Now())
+		time.Sleep(50 * time.Millisecond)
+		close(received)
+
+		got := []string{}
+		for ip := range received {
+			got = append(got, ip)
+		}
+		expected := append(test.expectedReceived, test.expectedRemoteIP)
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("test %s: received not matched, got: %v, expected: %v", test.name, got, expected)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WatchdogDaemonConfig is the configuration of WatchdogDaemon.
+type WatchdogDaemonConfig struct {
+	TimerUse      TimerUse
+	TimeoutAction TimeoutAction
+	// Timeout is the initial countdown of the watchdog timer, in 100 ms resolution.
+	Timeout time.Duration
+	// Interval is how often the watchdog timer is reset, it must be less than Timeout.
+	// Defaults to one third of Timeout.
+	Interval time.Duration
+
+	PreTimeoutInterrupt PreTimeoutInterrupt
+	// PreTimeout is the interval before the timeout at which the pre-timeout interrupt is raised,
+	// in seconds resolution.
+	PreTimeout time.Duration
+
+	DontLog bool
+
+	// OnExpired is called when the daemon starts if the expiration flags
+	// show the watchdog timer already expired, e.g. the host was reset by the watchdog.
+	// The flags are cleared when the daemon arms the timer.
+	OnExpired func(timerUses []TimerUse)
+
+	// OnPreTimeout is called when the BMC reports the watchdog pre-timeout interrupt occurred,
+	// or when the present countdown is found below the pre-timeout interval.
+	OnPreTimeout func(presentCountdown time.Duration)
+	// PreTimeoutPollInterval is how often the pre-timeout is checked if OnPreTimeout is set,
+	// independent of the reset Interval. Defaults to one second.
+	PreTimeoutPollInterval time.Duration
+
+	// OnError is called with the errors the daemon keeps running on, like a failed reset
+	// of the watchdog timer or a failed check of the pre-timeout.
+	// If it is nil, Run returns on these errors without disarming the timer.
+	OnError func(err error)
+}
+
+// WatchdogDaemon arms the BMC watchdog timer and periodically resets it,
+// so the BMC takes the timeout action when the host hangs and stops resetting it.
+// It is meant to run with the open interface on the managed host,
+// like FreeIPMI's bmc-watchdog.
+type WatchdogDaemon struct {
+	client *Client
+	config WatchdogDaemonConfig
+
+	initialCountdown uint16
+	preTimeoutSec    uint8
+}
+
+func NewWatchdogDaemon(client *Client, config WatchdogDaemonConfig) (*WatchdogDaemon, error) {
+	if config.TimerUse == 0 {
+		config.TimerUse = TimerUseSMSOS
+	}
+	if config.Interval == 0 {
+		config.Interval = config.Timeout / 3
+	}
+	if config.PreTimeoutPollInterval == 0 {
+		config.PreTimeoutPollInterval = time.Second
+	}
+
+	initialCountdown, err := WatchdogCountdown(config.Timeout.Truncate(100 * time.Millisecond))
+	if err != nil {
+		return nil, err
+	}
+	if config.Interval <= 0 || config.Interval >= config.Timeout {
+		return nil, fmt.Errorf("watchdog reset interval (%s) must be less than the timeout (%s)", config.Interval, config.Timeout)
+	}
+
+	if config.PreTimeoutPollInterval < 0 {
+		return nil, fmt.Errorf("watchdog pre-timeout poll interval (%s) must be positive", config.PreTimeoutPollInterval)
+	}
+
+	preTimeoutSec := config.PreTimeout / time.Second
+	if preTimeoutSec > 0xff {
+		return nil, fmt.Errorf("watchdog pre-timeout (%s) too long, max 255s", config.PreTimeout)
+	}
+
+	d := &WatchdogDaemon{
+		client:           client,
+		config:           config,
+		initialCountdown: initialCountdown,
+		preTimeoutSec:    uint8(preTimeoutSec),
+	}
+
+	if err := d.setRequest(0).Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *WatchdogDaemon) setRequest(expirationFlags uint8) *SetWatchdogTimerRequest {
+	return &SetWatchdogTimerRequest{
+		DontLog:              d.config.DontLog,
+		DontStopTimer:        false,
+		TimerUse:             d.config.TimerUse,
+		PreTimeoutInterrupt:  d.config.PreTimeoutInterrupt,
+		TimeoutAction:        d.config.TimeoutAction,
+		PreTimeoutIntevalSec: d.preTimeoutSec,
+		ExpirationFlags:      expirationFlags,
+		InitialCountdown:     d.initialCountdown,
+	}
+}
+
+// Arm reports the already expired timer, then sets and starts the watchdog timer.
+func (d *WatchdogDaemon) Arm() error {
+	current, err := d.client.GetWatchdogTimer()
+	if err != nil {
+		return fmt.Errorf("GetWatchdogTimer failed, err: %s", err)
+	}
+
+	expirationFlags := current.ExpirationFlags & WatchdogExpirationFlagsMask
+	if expirationFlags != 0 {
+		d.client.Debugf("watchdog timer expired before: %s\n", formatWatchdogExpirationFlags(expirationFlags))
+		if d.config.OnExpired != nil {
+			d.config.OnExpired(ExpiredTimerUses(expirationFlags))
+		}
+	}
+
+	if _, err := d.client.SetWatchdogTimer(d.setRequest(expirationFlags)); err != nil {
+		return fmt.Errorf("SetWatchdogTimer failed, err: %s", err)
+	}
+	if _, err := d.client.ResetWatchdogTimer(); err != nil {
+		return fmt.Errorf("ResetWatchdogTimer failed, err: %s", err)
+	}
+	return nil
+}
+
+// Disarm stops the watchdog timer, so the host is not affected after the daemon exits.
+func (d *WatchdogDaemon) Disarm() error {
+	request := d.setRequest(WatchdogExpirationFlag(d.config.TimerUse))
+	request.TimeoutAction = TimeoutActionNoAction
+	request.PreTimeoutInterrupt = PreTimeoutInterruptNone
+	request.PreTimeoutIntevalSec = 0
+	if _, err := d.client.SetWatchdogTimer(request); err != nil {
+		return fmt.Errorf("SetWatchdogTimer failed, err: %s", err)
+	}
+	return nil
+}
+
+// Run arms the watchdog timer and resets it every Interval until ctx is done,
+// then disarms the timer. Cancel ctx on SIGTERM for a clean shutdown.
+// If the daemon is killed without disarming, the BMC takes the timeout action.
+//
+// The pre-timeout is checked every PreTimeoutPollInterval if OnPreTimeout is set.
+// The failures of resetting the timer and checking the pre-timeout are passed to OnError,
+// or returned if OnError is not set.
+func (d *WatchdogDaemon) Run(ctx context.Context) error {
+	if err := d.Arm(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	var preTimeoutC <-chan time.Time
+	if d.config.OnPreTimeout != nil && d.config.PreTimeoutInterrupt != PreTimeoutInterruptNone {
+		preTimeoutTicker := time.NewTicker(d.config.PreTimeoutPollInterval)
+		defer preTimeoutTicker.Stop()
+		preTimeoutC = preTimeoutTicker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := d.Disarm(); err != nil {
+				return fmt.Errorf("disarm watchdog timer failed, err: %s", err)
+			}
+			return nil
+
+		case <-preTimeoutC:
+			if err := d.checkPreTimeout(); err != nil {
+				if err := d.report(err); err != nil {
+					return err
+				}
+			}
+
+		case <-ticker.C:
+			if err := d.reset(); err != nil {
+				// the hung host is exactly what the timeout action is for,
+				// but a transient failure should not stop the resetting if OnError is set.
+				if err := d.report(err); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// report passes the error to OnError, or returns it if OnError is not set.
+func (d *WatchdogDaemon) report(err error) error {
+	if d.config.OnError == nil {
+		return err
+	}
+	d.config.OnError(err)
+	return nil
+}
+
+// reset resets the watchdog timer, or arms it again if it is un-initialized.
+func (d *WatchdogDaemon) reset() error {
+	_, err := d.client.ResetWatchdogTimer()
+	if err == nil {
+		return nil
+	}
+
+	// cc 0x80 means the timer was re-initialized by others (or the BMC was reset), arm it again.
+	if isResponseErrorCC(err, 0x80) {
+		d.client.Debugf("watchdog timer un-initialized, arm it again\n")
+		if err := d.Arm(); err != nil {
+			return fmt.Errorf("arm watchdog timer again failed, err: %s", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("ResetWatchdogTimer failed, err: %s", err)
+}
+
+// checkPreTimeout calls OnPreTimeout if the pre-timeout is reached.
+func (d *WatchdogDaemon) checkPreTimeout() error {
+	current, err := d.client.GetWatchdogTimer()
+	if err != nil {
+		return fmt.Errorf("GetWatchdogTimer failed, err: %s", err)
+	}
+	presentCountdown := WatchdogCountdownDuration(current.PresentCountdown)
+
+	occurred := current.TimerIsStarted && presentCountdown <= time.Duration(d.preTimeoutSec)*time.Second
+
+	var clearErr error
+	if d.config.PreTimeoutInterrupt == PreTimeoutInterruptMessaging {
+		flags, err := d.client.GetMessageFlags()
+		if err != nil {
+			return fmt.Errorf("GetMessageFlags failed, err: %s", err)
+		}
+		if flags.WatchdogPreTimeoutInterruptOccurred {
+			occurred = true
+			if _, err := d.client.ClearMessageFlagsFor(&ClearMessageFlagsRequest{ClearWatchdogPreTimeoutInterruptFlag: true}); err != nil {
+				clearErr = fmt.Errorf("ClearMessageFlags failed, err: %s", err)
+			}
+		}
+	}
+
+	if occurred {
+		d.config.OnPreTimeout(presentCountdown)
+	}
+	return clearErr
+}
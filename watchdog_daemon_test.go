@@ -0,0 +1,252 @@
+package ipmi
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeWatchdogBMC is a fake BMC watchdog timer.
+type fakeWatchdogBMC struct {
+	l sync.Mutex
+
+	set     []byte
+	started bool
+	// expiration flags replied by Get Watchdog Timer
+	expirationFlags uint8
+	// present countdown replied by Get Watchdog Timer
+	presentCountdown uint16
+	// whether the pre-timeout interrupt flag is set in Get Message Flags
+	preTimeoutFlag bool
+
+	// completion codes replied to the following Reset Watchdog Timer commands
+	resetCCs []uint8
+
+	sets    int
+	resets  int
+	clears  []byte
+	getFlag int
+}
+
+func (bmc *fakeWatchdogBMC) handle(req *testBMCRequest) (uint8, []byte) {
+	bmc.l.Lock()
+	defer bmc.l.Unlock()
+
+	switch req.Cmd {
+	case CommandGetWatchdogTimer.ID:
+		b0 := uint8(TimerUseSMSOS)
+		if bmc.started {
+			b0 = setBit6(b0)
+		}
+		return 0x00, []byte{b0, 0x01, 0x00, bmc.expirationFlags, 0x64, 0x00, uint8(bmc.presentCountdown), uint8(bmc.presentCountdown >> 8)}
+
+	case CommandSetWatchdogTimer.ID:
+		bmc.set = append([]byte{}, req.Data...)
+		bmc.sets++
+		bmc.started = false
+		bmc.expirationFlags &^= req.Data[3]
+		return 0x00, nil
+
+	case CommandResetWatchdogTimer.ID:
+		bmc.resets++
+		if len(bmc.resetCCs) > 0 {
+			cc := bmc.resetCCs[0]
+			bmc.resetCCs = bmc.resetCCs[1:]
+			if cc != 0x00 {
+				return cc, nil
+			}
+		}
+		bmc.started = true
+		return 0x00, nil
+
+	case CommandGetMessageFlags.ID:
+		bmc.getFlag++
+		var b uint8
+		if bmc.preTimeoutFlag {
+			b = setBit3(b)
+		}
+		return 0x00, []byte{b}
+
+	case CommandClearMessageFlags.ID:
+		bmc.clears = append(bmc.clears, req.Data[0])
+		if isBit3Set(req.Data[0]) {
+			bmc.preTimeoutFlag = false
+		}
+		return 0x00, nil
+	}
+	return 0xc1, nil
+}
+
+func Test_WatchdogDaemon_Arm(t *testing.T) {
+	bmc := &fakeWatchdogBMC{expirationFlags: 0x10}
+	c := newTestLANClient(t, bmc.handle)
+
+	var expired []TimerUse
+	d, err := NewWatchdogDaemon(c, WatchdogDaemonConfig{
+		TimeoutAction: TimeoutActionHardReset,
+		Timeout:       10 * time.Second,
+		OnExpired: func(timerUses []TimerUse) {
+			expired = timerUses
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewWatchdogDaemon failed, err: %s", err)
+	}
+
+	if err := d.Arm(); err != nil {
+		t.Fatalf("Arm failed, err: %s", err)
+	}
+	if len(expired) != 1 || expired[0] != TimerUseSMSOS {
+		t.Errorf("expired timer uses not matched, got: %v, expected: %v", expired, []TimerUse{TimerUseSMSOS})
+	}
+	// sms/os, hard reset, clear sms/os expiration flag, 10 sec
+	expected := []byte{0x04, 0x01, 0x00, 0x10, 0x64, 0x00}
+	if string(bmc.set) != string(expected) {
+		t.Errorf("set watchdog timer not matched, got: % x, expected: % x", bmc.set, expected)
+	}
+	if !bmc.started || bmc.expirationFlags != 0 {
+		t.Errorf("watchdog timer not armed, started: %v, expiration flags: %#02x", bmc.started, bmc.expirationFlags)
+	}
+}
+
+func Test_WatchdogDaemon_Run(t *testing.T) {
+	tests := []struct {
+		name     string
+		resetCCs []uint8
+		onError  bool
+
+		expectedErr    string
+		expectedErrors int
+		// the number of Set Watchdog Timer commands, including the one to disarm
+		expectedSets int
+	}{
+		{
+			name:         "reset",
+			expectedSets: 2,
+		},
+		{
+			name:         "rearm un-initialized",
+			resetCCs:     []uint8{0x00, 0x80},
+			expectedSets: 3,
+		},
+		{
+			name:           "reset failed reported",
+			resetCCs:       []uint8{0x00, 0xff},
+			onError:        true,
+			expectedErrors: 1,
+			expectedSets:   2,
+		},
+		{
+			name:         "reset failed returned",
+			resetCCs:     []uint8{0x00, 0xff},
+			expectedErr:  "ResetWatchdogTimer failed",
+			expectedSets: 1,
+		},
+	}
+
+	for _, test := range tests {
+		bmc := &fakeWatchdogBMC{resetCCs: test.resetCCs}
+		c := newTestLANClient(t, bmc.handle)
+
+		var errs []error
+		config := WatchdogDaemonConfig{
+			TimeoutAction: TimeoutActionHardReset,
+			Timeout:       time.Second,
+			Interval:      20 * time.Millisecond,
+		}
+		if test.onError {
+			config.OnError = func(err error) {
+				errs = append(errs, err)
+			}
+		}
+		d, err := NewWatchdogDaemon(c, config)
+		if err != nil {
+			t.Fatalf("test %s: NewWatchdogDaemon failed, err: %s", test.name, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+		err = d.Run(ctx)
+		cancel()
+
+		if test.expectedErr == "" && err != nil {
+			t.Errorf("test %s: Run failed, err: %s", test.name, err)
+		}
+		if test.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), test.expectedErr)) {
+			t.Errorf("test %s: error not matched, got: %v, expected: %s", test.name, err, test.expectedErr)
+		}
+		if len(errs) != test.expectedErrors {
+			t.Errorf("test %s: reported errors not matched, got: %v, expected: %d", test.name, errs, test.expectedErrors)
+		}
+		if bmc.sets != test.expectedSets {
+			t.Errorf("test %s: set watchdog timer not matched, got: %d, expected: %d", test.name, bmc.sets, test.expectedSets)
+		}
+		if bmc.resets < 3 && test.expectedErr == "" {
+			t.Errorf("test %s: watchdog timer not reset periodically, resets: %d", test.name, bmc.resets)
+		}
+	}
+}
+
+func Test_WatchdogDaemon_PreTimeout(t *testing.T) {
+	bmc := &fakeWatchdogBMC{preTimeoutFlag: true, presentCountdown: 100}
+	c := newTestLANClient(t, bmc.handle)
+
+	var preTimeouts []time.Duration
+	d, err := NewWatchdogDaemon(c, WatchdogDaemonConfig{
+		TimeoutAction:       TimeoutActionHardReset,
+		Timeout:             20 * time.Second,
+		PreTimeoutInterrupt: PreTimeoutInterruptMessaging,
+		PreTimeout:          5 * time.Second,
+		OnPreTimeout: func(presentCountdown time.Duration) {
+			preTimeouts = append(preTimeouts, presentCountdown)
+		},
+		// the pre-timeout is polled on its own schedule, not the one of the reset
+		PreTimeoutPollInterval: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewWatchdogDaemon failed, err: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	if err := d.Run(ctx); err != nil {
+		t.Fatalf("Run failed, err: %s", err)
+	}
+
+	if len(preTimeouts) != 1 || preTimeouts[0] != 10*time.Second {
+		t.Errorf("pre-timeouts not matched, got: %v, expected: %v", preTimeouts, []time.Duration{10 * time.Second})
+	}
+	if bmc.getFlag < 2 {
+		t.Errorf("pre-timeout not polled periodically, polls: %d", bmc.getFlag)
+	}
+	if len(bmc.clears) != 1 || bmc.clears[0] != 0x08 {
+		t.Errorf("clear message flags not matched, got: % x, expected: % x", bmc.clears, []byte{0x08})
+	}
+	// only armed, the reset interval is not reached
+	if bmc.resets != 1 {
+		t.Errorf("resets not matched, got: %d, expected: %d", bmc.resets, 1)
+	}
+}
+
+func Test_ClearMessageFlags(t *testing.T) {
+	var clears []byte
+	c := newTestLANClient(t, func(req *testBMCRequest) (uint8, []byte) {
+		if req.Cmd != CommandClearMessageFlags.ID {
+			return 0xc1, nil
+		}
+		clears = append(clears, req.Data...)
+		return 0x00, nil
+	})
+
+	if _, err := c.ClearMessageFlags(); err != nil {
+		t.Fatalf("ClearMessageFlags failed, err: %s", err)
+	}
+	if _, err := c.ClearMessageFlagsFor(&ClearMessageFlagsRequest{ClearWatchdogPreTimeoutInterruptFlag: true, ClearReceiveMessageQueue: true}); err != nil {
+		t.Fatalf("ClearMessageFlagsFor failed, err: %s", err)
+	}
+	expected := []byte{0x00, 0x09}
+	if string(clears) != string(expected) {
+		t.Errorf("clear message flags not matched, got: % x, expected: % x", clears, expected)
+	}
+}